@@ -0,0 +1,153 @@
+package animation
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+	"golang.org/x/image/math/f64"
+)
+
+// A frame of a Clip, Duration is in seconds (0 uses Clip.FrameDuration)
+type Frame struct {
+	Sprite   *sprite.Frame
+	Duration float64
+}
+
+/*
+Clip is a named list of frames played by a Player
+Mode is ANIMATION_LOOP, ANIMATION_PING_PONG or ANIMATION_ONCE
+Repeat limits LOOP and PING_PONG to a number of cycles (0 is forever)
+*/
+type Clip struct {
+	Name          string
+	Frames        []Frame
+	Mode          string
+	Speed         float64 // Multiplier of Player.Speed
+	FrameDuration float64 // Default duration (seconds) of frames without one
+	Repeat        int
+
+	events map[int][]string
+}
+
+func NewClip(name string, mode string, frameDuration float64, frames ...*sprite.Frame) *Clip {
+	c := &Clip{
+		Name:          name,
+		Mode:          mode,
+		Speed:         1,
+		FrameDuration: frameDuration,
+	}
+	for _, f := range frames {
+		c.Frames = append(c.Frames, Frame{Sprite: f})
+	}
+	return c
+}
+
+// Clip from frames of an Atlas by name, missing names are an error
+func FromAtlas(atlas *sprite.Atlas, name string, mode string, frameDuration float64, frameNames ...string) (*Clip, error) {
+	frames := make([]*sprite.Frame, 0, len(frameNames))
+	for _, n := range frameNames {
+		f := atlas.Get(n)
+		if f == nil {
+			return nil, fmt.Errorf("animation: frame %q not found in atlas", n)
+		}
+		frames = append(frames, f)
+	}
+	return NewClip(name, mode, frameDuration, frames...), nil
+}
+
+/*
+Clip from an Aseprite tag (meta.frameTags) with the exported per-frame durations
+Reverse directions are baked into the frame order, pingpong tags use ANIMATION_PING_PONG
+*/
+func FromTag(atlas *sprite.Atlas, tag string) (*Clip, error) {
+	for _, t := range atlas.Tags {
+		if t.Name != tag {
+			continue
+		}
+		c := NewClip(t.Name, ANIMATION_LOOP, 0.1)
+		c.Repeat = t.Repeat
+		for i := t.From; i <= t.To; i++ {
+			f := atlas.GetByIndex(i)
+			if f == nil {
+				return nil, fmt.Errorf("animation: tag %q frame %d out of range", tag, i)
+			}
+			c.Frames = append(c.Frames, Frame{Sprite: f, Duration: f.Duration / 1000})
+		}
+		switch t.Direction {
+		case "reverse":
+			c.reverse()
+		case "pingpong":
+			c.Mode = ANIMATION_PING_PONG
+		case "pingpong_reverse":
+			c.reverse()
+			c.Mode = ANIMATION_PING_PONG
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("animation: tag %q not found in atlas", tag)
+}
+
+// Clip from plain images (each one becomes a frame with its pivot at pivotX, pivotY normalized)
+func FromImages(name string, mode string, frameDuration float64, pivotX, pivotY float64, images ...*ebiten.Image) *Clip {
+	frames := make([]*sprite.Frame, 0, len(images))
+	for i, img := range images {
+		b := img.Bounds()
+		frames = append(frames, &sprite.Frame{
+			Name:       fmt.Sprintf("%s%d", name, i),
+			Image:      img,
+			Rect:       b,
+			SourceSize: f64.Vec2{float64(b.Dx()), float64(b.Dy())},
+			Pivot:      f64.Vec2{pivotX, pivotY},
+		})
+	}
+	return NewClip(name, mode, frameDuration, frames...)
+}
+
+func (c *Clip) reverse() {
+	for i, j := 0, len(c.Frames)-1; i < j; i, j = i+1, j-1 {
+		c.Frames[i], c.Frames[j] = c.Frames[j], c.Frames[i]
+	}
+}
+
+// Sets the duration (seconds) of every frame
+func (c *Clip) SetDuration(seconds float64) {
+	for i := range c.Frames {
+		c.Frames[i].Duration = seconds
+	}
+}
+
+// Fires event on Player.OnEvent whenever frame is entered (eg: "footstep" or "hit")
+func (c *Clip) AddEvent(frame int, event string) {
+	if c.events == nil {
+		c.events = map[int][]string{}
+	}
+	c.events[frame] = append(c.events[frame], event)
+}
+
+func (c *Clip) GetEvents(frame int) []string {
+	return c.events[frame]
+}
+
+func (c *Clip) getDuration(i int) float64 {
+	if d := c.Frames[i].Duration; d > 0 {
+		return d
+	}
+	if c.FrameDuration > 0 {
+		return c.FrameDuration
+	}
+	return 0.1
+}
+
+// Length of one cycle in seconds (there and back for PING_PONG)
+func (c *Clip) GetLength() float64 {
+	total := 0.0
+	for i := range c.Frames {
+		total += c.getDuration(i)
+	}
+	if c.Mode == ANIMATION_PING_PONG && len(c.Frames) > 2 {
+		total += total - c.getDuration(0) - c.getDuration(len(c.Frames)-1)
+	}
+	return total
+}
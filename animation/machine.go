@@ -0,0 +1,103 @@
+package animation
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+)
+
+// Transition From "*" applies to every state
+const AnyState = "*"
+
+/*
+Transition switches the StateMachine From a state To another when Condition returns true
+AtEnd waits for the current Clip to finish (or complete a cycle if it loops) before checking Condition
+*/
+type Transition struct {
+	From      string
+	To        string
+	Condition func() bool // nil is always true
+	AtEnd     bool
+}
+
+/*
+StateMachine picks which Clip its Player plays (eg: "idle", "run", "jump")
+Transitions are checked in the order they were added before every Update, the first match wins
+*/
+type StateMachine struct {
+	Player      *Player
+	States      map[string]*Clip
+	Transitions []*Transition
+	Current     string
+	OnChange    func(from, to string)
+}
+
+func NewStateMachine() *StateMachine {
+	return &StateMachine{
+		Player: NewPlayer(nil),
+		States: map[string]*Clip{},
+	}
+}
+
+// Adds a state, the first one added becomes Current
+func (m *StateMachine) AddState(name string, clip *Clip) {
+	m.States[name] = clip
+	if m.Current == "" {
+		m.Current = name
+		m.Player.Play(clip)
+	}
+}
+
+func (m *StateMachine) AddTransition(from, to string, condition func() bool) *Transition {
+	t := &Transition{From: from, To: to, Condition: condition}
+	m.Transitions = append(m.Transitions, t)
+	return t
+}
+
+// Same as AddTransition but only once the current Clip finished or completed a cycle
+func (m *StateMachine) AddTransitionAtEnd(from, to string, condition func() bool) *Transition {
+	t := m.AddTransition(from, to, condition)
+	t.AtEnd = true
+	return t
+}
+
+// Switches to a state right away, its Clip restarts even if it's the current state
+func (m *StateMachine) SetState(name string) error {
+	clip, ok := m.States[name]
+	if !ok {
+		return fmt.Errorf("animation: unknown state %q", name)
+	}
+	from := m.Current
+	m.Current = name
+	m.Player.Clip = clip
+	m.Player.Restart()
+	if m.OnChange != nil && from != name {
+		m.OnChange(from, name)
+	}
+	return nil
+}
+
+// Checks Transitions then advances the Player by one tick (1/ebiten.TPS())
+func (m *StateMachine) Update() error {
+	atEnd := m.Player.Finished || m.Player.looped
+	for _, t := range m.Transitions {
+		if t.From != m.Current && t.From != AnyState {
+			continue
+		}
+		if t.To == m.Current || (t.AtEnd && !atEnd) {
+			continue
+		}
+		if t.Condition == nil || t.Condition() {
+			if err := m.SetState(t.To); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return m.Player.Update()
+}
+
+func (m *StateMachine) Draw(screen scr.Screen, x, y float64, op *ebiten.DrawImageOptions) {
+	m.Player.Draw(screen, x, y, op)
+}
@@ -0,0 +1,196 @@
+package animation
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+)
+
+/*
+Player plays a Clip, Update advances it by one tick (1/ebiten.TPS()) scaled by Speed and Clip.Speed
+OnEvent is called for every event of a frame when it's entered, OnFinish when a ONCE clip
+(or a LOOP/PING_PONG clip with Repeat) ends
+*/
+type Player struct {
+	Clip     *Clip
+	Speed    float64
+	Playing  bool
+	Finished bool
+	OnEvent  func(event string, frame int)
+	OnFinish func(clip *Clip)
+
+	frame     int
+	direction int // 1 or -1 (PING_PONG going back)
+	elapsed   float64
+	loops     int
+	looped    bool // A cycle completed during the last Advance
+}
+
+func NewPlayer(clip *Clip) *Player {
+	p := &Player{Speed: 1}
+	if clip != nil {
+		p.Play(clip)
+	}
+	return p
+}
+
+// Plays clip from its first frame, does nothing if it's already playing
+func (p *Player) Play(clip *Clip) {
+	if p.Clip == clip && p.Playing {
+		return
+	}
+	p.Clip = clip
+	p.Restart()
+}
+
+// Plays the current Clip from its first frame
+func (p *Player) Restart() {
+	p.Playing = p.Clip != nil && len(p.Clip.Frames) > 0
+	p.Finished = false
+	p.direction = 1
+	p.elapsed = 0
+	p.loops = 0
+	p.looped = false
+	p.setFrame(0)
+}
+
+func (p *Player) Pause() {
+	p.Playing = false
+}
+
+// Continues a paused Player, a finished one stays finished (use Restart)
+func (p *Player) Resume() {
+	if !p.Finished && p.Clip != nil && len(p.Clip.Frames) > 0 {
+		p.Playing = true
+	}
+}
+
+// Jumps to a frame (events of that frame are fired)
+func (p *Player) SetFrame(frame int) {
+	if p.Clip == nil || frame < 0 || frame >= len(p.Clip.Frames) {
+		return
+	}
+	p.elapsed = 0
+	p.setFrame(frame)
+}
+
+func (p *Player) setFrame(frame int) {
+	p.frame = frame
+	if p.Clip == nil || p.OnEvent == nil {
+		return
+	}
+	for _, event := range p.Clip.GetEvents(frame) {
+		p.OnEvent(event, frame)
+	}
+}
+
+func (p *Player) GetFrameIndex() int {
+	return p.frame
+}
+
+// Current sprite frame, nil if there is no Clip
+func (p *Player) GetFrame() *sprite.Frame {
+	if p.Clip == nil || p.frame >= len(p.Clip.Frames) {
+		return nil
+	}
+	return p.Clip.Frames[p.frame].Sprite
+}
+
+// Image of the current frame (eg: for ecs.Sprite), nil if there is no Clip
+func (p *Player) GetImage() *ebiten.Image {
+	if f := p.GetFrame(); f != nil {
+		return f.Image
+	}
+	return nil
+}
+
+// Completed cycles since the Clip started
+func (p *Player) GetLoops() int {
+	return p.loops
+}
+
+// Advances by one tick (1/ebiten.TPS())
+func (p *Player) Update() error {
+	p.Advance(1 / float64(ebiten.TPS()))
+	return nil
+}
+
+// Advances by dt seconds (scaled by Speed and Clip.Speed), several frames may be skipped
+func (p *Player) Advance(dt float64) {
+	p.looped = false
+	if !p.Playing || p.Clip == nil || len(p.Clip.Frames) == 0 {
+		return
+	}
+	p.elapsed += dt * p.Speed * p.Clip.Speed
+	for p.Playing && p.elapsed >= p.Clip.getDuration(p.frame) {
+		p.elapsed -= p.Clip.getDuration(p.frame)
+		p.step()
+	}
+}
+
+func (p *Player) step() {
+	n := len(p.Clip.Frames)
+	next := p.frame + p.direction
+
+	switch p.Clip.Mode {
+	case ANIMATION_ONCE:
+		if next >= n {
+			p.finish()
+			return
+		}
+	case ANIMATION_PING_PONG:
+		if next >= n && n > 1 {
+			p.direction = -1
+			next = n - 2
+		} else if next < 0 || next >= n {
+			if p.cycle() {
+				return
+			}
+			p.direction = 1
+			next = minInt(1, n-1)
+		}
+	default: // ANIMATION_LOOP
+		if next >= n {
+			if p.cycle() {
+				return
+			}
+			next = 0
+		}
+	}
+	p.setFrame(next)
+}
+
+// Counts a completed cycle, true if Repeat is reached and the Clip finished
+func (p *Player) cycle() bool {
+	p.loops++
+	p.looped = true
+	if p.Clip.Repeat > 0 && p.loops >= p.Clip.Repeat {
+		p.finish()
+		return true
+	}
+	return false
+}
+
+func (p *Player) finish() {
+	p.Playing = false
+	p.Finished = true
+	p.elapsed = 0
+	if p.OnFinish != nil {
+		p.OnFinish(p.Clip)
+	}
+}
+
+// Draws the current frame with its pivot at World x, y through Screen.DrawImage
+func (p *Player) Draw(screen scr.Screen, x, y float64, op *ebiten.DrawImageOptions) {
+	if f := p.GetFrame(); f != nil {
+		sprite.DrawFrame(screen, f, x, y, op)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
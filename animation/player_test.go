@@ -0,0 +1,236 @@
+package animation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+)
+
+// Clip of n empty frames lasting a second each
+func testClip(name, mode string, n int) *Clip {
+	frames := make([]*sprite.Frame, n)
+	for i := range frames {
+		frames[i] = &sprite.Frame{}
+	}
+	return NewClip(name, mode, 1, frames...)
+}
+
+// Frame index after each of steps one second Advances
+func playFrames(p *Player, steps int) []int {
+	frames := make([]int, 0, steps)
+	for i := 0; i < steps; i++ {
+		p.Advance(1)
+		frames = append(frames, p.GetFrameIndex())
+	}
+	return frames
+}
+
+func TestPlayerModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		frames   int
+		repeat   int
+		steps    int
+		want     []int
+		finished bool
+		loops    int
+	}{
+		{"loop", ANIMATION_LOOP, 3, 0, 7, []int{1, 2, 0, 1, 2, 0, 1}, false, 2},
+		{"loop repeat", ANIMATION_LOOP, 3, 2, 7, []int{1, 2, 0, 1, 2, 2, 2}, true, 2},
+		{"once", ANIMATION_ONCE, 3, 0, 4, []int{1, 2, 2, 2}, true, 0},
+		{"once single frame", ANIMATION_ONCE, 1, 0, 2, []int{0, 0}, true, 0},
+		{"ping-pong", ANIMATION_PING_PONG, 3, 0, 8, []int{1, 2, 1, 0, 1, 2, 1, 0}, false, 1},
+		{"ping-pong two frames", ANIMATION_PING_PONG, 2, 0, 5, []int{1, 0, 1, 0, 1}, false, 2},
+		{"ping-pong repeat", ANIMATION_PING_PONG, 3, 1, 6, []int{1, 2, 1, 0, 0, 0}, true, 1},
+		// A single frame turns around on itself, every frame duration is a cycle
+		{"ping-pong one frame", ANIMATION_PING_PONG, 1, 0, 3, []int{0, 0, 0}, false, 3},
+		{"ping-pong one frame repeat", ANIMATION_PING_PONG, 1, 2, 3, []int{0, 0, 0}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := testClip(tt.name, tt.mode, tt.frames)
+			clip.Repeat = tt.repeat
+			p := NewPlayer(clip)
+			finishes := 0
+			p.OnFinish = func(c *Clip) {
+				if c != clip {
+					t.Errorf("OnFinish(%q), want %q", c.Name, clip.Name)
+				}
+				finishes++
+			}
+
+			if got := playFrames(p, tt.steps); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("frames %v, want %v", got, tt.want)
+			}
+			if p.Finished != tt.finished || p.Playing == tt.finished {
+				t.Fatalf("Finished %v Playing %v, want finished %v", p.Finished, p.Playing, tt.finished)
+			}
+			if (finishes == 1) != tt.finished || finishes > 1 {
+				t.Fatalf("OnFinish called %d times", finishes)
+			}
+			if p.GetLoops() != tt.loops {
+				t.Fatalf("GetLoops() = %d, want %d", p.GetLoops(), tt.loops)
+			}
+		})
+	}
+}
+
+func TestPlayerAdvanceSkipsFrames(t *testing.T) {
+	clip := testClip("walk", ANIMATION_LOOP, 4)
+	clip.Frames[1].Duration = 0.5
+	p := NewPlayer(clip)
+
+	// 1 (frame 0) + 0.5 (frame 1) + 1 (frame 2), 0.25 left on frame 3
+	p.Advance(2.75)
+	if p.GetFrameIndex() != 3 {
+		t.Fatalf("frame %d, want 3", p.GetFrameIndex())
+	}
+	p.Advance(0.75)
+	if p.GetFrameIndex() != 0 || p.GetLoops() != 1 {
+		t.Fatalf("frame %d loops %d, want 0 and 1", p.GetFrameIndex(), p.GetLoops())
+	}
+
+	// Speed and Clip.Speed both scale time
+	p.Speed, clip.Speed = 2, 0.5
+	p.Advance(1)
+	if p.GetFrameIndex() != 1 {
+		t.Fatalf("frame %d with speeds cancelling out, want 1", p.GetFrameIndex())
+	}
+	p.Speed = 4
+	p.Advance(0.75) // 1.5 seconds of clip time
+	if p.GetFrameIndex() != 3 {
+		t.Fatalf("frame %d at double speed, want 3", p.GetFrameIndex())
+	}
+
+	if clip.GetLength() != 3.5 {
+		t.Fatalf("GetLength() = %v, want 3.5", clip.GetLength())
+	}
+	clip.Mode = ANIMATION_PING_PONG
+	if clip.GetLength() != 5 {
+		t.Fatalf("ping-pong GetLength() = %v, want 5 (ends aren't played twice)", clip.GetLength())
+	}
+}
+
+func TestPlayerEvents(t *testing.T) {
+	type fired struct {
+		event string
+		frame int
+	}
+	clip := testClip("attack", ANIMATION_LOOP, 3)
+	clip.AddEvent(0, "start")
+	clip.AddEvent(1, "swing")
+	clip.AddEvent(1, "sound")
+	clip.AddEvent(2, "hit")
+
+	var got []fired
+	p := NewPlayer(nil)
+	p.OnEvent = func(event string, frame int) {
+		got = append(got, fired{event, frame})
+	}
+
+	p.Play(clip)
+	want := []fired{{"start", 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events on Play %v, want %v", got, want)
+	}
+
+	// Skipped frames still fire, looping back fires frame 0 again
+	got = nil
+	p.Advance(3)
+	want = []fired{{"swing", 1}, {"sound", 1}, {"hit", 2}, {"start", 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events %v, want %v", got, want)
+	}
+
+	// Playing the same clip doesn't restart it
+	got = nil
+	p.Play(clip)
+	if len(got) != 0 {
+		t.Fatalf("Play() of the playing clip fired %v", got)
+	}
+
+	got = nil
+	p.SetFrame(2)
+	p.Pause()
+	p.Advance(5)
+	want = []fired{{"hit", 2}}
+	if !reflect.DeepEqual(got, want) || p.GetFrameIndex() != 2 {
+		t.Fatalf("events %v at frame %d, want %v at 2 (paused)", got, p.GetFrameIndex(), want)
+	}
+}
+
+func TestStateMachineAtEnd(t *testing.T) {
+	// One frame per Update
+	tick := 1 / float64(ebiten.TPS())
+	attack := testClip("attack", ANIMATION_ONCE, 3)
+	run := testClip("run", ANIMATION_LOOP, 2)
+	idle := testClip("idle", ANIMATION_LOOP, 2)
+	for _, c := range []*Clip{attack, run, idle} {
+		c.FrameDuration = tick
+	}
+
+	m := NewStateMachine()
+	m.AddState("idle", idle)
+	m.AddState("attack", attack)
+	m.AddState("run", run)
+	stop := false
+	m.AddTransitionAtEnd("attack", "idle", nil)
+	m.AddTransitionAtEnd("run", "idle", func() bool { return stop })
+	var changes []string
+	m.OnChange = func(from, to string) {
+		changes = append(changes, from+">"+to)
+	}
+
+	update := func() {
+		t.Helper()
+		if err := m.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ONCE waits until the clip finished
+	if err := m.SetState("attack"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		update()
+		if m.Current != "attack" {
+			t.Fatalf("left attack after %d updates, before it finished", i+1)
+		}
+	}
+	if !m.Player.Finished {
+		t.Fatal("attack didn't finish after 3 frames")
+	}
+	update()
+	if m.Current != "idle" || m.Player.Clip != idle {
+		t.Fatalf("state %q after attack finished, want idle", m.Current)
+	}
+
+	// LOOP waits for the end of a cycle even once Condition is true
+	if err := m.SetState("run"); err != nil {
+		t.Fatal(err)
+	}
+	update()
+	stop = true
+	update()
+	if m.Current != "run" {
+		t.Fatal("left run in the middle of a cycle")
+	}
+	update()
+	if m.Current != "idle" {
+		t.Fatalf("state %q after run completed a cycle, want idle", m.Current)
+	}
+
+	want := []string{"idle>attack", "attack>idle", "idle>run", "run>idle"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("OnChange %v, want %v", changes, want)
+	}
+
+	if err := m.SetState("jump"); err == nil {
+		t.Fatal("SetState() of an unknown state didn't fail")
+	}
+}
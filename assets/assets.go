@@ -0,0 +1,311 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+	"github.com/shubhamdwivedii/gopher-engine/tiled"
+	"golang.org/x/image/font/opentype"
+)
+
+/*
+Loader loads one kind of asset from a file system, Unload (optional) frees it (eg: Dispose an image)
+Swap (optional) is used by hot reloading to move a reloaded value into the live one, it returns
+the value to keep (old if it was updated in place), without it the new value replaces the old one
+Custom kinds can be added with Manager.RegisterLoader
+*/
+type Loader struct {
+	Load   func(fsys fs.FS, name string) (interface{}, error)
+	Unload func(value interface{})
+	Swap   func(old, new interface{}) interface{}
+}
+
+type entry struct {
+	kind  string
+	name  string
+	value interface{}
+	refs  int
+
+	files    []string             // Every file opened while loading (eg: an atlas and its texture)
+	modTimes map[string]time.Time // Of files, for hot reloading
+	targets  []interface{}        // Values LoadJSON decoded into, refreshed on reload
+	replaced []interface{}        // Old values a reload couldn't update in place, unloaded with the entry
+}
+
+/*
+Manager loads assets (images, fonts, sounds, data, atlases, maps) from a file system (a directory or embed.FS)
+Every asset is loaded once per (kind, name), each Load adds a reference and each Release drops one,
+the asset is unloaded when nothing references it anymore
+Names use forward slashes and are relative to the root of the file system
+*/
+type Manager struct {
+	FS fs.FS
+
+	mutex   sync.Mutex
+	entries map[string]*entry
+	loaders map[string]Loader
+	watcher *watcher
+}
+
+func New(fsys fs.FS) *Manager {
+	m := &Manager{
+		FS:      fsys,
+		entries: map[string]*entry{},
+		loaders: map[string]Loader{},
+	}
+	m.loaders[ASSET_IMAGE] = Loader{Load: loadImage, Unload: func(v interface{}) { v.(*ebiten.Image).Dispose() }, Swap: swapImage}
+	m.loaders[ASSET_FONT] = Loader{Load: loadFont, Swap: swapFont}
+	m.loaders[ASSET_SOUND] = Loader{Load: loadSound, Swap: swapSound}
+	m.loaders[ASSET_DATA] = Loader{Load: loadData}
+	m.loaders[ASSET_ATLAS] = Loader{Load: loadAtlas, Unload: func(v interface{}) { v.(*sprite.Atlas).Image.Dispose() }, Swap: swapAtlas}
+	m.loaders[ASSET_MAP] = Loader{Load: loadMap, Unload: unloadMap, Swap: swapMap}
+	return m
+}
+
+// Manager for a directory on disk (eg: "./examples/assets")
+func NewFromDir(dir string) *Manager {
+	return New(os.DirFS(dir))
+}
+
+// Adds (or replaces) the Loader of a kind of asset
+func (m *Manager) RegisterLoader(kind string, loader Loader) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.loaders[kind] = loader
+}
+
+func key(kind, name string) string {
+	return kind + ":" + name
+}
+
+func clean(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+/*
+Loads an asset (or adds a reference to an already loaded one)
+The lock isn't held while the Loader runs, so a Loader can load its dependencies through the same Manager
+*/
+func (m *Manager) Load(kind, name string) (interface{}, error) {
+	name = clean(name)
+	k := key(kind, name)
+	m.mutex.Lock()
+	if e, ok := m.entries[k]; ok {
+		e.refs++
+		m.mutex.Unlock()
+		return e.value, nil
+	}
+	loader, ok := m.loaders[kind]
+	m.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("assets: no loader for %s assets", kind)
+	}
+
+	value, files, err := m.load(loader, name)
+	if err != nil {
+		return nil, fmt.Errorf("assets: loading %s %q: %w", kind, name, err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// Loaded by someone else in the meantime, keep theirs
+	if e, ok := m.entries[k]; ok {
+		e.refs++
+		if loader.Unload != nil {
+			loader.Unload(value)
+		}
+		return e.value, nil
+	}
+	e := &entry{kind: kind, name: name, value: value, refs: 1, files: files}
+	if m.watcher != nil {
+		e.modTimes = m.stat(files)
+	}
+	m.entries[k] = e
+	return value, nil
+}
+
+// Loads through a recordingFS so every file the asset depends on is known
+func (m *Manager) load(loader Loader, name string) (interface{}, []string, error) {
+	rec := &recordingFS{fsys: m.FS}
+	value, err := loader.Load(rec, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, rec.opened, nil
+}
+
+// An already loaded asset (no reference is added)
+func (m *Manager) Get(kind, name string) (interface{}, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e, ok := m.entries[key(kind, clean(name))]
+	if !ok {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Number of references to an asset, 0 if it isn't loaded
+func (m *Manager) GetRefs(kind, name string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if e, ok := m.entries[key(kind, clean(name))]; ok {
+		return e.refs
+	}
+	return 0
+}
+
+// Drops a reference, the asset is unloaded when it was the last one
+func (m *Manager) Release(kind, name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	k := key(kind, clean(name))
+	e, ok := m.entries[k]
+	if !ok {
+		return
+	}
+	e.refs--
+	if e.refs <= 0 {
+		m.unload(k, e)
+	}
+}
+
+// Unloads an asset regardless of its references
+func (m *Manager) Unload(kind, name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	k := key(kind, clean(name))
+	if e, ok := m.entries[k]; ok {
+		m.unload(k, e)
+	}
+}
+
+// Unloads every asset (eg: when switching levels)
+func (m *Manager) UnloadAll() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for k, e := range m.entries {
+		m.unload(k, e)
+	}
+}
+
+func (m *Manager) unload(k string, e *entry) {
+	delete(m.entries, k)
+	if loader, ok := m.loaders[e.kind]; ok && loader.Unload != nil {
+		loader.Unload(e.value)
+		for _, old := range e.replaced {
+			loader.Unload(old)
+		}
+	}
+}
+
+// Keys ("KIND:name") of loaded assets, sorted
+func (m *Manager) GetLoaded() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	keys := make([]string, 0, len(m.entries))
+	for k := range m.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/***************** TYPED LOADERS *********************/
+
+func (m *Manager) LoadImage(name string) (*ebiten.Image, error) {
+	v, err := m.Load(ASSET_IMAGE, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ebiten.Image), nil
+}
+
+// TTF/OTF font, create faces with opentype.NewFace (or register the data in a fonts.Registry)
+func (m *Manager) LoadFont(name string) (*opentype.Font, error) {
+	v, err := m.Load(ASSET_FONT, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*opentype.Font), nil
+}
+
+// Encoded sound, see Sound
+func (m *Manager) LoadSound(name string) (*Sound, error) {
+	v, err := m.Load(ASSET_SOUND, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Sound), nil
+}
+
+// Raw file contents (configs, levels, shaders...)
+func (m *Manager) LoadData(name string) ([]byte, error) {
+	v, err := m.Load(ASSET_DATA, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// Loads a data asset and unmarshals it as JSON into v (v should be a pointer, it's updated on hot reload)
+func (m *Manager) LoadJSON(name string, v interface{}) error {
+	data, err := m.LoadData(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		m.Release(ASSET_DATA, name)
+		return fmt.Errorf("assets: decoding %q: %w", name, err)
+	}
+	m.addTarget(name, v)
+	return nil
+}
+
+// Remembers v so hot reloading decodes the new data into it
+func (m *Manager) addTarget(name string, v interface{}) {
+	if reflect.ValueOf(v).Kind() != reflect.Ptr {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e, ok := m.entries[key(ASSET_DATA, clean(name))]
+	if !ok {
+		return
+	}
+	for _, target := range e.targets {
+		if target == v {
+			return
+		}
+	}
+	e.targets = append(e.targets, v)
+}
+
+// Sprite atlas descriptor (see sprite.LoadFS), its texture is loaded with it
+func (m *Manager) LoadAtlas(name string) (*sprite.Atlas, error) {
+	v, err := m.Load(ASSET_ATLAS, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*sprite.Atlas), nil
+}
+
+// Tiled map (see tiled.LoadFS)
+func (m *Manager) LoadMap(name string) (*tiled.Map, error) {
+	v, err := m.Load(ASSET_MAP, name)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*tiled.Map), nil
+}
@@ -0,0 +1,288 @@
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/tiled"
+)
+
+// Fails instead of hanging when f deadlocks
+func within(t *testing.T, f func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlocked")
+	}
+}
+
+// A level lists the data files it needs, which are loaded through the same Manager
+func levelLoader(m *Manager) Loader {
+	return Loader{Load: func(fsys fs.FS, name string) (interface{}, error) {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var parts []string
+		for _, dep := range strings.Fields(string(data)) {
+			d, err := m.LoadData(dep)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, string(d))
+		}
+		return strings.Join(parts, "+"), nil
+	}}
+}
+
+func TestLoaderLoadsDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"level.txt": {Data: []byte("a.txt b.txt"), ModTime: time.Unix(1, 0)},
+		"a.txt":     {Data: []byte("a")},
+		"b.txt":     {Data: []byte("b")},
+	}
+	m := New(fsys)
+	m.RegisterLoader("LEVEL", levelLoader(m))
+
+	within(t, func() {
+		v, err := m.Load("LEVEL", "level.txt")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if v != "a+b" {
+			t.Errorf("level = %v, want a+b", v)
+		}
+	})
+	if m.GetRefs(ASSET_DATA, "a.txt") != 1 || m.GetRefs("LEVEL", "level.txt") != 1 {
+		t.Fatalf("loaded %v", m.GetLoaded())
+	}
+
+	// Reloading doesn't hold the lock either
+	var got interface{}
+	m.EnableHotReload(1, func(kind, name string, value interface{}, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		if kind == "LEVEL" {
+			got = value
+		}
+	})
+	fsys["level.txt"] = &fstest.MapFile{Data: []byte("b.txt"), ModTime: time.Unix(2, 0)}
+	within(t, m.Poll)
+	if got != "b" {
+		t.Fatalf("reloaded level = %v, want b", got)
+	}
+	if v, _ := m.Get("LEVEL", "level.txt"); v != "b" {
+		t.Fatalf("live level = %v, want b", v)
+	}
+}
+
+func TestConcurrentLoadKeepsOneValue(t *testing.T) {
+	m := New(fstest.MapFS{"slow.txt": {Data: []byte("slow")}})
+	var started sync.WaitGroup
+	started.Add(2)
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	unloads := 0
+	m.RegisterLoader("SLOW", Loader{
+		Load: func(fsys fs.FS, name string) (interface{}, error) {
+			started.Done()
+			<-release
+			return new(int), nil
+		},
+		Unload: func(v interface{}) {
+			mutex.Lock()
+			unloads++
+			mutex.Unlock()
+		},
+	})
+
+	values := make([]interface{}, 2)
+	var loaded sync.WaitGroup
+	for i := range values {
+		loaded.Add(1)
+		go func(i int) {
+			defer loaded.Done()
+			values[i], _ = m.Load("SLOW", "slow.txt")
+		}(i)
+	}
+	// Both are inside the Loader at once, which only works without the lock
+	within(t, started.Wait)
+	close(release)
+	within(t, loaded.Wait)
+
+	if values[0] == nil || values[0] != values[1] {
+		t.Fatalf("Load() returned %v and %v, want the same value", values[0], values[1])
+	}
+	if unloads != 1 || m.GetRefs("SLOW", "slow.txt") != 2 {
+		t.Fatalf("%d unloads and %d refs, want the duplicate unloaded and 2 refs", unloads, m.GetRefs("SLOW", "slow.txt"))
+	}
+}
+
+func TestReplacedValuesUnloadWithTheAsset(t *testing.T) {
+	fsys := fstest.MapFS{"box.txt": {Data: []byte("1"), ModTime: time.Unix(1, 0)}}
+	m := New(fsys)
+	var unloaded []string
+	m.RegisterLoader("BOX", Loader{
+		Load: func(fsys fs.FS, name string) (interface{}, error) {
+			data, err := fs.ReadFile(fsys, name)
+			s := string(data)
+			return &s, err
+		},
+		Unload: func(v interface{}) { unloaded = append(unloaded, *v.(*string)) },
+		// Like an image of a different size, the new value replaces the old one
+		Swap: func(old, new interface{}) interface{} { return new },
+	})
+
+	old, err := m.Load("BOX", "box.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.EnableHotReload(1, nil)
+	fsys["box.txt"] = &fstest.MapFile{Data: []byte("2"), ModTime: time.Unix(2, 0)}
+	m.Poll()
+	if v, _ := m.Get("BOX", "box.txt"); v == old || *v.(*string) != "2" {
+		t.Fatalf("live value %v, want the reloaded one", v)
+	}
+	if len(unloaded) != 0 {
+		t.Fatalf("unloaded %v while the old value may still be held", unloaded)
+	}
+
+	m.Release("BOX", "box.txt")
+	if strings.Join(unloaded, ",") != "2,1" {
+		t.Fatalf("unloaded %v, want the live and the replaced value", unloaded)
+	}
+}
+
+func TestLoadJSONReleasesOnError(t *testing.T) {
+	m := New(fstest.MapFS{
+		"good.json": {Data: []byte(`{"speed": 3}`)},
+		"bad.json":  {Data: []byte(`{"speed": "fast"}`)},
+	})
+	var config struct{ Speed int }
+	if err := m.LoadJSON("bad.json", &config); err == nil {
+		t.Fatal("LoadJSON() of a mismatched type didn't fail")
+	}
+	if refs := m.GetRefs(ASSET_DATA, "bad.json"); refs != 0 {
+		t.Fatalf("%d refs after a failed LoadJSON, want 0", refs)
+	}
+	if err := m.LoadJSON("good.json", &config); err != nil || config.Speed != 3 {
+		t.Fatalf("LoadJSON() = %v, speed %d", err, config.Speed)
+	}
+	if refs := m.GetRefs(ASSET_DATA, "good.json"); refs != 1 {
+		t.Fatalf("%d refs, want 1", refs)
+	}
+}
+
+func TestMapImages(t *testing.T) {
+	sheet, shared, background := ebiten.NewImage(32, 32), ebiten.NewImage(8, 8), ebiten.NewImage(64, 64)
+	m := &tiled.Map{
+		Tilesets: []*tiled.Tileset{
+			{Image: sheet},
+			{Tiles: map[uint32]*tiled.TileInfo{0: {Image: shared}, 1: {Image: shared}, 2: {}}},
+		},
+		Layers: []*tiled.Layer{
+			{Type: TILED_LAYER_GROUP, Layers: []*tiled.Layer{{Type: TILED_LAYER_IMAGE, Image: background}}},
+			{Type: TILED_LAYER_TILES},
+		},
+	}
+	images := mapImages(m)
+	if len(images) != 3 || images[0] != sheet || images[1] != shared || images[2] != background {
+		t.Fatalf("mapImages() = %v, want the sheet, the shared tile image and the image layer once", images)
+	}
+}
+
+func TestReplacedOnlyKeptWithUnload(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("1"), ModTime: time.Unix(1, 0)}}
+	m := New(fsys)
+	if _, err := m.LoadData("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	m.EnableHotReload(1, nil)
+	for i := 2; i < 5; i++ {
+		fsys["a.txt"] = &fstest.MapFile{Data: []byte(fmt.Sprint(i)), ModTime: time.Unix(int64(i), 0)}
+		m.Poll()
+	}
+	if v, _ := m.Get(ASSET_DATA, "a.txt"); string(v.([]byte)) != "4" {
+		t.Fatalf("live data %q, want 4", v)
+	}
+	if replaced := m.entries[key(ASSET_DATA, "a.txt")].replaced; len(replaced) != 0 {
+		t.Fatalf("kept %d replaced values of a kind without Unload", len(replaced))
+	}
+}
+
+// Group "props" holds tile layer "ground", cells use both tilesets
+func testMap(groundData, extraLayer string) []byte {
+	return []byte(`{
+ "orientation": "orthogonal", "width": 2, "height": 1, "tilewidth": 16, "tileheight": 16,
+ "tilesets": [
+  {"firstgid": 1, "name": "a", "tilewidth": 16, "tileheight": 16, "tilecount": 4, "columns": 2},
+  {"firstgid": 5, "name": "b", "tilewidth": 16, "tileheight": 16, "tilecount": 4, "columns": 2}
+ ],
+ "layers": [` + extraLayer + `
+  {"id": 1, "name": "props", "type": "group", "visible": true, "opacity": 1, "layers": [
+   {"id": 2, "name": "ground", "type": "tilelayer", "width": 2, "height": 1, "visible": true, "opacity": 1, "data": ` + groundData + `}
+  ]}
+ ]
+}`)
+}
+
+func TestMapReloadUpdatesInPlace(t *testing.T) {
+	fsys := fstest.MapFS{"level.tmj": {Data: testMap("[1, 5]", ""), ModTime: time.Unix(1, 0)}}
+	m := New(fsys)
+	level, err := m.LoadMap("level.tmj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, ground := level.GetLayer("props"), level.GetLayer("ground")
+	source := level.GetLayerSource(ground)
+	tilesetA, tilesetB := level.Tilesets[0], level.Tilesets[1]
+
+	reloads := 0
+	m.EnableHotReload(1, func(kind, name string, value interface{}, err error) {
+		if err != nil || value != level {
+			t.Errorf("OnReload(%v, %v), want the live map", value, err)
+		}
+		reloads++
+	})
+	extra := `{"id": 3, "name": "sky", "type": "imagelayer", "visible": true, "opacity": 1},`
+	fsys["level.tmj"] = &fstest.MapFile{Data: testMap("[6, 2]", extra), ModTime: time.Unix(2, 0)}
+	m.Poll()
+	if reloads != 1 {
+		t.Fatalf("%d reloads, want 1", reloads)
+	}
+
+	if level.GetLayer("props") != props || level.GetLayer("ground") != ground || source.Layer != ground {
+		t.Fatal("layers were replaced instead of updated")
+	}
+	if len(level.Layers) != 2 || level.GetLayer("sky") == nil {
+		t.Fatalf("%d layers, want the new one added", len(level.Layers))
+	}
+	if ground.Parent != props || props.Layers[0] != ground {
+		t.Fatal("group and child don't point at each other")
+	}
+	if level.Tilesets[0] != tilesetA || level.Tilesets[1] != tilesetB {
+		t.Fatal("tilesets were replaced instead of updated")
+	}
+	first, second := ground.GetCell(0, 0), ground.GetCell(1, 0)
+	if first.GID != 6 || first.Tileset != tilesetB || second.GID != 2 || second.Tileset != tilesetA {
+		t.Fatalf("cells %+v %+v, want the new GIDs in the live tilesets", first, second)
+	}
+	if replaced := m.entries[key(ASSET_MAP, "level.tmj")].replaced; len(replaced) != 0 {
+		t.Fatalf("%d replaced maps, want it updated in place", len(replaced))
+	}
+}
@@ -0,0 +1,100 @@
+package assets
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+	"github.com/shubhamdwivedii/gopher-engine/tiled"
+	"golang.org/x/image/font/opentype"
+)
+
+/*
+Sound is an encoded audio file kept in memory, Format is its extension ("wav", "mp3", "ogg")
+Decode it with ebiten's audio/wav, audio/mp3 or audio/vorbis (eg: wav.DecodeWithSampleRate(rate, sound.NewReader()))
+so the engine itself doesn't depend on an audio backend
+*/
+type Sound struct {
+	Data   []byte
+	Format string
+}
+
+func (s *Sound) NewReader() *bytes.Reader {
+	return bytes.NewReader(s.Data)
+}
+
+func loadImage(fsys fs.FS, name string) (interface{}, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+func loadFont(fsys fs.FS, name string) (interface{}, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.Parse(data)
+}
+
+func loadSound(fsys fs.FS, name string) (interface{}, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Sound{Data: data, Format: strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")}, nil
+}
+
+func loadData(fsys fs.FS, name string) (interface{}, error) {
+	return fs.ReadFile(fsys, name)
+}
+
+func loadAtlas(fsys fs.FS, name string) (interface{}, error) {
+	return sprite.LoadFS(fsys, name)
+}
+
+func loadMap(fsys fs.FS, name string) (interface{}, error) {
+	return tiled.LoadFS(fsys, name)
+}
+
+// Disposes the images of a map (tilesets, image collection tiles and image layers)
+func unloadMap(v interface{}) {
+	for _, img := range mapImages(v.(*tiled.Map)) {
+		img.Dispose()
+	}
+}
+
+// Every image a map loaded, once even if shared (the loader reuses images by path)
+func mapImages(m *tiled.Map) []*ebiten.Image {
+	var images []*ebiten.Image
+	seen := map[*ebiten.Image]bool{}
+	add := func(img *ebiten.Image) {
+		if img != nil && !seen[img] {
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+	for _, ts := range m.Tilesets {
+		add(ts.Image)
+		for _, info := range ts.Tiles {
+			add(info.Image)
+		}
+	}
+	m.EachLayer(func(l *tiled.Layer) {
+		add(l.Image)
+	})
+	return images
+}
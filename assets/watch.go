@@ -0,0 +1,322 @@
+package assets
+
+import (
+	"encoding/json"
+	"io/fs"
+	"reflect"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+	"github.com/shubhamdwivedii/gopher-engine/tiled"
+	"golang.org/x/image/font/opentype"
+)
+
+// Wraps a file system and records every file opened through it
+type recordingFS struct {
+	fsys   fs.FS
+	opened []string
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	file, err := r.fsys.Open(name)
+	if err == nil {
+		for _, n := range r.opened {
+			if n == name {
+				return file, nil
+			}
+		}
+		r.opened = append(r.opened, name)
+	}
+	return file, err
+}
+
+/*
+OnReload is called after an asset was reloaded (value is the live value, which may be a new one
+for assets that can't be updated in place) or failed to reload (err is set, the old value is kept)
+A replaced value isn't freed right away since something may still hold it (eg: an image of a different size
+keeps its old pixels), it's unloaded with the asset once its references reach 0
+*/
+type OnReload func(kind, name string, value interface{}, err error)
+
+type watcher struct {
+	interval float64 // Seconds between polls
+	elapsed  float64
+	onReload OnReload
+}
+
+/*
+Enables hot reloading (meant for development): every interval seconds Update checks the modification time
+of every file a loaded asset was read from and reloads assets that changed
+Reloaded values are swapped into the live ones where possible, so objects holding them see the change:
+images of the same size are redrawn, atlases update their frames (removed frames become empty),
+maps update their tilesets and layers (a tilemap.Renderer needs InvalidateAll from OnReload to show it),
+fonts and sounds are overwritten and JSON loaded with LoadJSON is decoded again into the same value
+Files without modification times (eg: embed.FS) are never reloaded
+*/
+func (m *Manager) EnableHotReload(interval float64, onReload OnReload) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if interval <= 0 {
+		interval = 1
+	}
+	m.watcher = &watcher{interval: interval, onReload: onReload}
+	for _, e := range m.entries {
+		e.modTimes = m.stat(e.files)
+	}
+}
+
+func (m *Manager) DisableHotReload() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.watcher = nil
+}
+
+func (m *Manager) IsHotReloading() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.watcher != nil
+}
+
+func (m *Manager) stat(files []string) map[string]time.Time {
+	modTimes := make(map[string]time.Time, len(files))
+	for _, name := range files {
+		if info, err := fs.Stat(m.FS, name); err == nil {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// Polls for changed files once per interval when hot reloading is enabled, call it every tick
+func (m *Manager) Update() error {
+	m.mutex.Lock()
+	w := m.watcher
+	if w == nil {
+		m.mutex.Unlock()
+		return nil
+	}
+	w.elapsed += 1 / float64(ebiten.TPS())
+	if w.elapsed < w.interval {
+		m.mutex.Unlock()
+		return nil
+	}
+	w.elapsed = 0
+	m.mutex.Unlock()
+
+	m.Poll()
+	return nil
+}
+
+type reloaded struct {
+	kind, name string
+	value      interface{}
+	err        error
+}
+
+// Checks for changed files right away and reloads their assets
+func (m *Manager) Poll() {
+	m.mutex.Lock()
+	var changed []*entry
+	for _, e := range m.entries {
+		if m.changed(e) {
+			changed = append(changed, e)
+		}
+	}
+	m.mutex.Unlock()
+
+	var results []reloaded
+	for _, e := range changed {
+		if r, ok := m.reload(e); ok {
+			results = append(results, r)
+		}
+	}
+
+	m.mutex.Lock()
+	var onReload OnReload
+	if m.watcher != nil {
+		onReload = m.watcher.onReload
+	}
+	m.mutex.Unlock()
+
+	// Called without the lock so callbacks can use the Manager
+	if onReload != nil {
+		for _, r := range results {
+			onReload(r.kind, r.name, r.value, r.err)
+		}
+	}
+}
+
+// True if any file of the entry changed, the new times are recorded so a broken file isn't retried every poll
+func (m *Manager) changed(e *entry) bool {
+	current := m.stat(e.files)
+	changed := false
+	for name, t := range current {
+		if old, ok := e.modTimes[name]; ok && !t.Equal(old) {
+			changed = true
+		}
+	}
+	e.modTimes = current
+	return changed
+}
+
+// Loads the entry again without holding the lock (like Load), ok is false if it was unloaded meanwhile
+func (m *Manager) reload(e *entry) (r reloaded, ok bool) {
+	m.mutex.Lock()
+	loader := m.loaders[e.kind]
+	m.mutex.Unlock()
+	value, files, err := m.load(loader, e.name)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.entries[key(e.kind, e.name)] != e {
+		if err == nil && loader.Unload != nil {
+			loader.Unload(value)
+		}
+		return r, false
+	}
+	r = reloaded{kind: e.kind, name: e.name, value: e.value, err: err}
+	if err != nil {
+		return r, true
+	}
+	if loader.Swap != nil {
+		value = loader.Swap(e.value, value)
+	}
+	// Only values that need freeing are kept (others are garbage collected once dropped)
+	if loader.Unload != nil && !sameValue(value, e.value) {
+		e.replaced = append(e.replaced, e.value)
+	}
+	e.value = value
+	e.files = files
+	e.modTimes = m.stat(files)
+	r.value = value
+
+	for _, target := range e.targets {
+		if err := json.Unmarshal(value.([]byte), target); err != nil {
+			r.err = err
+			return r, true
+		}
+	}
+	return r, true
+}
+
+// a == b without panicking on uncomparable values (eg: []byte, which are never the same)
+func sameValue(a, b interface{}) bool {
+	if a == nil || b == nil || !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+/***************** IN PLACE SWAPS *********************/
+
+// Same sized images are redrawn (sub-images stay valid), otherwise the new image is used (see OnReload)
+func swapImage(old, new interface{}) interface{} {
+	oldImg, newImg := old.(*ebiten.Image), new.(*ebiten.Image)
+	if oldImg.Bounds() != newImg.Bounds() {
+		return newImg
+	}
+	oldImg.Clear()
+	oldImg.DrawImage(newImg, nil)
+	newImg.Dispose()
+	return oldImg
+}
+
+// Shared by atlas frames that were removed on reload
+var emptyImage *ebiten.Image
+
+/*
+Frames are updated in place, so animation clips holding them keep working
+Frames missing from the new atlas are pointed at an empty image since the old texture is disposed
+*/
+func swapAtlas(old, new interface{}) interface{} {
+	oldAtlas, newAtlas := old.(*sprite.Atlas), new.(*sprite.Atlas)
+	oldImage := oldAtlas.Image
+	for name, f := range oldAtlas.Frames {
+		if updated, ok := newAtlas.Frames[name]; ok {
+			*f = *updated
+			newAtlas.Frames[name] = f
+			continue
+		}
+		if emptyImage == nil {
+			emptyImage = ebiten.NewImage(1, 1)
+		}
+		f.Image = emptyImage
+	}
+	*oldAtlas = *newAtlas
+	if oldImage != nil {
+		oldImage.Dispose()
+	}
+	return oldAtlas
+}
+
+/*
+Tilesets (by Name) and Layers (by ID) are updated in place, so LayerSources and Layers from GetLayer keep working
+Removed ones are left as they were, the old tileset and layer images are disposed
+Renderers caching chunks of the map still need Renderer.InvalidateAll (eg: from OnReload)
+*/
+func swapMap(old, new interface{}) interface{} {
+	oldMap, newMap := old.(*tiled.Map), new.(*tiled.Map)
+	stale := mapImages(oldMap)
+
+	tilesets := map[*tiled.Tileset]*tiled.Tileset{} // New to live
+	byName := map[string]*tiled.Tileset{}
+	for _, ts := range oldMap.Tilesets {
+		if _, ok := byName[ts.Name]; !ok {
+			byName[ts.Name] = ts
+		}
+	}
+	for i, ts := range newMap.Tilesets {
+		if live, ok := byName[ts.Name]; ok {
+			delete(byName, ts.Name)
+			*live = *ts
+			tilesets[ts] = live
+			newMap.Tilesets[i] = live
+		}
+	}
+	cell := func(c *tiled.Cell) {
+		if live, ok := tilesets[c.Tileset]; ok {
+			c.Tileset = live
+		}
+	}
+
+	layers := map[int]*tiled.Layer{}
+	oldMap.EachLayer(func(l *tiled.Layer) {
+		layers[l.ID] = l
+	})
+	var swapLayers func(list []*tiled.Layer, parent *tiled.Layer)
+	swapLayers = func(list []*tiled.Layer, parent *tiled.Layer) {
+		for i, l := range list {
+			if live, ok := layers[l.ID]; ok {
+				delete(layers, l.ID)
+				*live = *l
+				list[i], l = live, live
+			}
+			l.Parent = parent
+			for j := range l.Cells {
+				cell(&l.Cells[j])
+			}
+			for _, o := range l.Objects {
+				cell(&o.Cell)
+			}
+			swapLayers(l.Layers, l)
+		}
+	}
+	swapLayers(newMap.Layers, nil)
+
+	*oldMap = *newMap
+	for _, img := range stale {
+		img.Dispose()
+	}
+	return oldMap
+}
+
+func swapFont(old, new interface{}) interface{} {
+	*old.(*opentype.Font) = *new.(*opentype.Font)
+	return old
+}
+
+func swapSound(old, new interface{}) interface{} {
+	*old.(*Sound) = *new.(*Sound)
+	return old
+}
@@ -0,0 +1,260 @@
+package collision
+
+import (
+	"image/color"
+	"math"
+
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"github.com/shubhamdwivedii/gopher-engine/spatial"
+	"golang.org/x/image/math/f64"
+)
+
+// Result of a collision test between a and b
+type Contact struct {
+	Normal f64.Vec2 // Unit vector pointing from b towards a
+	Depth  float64  // Penetration depth along Normal
+}
+
+// Minimum Translation Vector, move a by this to separate it from b
+func (c Contact) MTV() f64.Vec2 {
+	return scale(c.Normal, c.Depth)
+}
+
+// Separating Axis Test, returns the contact with the smallest penetration
+func Collide(a, b Shape) (Contact, bool) {
+	// Fast paths
+	if ba, ok := a.(*AABB); ok {
+		if bb, ok := b.(*AABB); ok {
+			return collideAABBs(ba.AABB, bb.AABB)
+		}
+	}
+	if ca, ok := a.(*Circle); ok {
+		if cb, ok := b.(*Circle); ok {
+			return collideCircles(ca, cb)
+		}
+	}
+
+	if !a.Bounds().Overlaps(b.Bounds()) {
+		return Contact{}, false
+	}
+
+	best := Contact{Depth: math.Inf(1)}
+	axes := append(a.Axes(b), b.Axes(a)...)
+	for _, axis := range axes {
+		minA, maxA := a.Project(axis)
+		minB, maxB := b.Project(axis)
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return Contact{}, false
+		}
+		if overlap < best.Depth {
+			best = Contact{Normal: axis, Depth: overlap}
+		}
+	}
+	if math.IsInf(best.Depth, 1) {
+		return Contact{}, false
+	}
+
+	// Normal should point from b towards a
+	if dot(sub(a.Center(), b.Center()), best.Normal) < 0 {
+		best.Normal = scale(best.Normal, -1)
+	}
+	return best, true
+}
+
+func Overlaps(a, b Shape) bool {
+	_, ok := Collide(a, b)
+	return ok
+}
+
+func collideAABBs(a, b spatial.AABB) (Contact, bool) {
+	if !a.Overlaps(b) {
+		return Contact{}, false
+	}
+	ox := math.Min(a.Right(), b.Right()) - math.Max(a.X, b.X)
+	oy := math.Min(a.Bottom(), b.Bottom()) - math.Max(a.Y, b.Y)
+	acx, acy := a.Center()
+	bcx, bcy := b.Center()
+	if ox < oy {
+		if acx < bcx {
+			return Contact{Normal: f64.Vec2{-1, 0}, Depth: ox}, true
+		}
+		return Contact{Normal: f64.Vec2{1, 0}, Depth: ox}, true
+	}
+	if acy < bcy {
+		return Contact{Normal: f64.Vec2{0, -1}, Depth: oy}, true
+	}
+	return Contact{Normal: f64.Vec2{0, 1}, Depth: oy}, true
+}
+
+func collideCircles(a, b *Circle) (Contact, bool) {
+	d := sub(a.Position, b.Position)
+	dist := length(d)
+	depth := a.Radius + b.Radius - dist
+	if depth <= 0 {
+		return Contact{}, false
+	}
+	if dist == 0 {
+		return Contact{Normal: f64.Vec2{0, -1}, Depth: depth}, true
+	}
+	return Contact{Normal: scale(d, 1/dist), Depth: depth}, true
+}
+
+/*
+Swept AABB, for fast movers that could tunnel through thin objects
+moving travels by (vx, vy) this step, returns time of impact t in [0, 1] and the surface normal
+*/
+func Sweep(moving spatial.AABB, vx, vy float64, static spatial.AABB) (t float64, normal f64.Vec2, hit bool) {
+	// Minkowski sum of static with moving, then raycast its center
+	expanded := spatial.NewAABB(static.X-moving.W/2, static.Y-moving.H/2, static.W+moving.W, static.H+moving.H)
+	cx, cy := moving.Center()
+
+	if cx > expanded.X && cx < expanded.Right() && cy > expanded.Y && cy < expanded.Bottom() {
+		return 0, f64.Vec2{}, false // Already overlapping, use Collide instead
+	}
+	t, hit = expanded.Raycast(cx, cy, vx, vy, 1)
+	if !hit {
+		return 1, f64.Vec2{}, false
+	}
+
+	// Normal of the face that was hit
+	px, py := cx+vx*t, cy+vy*t
+	const eps = 1e-9
+	switch {
+	case math.Abs(px-expanded.X) < eps:
+		normal = f64.Vec2{-1, 0}
+	case math.Abs(px-expanded.Right()) < eps:
+		normal = f64.Vec2{1, 0}
+	case math.Abs(py-expanded.Y) < eps:
+		normal = f64.Vec2{0, -1}
+	default:
+		normal = f64.Vec2{0, 1}
+	}
+
+	// Touching but moving away (or sliding along) is not a hit
+	if dot(normal, f64.Vec2{vx, vy}) >= 0 {
+		return 1, f64.Vec2{}, false
+	}
+	return t, normal, true
+}
+
+/***************** COLLIDER & LAYERS *********************/
+
+// Collider is a Shape with collision Layers, Data can hold the owner (eg: *Gopher, ecs.Entity)
+type Collider struct {
+	Shape Shape
+	Layer uint32 // Bits of layers this Collider is on
+	Mask  uint32 // Bits of layers this Collider collides with
+	Data  interface{}
+
+	id    spatial.ID
+	space *Space
+}
+
+// Collider on layer 1 that collides with everything
+func NewCollider(shape Shape, data interface{}) *Collider {
+	return &Collider{Shape: shape, Layer: 1, Mask: math.MaxUint32, Data: data}
+}
+
+// Both Colliders must be interested in each other's layers
+func CanCollide(a, b *Collider) bool {
+	return a.Layer&b.Mask != 0 && b.Layer&a.Mask != 0
+}
+
+/***************** SPACE *********************/
+
+// Space keeps Colliders in a Spatial Hash for broadphase, and tests narrowphase with Collide
+type Space struct {
+	Hash      *spatial.Hash
+	colliders map[spatial.ID]*Collider
+}
+
+func NewSpace(cellSize float64) *Space {
+	return &Space{
+		Hash:      spatial.NewHash(cellSize),
+		colliders: map[spatial.ID]*Collider{},
+	}
+}
+
+func (s *Space) Add(c *Collider) {
+	if c.space != nil {
+		return
+	}
+	c.id = s.Hash.Insert(c.Shape.Bounds(), c)
+	c.space = s
+	s.colliders[c.id] = c
+}
+
+func (s *Space) Remove(c *Collider) {
+	if c.space != s {
+		return
+	}
+	s.Hash.Remove(c.id)
+	delete(s.colliders, c.id)
+	c.space = nil
+}
+
+// Call after moving a Collider's Shape
+func (s *Space) Update(c *Collider) {
+	if c.space == s {
+		s.Hash.Move(c.id, c.Shape.Bounds())
+	}
+}
+
+// Moves the Collider's Shape and updates the Space
+func (s *Space) MoveBy(c *Collider, dx, dy float64) {
+	c.Shape.MoveBy(dx, dy)
+	s.Update(c)
+}
+
+type Collision struct {
+	Other   *Collider
+	Contact Contact // Normal points from Other towards the tested Collider
+}
+
+// All Colliders overlapping c (respecting layers)
+func (s *Space) Check(c *Collider) []Collision {
+	var result []Collision
+	s.Hash.QueryRect(c.Shape.Bounds(), func(id spatial.ID, bounds spatial.AABB, data interface{}) bool {
+		other := data.(*Collider)
+		if other == c || !CanCollide(c, other) {
+			return true
+		}
+		if contact, ok := Collide(c.Shape, other.Shape); ok {
+			result = append(result, Collision{Other: other, Contact: contact})
+		}
+		return true
+	})
+	return result
+}
+
+// Colliders (on layers in mask) containing the point
+func (s *Space) QueryPoint(x, y float64, mask uint32) []*Collider {
+	probe := &Collider{Shape: NewCircle(x, y, 0.01), Layer: math.MaxUint32, Mask: mask}
+	var result []*Collider
+	for _, c := range s.Check(probe) {
+		result = append(result, c.Other)
+	}
+	return result
+}
+
+// Moves c by the MTVs of everything it overlaps, returns the collisions that were resolved
+func (s *Space) Resolve(c *Collider) []Collision {
+	collisions := s.Check(c)
+	for _, col := range collisions {
+		// Re-test, an earlier push may have already separated them
+		if contact, ok := Collide(c.Shape, col.Other.Shape); ok {
+			mtv := contact.MTV()
+			c.Shape.MoveBy(mtv[0], mtv[1])
+		}
+	}
+	s.Update(c)
+	return collisions
+}
+
+// Draws every Collider's Shape using Screen.DrawLine
+func (s *Space) DebugDraw(screen scr.Screen, clr color.Color) {
+	for _, c := range s.colliders {
+		c.Shape.DebugDraw(screen, clr)
+	}
+}
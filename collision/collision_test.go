@@ -0,0 +1,157 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shubhamdwivedii/gopher-engine/spatial"
+	"golang.org/x/image/math/f64"
+)
+
+const eps = 1e-9
+
+func square(x, y, size float64) *Polygon {
+	return NewPolygon(x, y, f64.Vec2{0, 0}, f64.Vec2{size, 0}, f64.Vec2{size, size}, f64.Vec2{0, size})
+}
+
+func TestCollideNormalPointsFromBToA(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   Shape
+		normal f64.Vec2
+		depth  float64
+	}{
+		{"aabb left of aabb", NewAABB(0, 0, 10, 10), NewAABB(8, 0, 10, 10), f64.Vec2{-1, 0}, 2},
+		{"aabb right of aabb", NewAABB(8, 0, 10, 10), NewAABB(0, 0, 10, 10), f64.Vec2{1, 0}, 2},
+		{"aabb below aabb", NewAABB(0, 8, 10, 10), NewAABB(0, 0, 10, 10), f64.Vec2{0, 1}, 2},
+		{"aabb above aabb", NewAABB(0, -7, 10, 10), NewAABB(0, 0, 10, 10), f64.Vec2{0, -1}, 3},
+		{"circle left of circle", NewCircle(0, 0, 5), NewCircle(8, 0, 5), f64.Vec2{-1, 0}, 2},
+		{"circle below circle", NewCircle(0, 7, 5), NewCircle(0, 0, 5), f64.Vec2{0, 1}, 3},
+		{"circle right of aabb", NewCircle(13, 5, 5), NewAABB(0, 0, 10, 10), f64.Vec2{1, 0}, 2},
+		{"aabb left of circle", NewAABB(0, 0, 10, 10), NewCircle(13, 5, 5), f64.Vec2{-1, 0}, 2},
+		{"polygon above aabb", square(0, -8, 10), NewAABB(0, 0, 10, 10), f64.Vec2{0, -1}, 2},
+		{"aabb below polygon", NewAABB(0, 0, 10, 10), square(0, -8, 10), f64.Vec2{0, 1}, 2},
+		{"polygon right of polygon", square(9, 0, 10), square(0, 0, 10), f64.Vec2{1, 0}, 1},
+		{"capsule above aabb", NewCapsule(2, -16, 6, 20), NewAABB(0, 0, 10, 10), f64.Vec2{0, -1}, 4},
+		{"aabb below capsule", NewAABB(0, 0, 10, 10), NewCapsule(2, -16, 6, 20), f64.Vec2{0, 1}, 4},
+		{"capsule left of circle", NewCapsule(0, 0, 10, 30), NewCircle(13, 15, 5), f64.Vec2{-1, 0}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := Collide(tt.a, tt.b)
+			if !ok {
+				t.Fatal("no contact")
+			}
+			if math.Abs(c.Normal[0]-tt.normal[0]) > eps || math.Abs(c.Normal[1]-tt.normal[1]) > eps {
+				t.Fatalf("normal %v, want %v", c.Normal, tt.normal)
+			}
+			if math.Abs(c.Depth-tt.depth) > eps {
+				t.Fatalf("depth %v, want %v", c.Depth, tt.depth)
+			}
+
+			// Moving a by the MTV separates them
+			mtv := c.MTV()
+			tt.a.MoveBy(mtv[0], mtv[1])
+			if c, ok := Collide(tt.a, tt.b); ok && c.Depth > eps {
+				t.Fatalf("still overlapping by %v after MTV", c.Depth)
+			}
+		})
+	}
+}
+
+func TestCollideSeparated(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Shape
+	}{
+		{"touching aabbs", NewAABB(0, 0, 10, 10), NewAABB(10, 0, 10, 10)},
+		{"far circles", NewCircle(0, 0, 5), NewCircle(20, 0, 5)},
+		// Bounds overlap, but the circle misses the corner
+		{"circle past corner", NewCircle(13, 13, 4), NewAABB(0, 0, 10, 10)},
+		{"capsule past corner", NewCapsule(12, 12, 6, 20), NewAABB(0, 0, 10, 10)},
+		{"triangle diagonal", NewPolygon(0, 0, f64.Vec2{0, 0}, f64.Vec2{10, 0}, f64.Vec2{0, 10}), NewAABB(6, 6, 4, 4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, ok := Collide(tt.a, tt.b); ok {
+				t.Fatalf("unexpected contact %+v", c)
+			}
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	moving := spatial.NewAABB(0, 0, 10, 10)
+	wall := spatial.NewAABB(25, 0, 10, 10)
+
+	toi, normal, hit := Sweep(moving, 20, 0, wall)
+	if !hit || math.Abs(toi-0.75) > eps || normal != (f64.Vec2{-1, 0}) {
+		t.Fatalf("Sweep() = %v, %v, %v, want 0.75, (-1, 0), true", toi, normal, hit)
+	}
+
+	if _, _, hit := Sweep(moving, -20, 0, wall); hit {
+		t.Fatal("hit while moving away")
+	}
+	if _, _, hit := Sweep(moving, 10, 0, wall); hit {
+		t.Fatal("hit while stopping short of the wall")
+	}
+	if toi, _, hit := Sweep(moving, 20, 0, spatial.NewAABB(5, 0, 10, 10)); hit || toi != 0 {
+		t.Fatal("already overlapping boxes must be left to Collide")
+	}
+
+	// Landing on a floor
+	floor := spatial.NewAABB(-50, 20, 100, 10)
+	toi, normal, hit = Sweep(moving, 3, 40, floor)
+	if !hit || math.Abs(toi-0.25) > eps || normal != (f64.Vec2{0, -1}) {
+		t.Fatalf("Sweep() = %v, %v, %v, want 0.25, (0, -1), true", toi, normal, hit)
+	}
+}
+
+func TestLayerMasks(t *testing.T) {
+	const (
+		layerPlayer = 1 << iota
+		layerEnemy
+		layerPickup
+	)
+
+	player := NewCollider(NewAABB(0, 0, 10, 10), "player")
+	player.Layer, player.Mask = layerPlayer, layerEnemy
+	enemy := NewCollider(NewAABB(5, 0, 10, 10), "enemy")
+	enemy.Layer, enemy.Mask = layerEnemy, layerPlayer
+	// Wants the player, but the player doesn't want pickups
+	pickup := NewCollider(NewCircle(5, 5, 3), "pickup")
+	pickup.Layer, pickup.Mask = layerPickup, layerPlayer
+
+	if !CanCollide(player, enemy) || CanCollide(player, pickup) || CanCollide(enemy, pickup) {
+		t.Fatal("CanCollide must need both masks to match")
+	}
+
+	s := NewSpace(16)
+	s.Add(player)
+	s.Add(enemy)
+	s.Add(pickup)
+
+	collisions := s.Check(player)
+	if len(collisions) != 1 || collisions[0].Other != enemy {
+		t.Fatalf("Check() = %+v, want only the enemy", collisions)
+	}
+	if n := collisions[0].Contact.Normal; n != (f64.Vec2{-1, 0}) {
+		t.Fatalf("normal %v, want (-1, 0) (from enemy towards player)", n)
+	}
+
+	if hits := s.QueryPoint(5, 5, layerPickup); len(hits) != 1 || hits[0] != pickup {
+		t.Fatalf("QueryPoint(pickup layer) = %v, want the pickup", hits)
+	}
+	if hits := s.QueryPoint(5, 5, layerPlayer|layerEnemy); len(hits) != 2 {
+		t.Fatalf("QueryPoint(player|enemy) found %d colliders, want 2", len(hits))
+	}
+
+	s.Resolve(player)
+	if len(s.Check(player)) != 0 {
+		t.Fatal("player still overlaps after Resolve")
+	}
+	if b := player.Shape.Bounds(); b.X != -5 {
+		t.Fatalf("player resolved to x=%v, want -5", b.X)
+	}
+}
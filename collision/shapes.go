@@ -0,0 +1,269 @@
+package collision
+
+import (
+	"image/color"
+	"math"
+
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"github.com/shubhamdwivedii/gopher-engine/spatial"
+	"golang.org/x/image/math/f64"
+)
+
+// Shapes are in World coordinates, all are convex (so SAT works for every pair)
+type Shape interface {
+	Bounds() spatial.AABB
+	Center() f64.Vec2
+	MoveBy(dx, dy float64)
+	Project(axis f64.Vec2) (min, max float64) // axis must be normalized
+	Axes(other Shape) []f64.Vec2              // Separating axes to test against other
+	Vertices() []f64.Vec2                     // Points other shapes build axes towards
+	DebugDraw(screen scr.Screen, clr color.Color)
+}
+
+/***************** AABB *********************/
+
+type AABB struct {
+	spatial.AABB
+}
+
+func NewAABB(x, y, width, height float64) *AABB {
+	return &AABB{spatial.NewAABB(x, y, width, height)}
+}
+
+func (a *AABB) Bounds() spatial.AABB {
+	return a.AABB
+}
+
+func (a *AABB) Center() f64.Vec2 {
+	cx, cy := a.AABB.Center()
+	return f64.Vec2{cx, cy}
+}
+
+func (a *AABB) MoveBy(dx, dy float64) {
+	a.X += dx
+	a.Y += dy
+}
+
+func (a *AABB) Project(axis f64.Vec2) (min, max float64) {
+	return projectPoints(a.Vertices(), axis)
+}
+
+func (a *AABB) Axes(other Shape) []f64.Vec2 {
+	return []f64.Vec2{{1, 0}, {0, 1}}
+}
+
+func (a *AABB) Vertices() []f64.Vec2 {
+	return []f64.Vec2{{a.X, a.Y}, {a.Right(), a.Y}, {a.Right(), a.Bottom()}, {a.X, a.Bottom()}}
+}
+
+func (a *AABB) DebugDraw(screen scr.Screen, clr color.Color) {
+	drawPolyline(screen, a.Vertices(), clr)
+}
+
+/***************** CIRCLE *********************/
+
+type Circle struct {
+	Position f64.Vec2 // Center
+	Radius   float64
+}
+
+func NewCircle(cx, cy, radius float64) *Circle {
+	return &Circle{Position: f64.Vec2{cx, cy}, Radius: radius}
+}
+
+func (c *Circle) Bounds() spatial.AABB {
+	return spatial.NewAABB(c.Position[0]-c.Radius, c.Position[1]-c.Radius, 2*c.Radius, 2*c.Radius)
+}
+
+func (c *Circle) Center() f64.Vec2 {
+	return c.Position
+}
+
+func (c *Circle) MoveBy(dx, dy float64) {
+	c.Position[0] += dx
+	c.Position[1] += dy
+}
+
+func (c *Circle) Project(axis f64.Vec2) (min, max float64) {
+	d := dot(c.Position, axis)
+	return d - c.Radius, d + c.Radius
+}
+
+// Axis towards the nearest vertex of other
+func (c *Circle) Axes(other Shape) []f64.Vec2 {
+	nearest, best := f64.Vec2{}, math.Inf(1)
+	for _, v := range other.Vertices() {
+		if d := length(sub(v, c.Position)); d < best {
+			nearest, best = v, d
+		}
+	}
+	if best == 0 || math.IsInf(best, 1) {
+		return nil
+	}
+	return []f64.Vec2{normalize(sub(nearest, c.Position))}
+}
+
+func (c *Circle) Vertices() []f64.Vec2 {
+	return []f64.Vec2{c.Position}
+}
+
+func (c *Circle) DebugDraw(screen scr.Screen, clr color.Color) {
+	const segments = 16
+	points := make([]f64.Vec2, segments)
+	for i := range points {
+		a := float64(i) * 2 * math.Pi / segments
+		points[i] = f64.Vec2{c.Position[0] + c.Radius*math.Cos(a), c.Position[1] + c.Radius*math.Sin(a)}
+	}
+	drawPolyline(screen, points, clr)
+}
+
+/***************** POLYGON *********************/
+
+// Convex Polygon, Points are relative to Position and should be in clockwise or counter-clockwise order
+type Polygon struct {
+	Position f64.Vec2
+	Points   []f64.Vec2
+}
+
+func NewPolygon(x, y float64, points ...f64.Vec2) *Polygon {
+	return &Polygon{Position: f64.Vec2{x, y}, Points: points}
+}
+
+func (p *Polygon) Bounds() spatial.AABB {
+	verts := p.Vertices()
+	if len(verts) == 0 {
+		return spatial.NewAABB(p.Position[0], p.Position[1], 0, 0)
+	}
+	minX, maxX := projectPoints(verts, f64.Vec2{1, 0})
+	minY, maxY := projectPoints(verts, f64.Vec2{0, 1})
+	return spatial.NewAABB(minX, minY, maxX-minX, maxY-minY)
+}
+
+func (p *Polygon) Center() f64.Vec2 {
+	c := f64.Vec2{}
+	verts := p.Vertices()
+	for _, v := range verts {
+		c = add(c, v)
+	}
+	if len(verts) > 0 {
+		c = scale(c, 1/float64(len(verts)))
+	}
+	return c
+}
+
+func (p *Polygon) MoveBy(dx, dy float64) {
+	p.Position[0] += dx
+	p.Position[1] += dy
+}
+
+func (p *Polygon) Project(axis f64.Vec2) (min, max float64) {
+	return projectPoints(p.Vertices(), axis)
+}
+
+// Edge normals
+func (p *Polygon) Axes(other Shape) []f64.Vec2 {
+	verts := p.Vertices()
+	axes := make([]f64.Vec2, 0, len(verts))
+	for i := range verts {
+		edge := sub(verts[(i+1)%len(verts)], verts[i])
+		if n := normalize(perp(edge)); n != (f64.Vec2{}) {
+			axes = append(axes, n)
+		}
+	}
+	return axes
+}
+
+func (p *Polygon) Vertices() []f64.Vec2 {
+	verts := make([]f64.Vec2, len(p.Points))
+	for i, pt := range p.Points {
+		verts[i] = add(p.Position, pt)
+	}
+	return verts
+}
+
+func (p *Polygon) DebugDraw(screen scr.Screen, clr color.Color) {
+	drawPolyline(screen, p.Vertices(), clr)
+}
+
+/***************** CAPSULE *********************/
+
+// Capsule is a segment A-B swept by Radius (a pill shape)
+type Capsule struct {
+	A, B   f64.Vec2
+	Radius float64
+}
+
+// Vertical capsule (eg: for characters), x, y is TopLeft of its bounds
+func NewCapsule(x, y, width, height float64) *Capsule {
+	r := width / 2
+	return &Capsule{
+		A:      f64.Vec2{x + r, y + r},
+		B:      f64.Vec2{x + r, y + math.Max(r, height-r)},
+		Radius: r,
+	}
+}
+
+func (c *Capsule) Bounds() spatial.AABB {
+	minX, maxX := math.Min(c.A[0], c.B[0])-c.Radius, math.Max(c.A[0], c.B[0])+c.Radius
+	minY, maxY := math.Min(c.A[1], c.B[1])-c.Radius, math.Max(c.A[1], c.B[1])+c.Radius
+	return spatial.NewAABB(minX, minY, maxX-minX, maxY-minY)
+}
+
+func (c *Capsule) Center() f64.Vec2 {
+	return scale(add(c.A, c.B), 0.5)
+}
+
+func (c *Capsule) MoveBy(dx, dy float64) {
+	c.A = add(c.A, f64.Vec2{dx, dy})
+	c.B = add(c.B, f64.Vec2{dx, dy})
+}
+
+func (c *Capsule) Project(axis f64.Vec2) (min, max float64) {
+	min, max = projectPoints([]f64.Vec2{c.A, c.B}, axis)
+	return min - c.Radius, max + c.Radius
+}
+
+// Segment normal, and axes from the segment towards every vertex of other
+func (c *Capsule) Axes(other Shape) []f64.Vec2 {
+	var axes []f64.Vec2
+	if n := normalize(perp(sub(c.B, c.A))); n != (f64.Vec2{}) {
+		axes = append(axes, n)
+	}
+	for _, v := range other.Vertices() {
+		if d := sub(v, closestOnSegment(v, c.A, c.B)); length(d) > 0 {
+			axes = append(axes, normalize(d))
+		}
+	}
+	return axes
+}
+
+func (c *Capsule) Vertices() []f64.Vec2 {
+	return []f64.Vec2{c.A, c.B}
+}
+
+func (c *Capsule) DebugDraw(screen scr.Screen, clr color.Color) {
+	NewCircle(c.A[0], c.A[1], c.Radius).DebugDraw(screen, clr)
+	NewCircle(c.B[0], c.B[1], c.Radius).DebugDraw(screen, clr)
+	n := scale(normalize(perp(sub(c.B, c.A))), c.Radius)
+	screen.DrawLine(c.A[0]+n[0], c.A[1]+n[1], c.B[0]+n[0], c.B[1]+n[1], clr)
+	screen.DrawLine(c.A[0]-n[0], c.A[1]-n[1], c.B[0]-n[0], c.B[1]-n[1], clr)
+}
+
+/***************** HELPERS *********************/
+
+func projectPoints(points []f64.Vec2, axis f64.Vec2) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		d := dot(p, axis)
+		min, max = math.Min(min, d), math.Max(max, d)
+	}
+	return min, max
+}
+
+// Closed polyline through Screen.DrawLine
+func drawPolyline(screen scr.Screen, points []f64.Vec2, clr color.Color) {
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		screen.DrawLine(a[0], a[1], b[0], b[1], clr)
+	}
+}
@@ -0,0 +1,52 @@
+package collision
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+func add(a, b f64.Vec2) f64.Vec2 {
+	return f64.Vec2{a[0] + b[0], a[1] + b[1]}
+}
+
+func sub(a, b f64.Vec2) f64.Vec2 {
+	return f64.Vec2{a[0] - b[0], a[1] - b[1]}
+}
+
+func scale(a f64.Vec2, s float64) f64.Vec2 {
+	return f64.Vec2{a[0] * s, a[1] * s}
+}
+
+func dot(a, b f64.Vec2) float64 {
+	return a[0]*b[0] + a[1]*b[1]
+}
+
+func length(a f64.Vec2) float64 {
+	return math.Hypot(a[0], a[1])
+}
+
+// Returns zero vector for zero input
+func normalize(a f64.Vec2) f64.Vec2 {
+	l := length(a)
+	if l == 0 {
+		return f64.Vec2{}
+	}
+	return f64.Vec2{a[0] / l, a[1] / l}
+}
+
+// Perpendicular (rotated 90 degrees)
+func perp(a f64.Vec2) f64.Vec2 {
+	return f64.Vec2{-a[1], a[0]}
+}
+
+// Closest point to p on segment a-b
+func closestOnSegment(p, a, b f64.Vec2) f64.Vec2 {
+	ab := sub(b, a)
+	denom := dot(ab, ab)
+	if denom == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, dot(sub(p, a), ab)/denom))
+	return add(a, scale(ab, t))
+}
@@ -7,3 +7,57 @@ const AUTO_PADDING = 20
 const CAMERA_FOCUS_BOX_LINEAR = "FOCUS_BOX_LINEAR"
 const CAMERA_FOCUS_BOX_LERP = "FOCUS_BOX_LERP"
 const CAMERA_FOCUS_POINT_BASIC = "FOCUS_POINT_BASIC"
+
+const ANCHOR_TOP_LEFT = "TOP_LEFT"
+const ANCHOR_TOP = "TOP"
+const ANCHOR_TOP_RIGHT = "TOP_RIGHT"
+const ANCHOR_LEFT = "LEFT"
+const ANCHOR_CENTER = "CENTER"
+const ANCHOR_RIGHT = "RIGHT"
+const ANCHOR_BOTTOM_LEFT = "BOTTOM_LEFT"
+const ANCHOR_BOTTOM = "BOTTOM"
+const ANCHOR_BOTTOM_RIGHT = "BOTTOM_RIGHT"
+
+const OVERLAY_SCALE_STRETCH = "SCALE_STRETCH"
+const OVERLAY_SCALE_FIT = "SCALE_FIT"
+
+const TEXT_ALIGN_LEFT = "ALIGN_LEFT"
+const TEXT_ALIGN_CENTER = "ALIGN_CENTER"
+const TEXT_ALIGN_RIGHT = "ALIGN_RIGHT"
+const TEXT_ALIGN_JUSTIFY = "ALIGN_JUSTIFY"
+
+const ECS_PHASE_PRE_UPDATE = "PRE_UPDATE"
+const ECS_PHASE_UPDATE = "UPDATE"
+const ECS_PHASE_POST_UPDATE = "POST_UPDATE"
+const ECS_PHASE_RENDER = "RENDER"
+
+const PHYSICS_BODY_STATIC = "BODY_STATIC"
+const PHYSICS_BODY_KINEMATIC = "BODY_KINEMATIC"
+const PHYSICS_BODY_DYNAMIC = "BODY_DYNAMIC"
+
+const TILED_ORTHOGONAL = "orthogonal"
+const TILED_ISOMETRIC = "isometric"
+const TILED_STAGGERED = "staggered"
+const TILED_HEXAGONAL = "hexagonal"
+
+const TILED_LAYER_TILES = "tilelayer"
+const TILED_LAYER_OBJECTS = "objectgroup"
+const TILED_LAYER_IMAGE = "imagelayer"
+const TILED_LAYER_GROUP = "group"
+
+const ANIMATION_LOOP = "LOOP"
+const ANIMATION_PING_PONG = "PING_PONG"
+const ANIMATION_ONCE = "ONCE"
+
+const ASSET_IMAGE = "IMAGE"
+const ASSET_FONT = "FONT"
+const ASSET_SOUND = "SOUND"
+const ASSET_DATA = "DATA"
+const ASSET_ATLAS = "ATLAS"
+const ASSET_MAP = "MAP"
+
+const INPUT_KEY = "KEY"
+const INPUT_MOUSE_BUTTON = "MOUSE_BUTTON"
+const INPUT_GAMEPAD_BUTTON = "GAMEPAD_BUTTON"
+const INPUT_GAMEPAD_AXIS = "GAMEPAD_AXIS"
+const INPUT_CURSOR = "CURSOR" // Recorded cursor moves (see input.Recording)
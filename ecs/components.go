@@ -0,0 +1,75 @@
+package ecs
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/entity"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"golang.org/x/image/math/f64"
+)
+
+/***************** BUILT-IN COMPONENTS *********************/
+
+// Linear velocity in pixels per second, applied to *entity.Transform by MovementSystem
+type Velocity struct {
+	f64.Vec2
+}
+
+// Image drawn with the Entity's *entity.Transform by SpriteSystem
+type Sprite struct {
+	Image  *ebiten.Image
+	Z      float64 // Higher Z is drawn on top
+	Hidden bool
+	OP     ebiten.DrawImageOptions // ColorScale, Blend, Filter etc. (GeoM is applied before Transform)
+}
+
+var (
+	TransformType = TypeOf((*entity.Transform)(nil))
+	VelocityType  = TypeOf((*Velocity)(nil))
+	SpriteType    = TypeOf((*Sprite)(nil))
+)
+
+// Moves Transforms by their Velocity, meant for ECS_PHASE_UPDATE
+type MovementSystem struct{}
+
+func (MovementSystem) Update(w *World) error {
+	dt := 1 / float64(ebiten.TPS())
+	w.Query(TransformType, VelocityType).Each(func(e Entity) {
+		t := w.Get(e, TransformType).(*entity.Transform)
+		v := w.Get(e, VelocityType).(*Velocity)
+		t.Position[0] += v.Vec2[0] * dt
+		t.Position[1] += v.Vec2[1] * dt
+	})
+	return nil
+}
+
+// Draws Sprites sorted by Z (then by Y of Transform), through Screen so the Viewport is applied
+type SpriteSystem struct {
+	sorted []Entity
+}
+
+func (s *SpriteSystem) Draw(w *World, screen scr.Screen) {
+	s.sorted = s.sorted[:0]
+	w.Query(TransformType, SpriteType).Each(func(e Entity) {
+		s.sorted = append(s.sorted, e)
+	})
+
+	sort.SliceStable(s.sorted, func(i, j int) bool {
+		a, b := w.Get(s.sorted[i], SpriteType).(*Sprite), w.Get(s.sorted[j], SpriteType).(*Sprite)
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		ta, tb := w.Get(s.sorted[i], TransformType).(*entity.Transform), w.Get(s.sorted[j], TransformType).(*entity.Transform)
+		return ta.GetPosition()[1] < tb.GetPosition()[1]
+	})
+
+	for _, e := range s.sorted {
+		sprite := w.Get(e, SpriteType).(*Sprite)
+		if sprite.Hidden || sprite.Image == nil {
+			continue
+		}
+		op := sprite.OP
+		screen.DrawEntity(sprite.Image, w.Get(e, TransformType).(*entity.Transform), &op)
+	}
+}
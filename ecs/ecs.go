@@ -0,0 +1,75 @@
+package ecs
+
+import (
+	"reflect"
+)
+
+// Entity is just an ID, index in the lower 32 bits and generation in the upper 32 bits
+type Entity uint64
+
+func (e Entity) index() uint32 {
+	return uint32(e)
+}
+
+func (e Entity) generation() uint32 {
+	return uint32(e >> 32)
+}
+
+func newEntity(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<32 | uint64(index))
+}
+
+// Type of a Component, get it with TypeOf
+type ComponentType reflect.Type
+
+/*
+Returns the ComponentType of a component value, pointers are recommended
+so Systems can mutate components in place: ecs.TypeOf(&Position{}) or ecs.TypeOf((*Position)(nil))
+*/
+func TypeOf(component interface{}) ComponentType {
+	return reflect.TypeOf(component)
+}
+
+// Sparse-Set storage of a single Component type
+type storage struct {
+	dense    []interface{}
+	entities []Entity
+	sparse   map[uint32]int // Entity index -> position in dense
+}
+
+func newStorage() *storage {
+	return &storage{sparse: map[uint32]int{}}
+}
+
+func (s *storage) set(e Entity, component interface{}) {
+	if i, ok := s.sparse[e.index()]; ok {
+		s.dense[i] = component
+		s.entities[i] = e
+		return
+	}
+	s.sparse[e.index()] = len(s.dense)
+	s.dense = append(s.dense, component)
+	s.entities = append(s.entities, e)
+}
+
+func (s *storage) get(e Entity) (interface{}, bool) {
+	i, ok := s.sparse[e.index()]
+	if !ok || s.entities[i] != e {
+		return nil, false
+	}
+	return s.dense[i], true
+}
+
+// Swap-removes, so order of dense is not stable
+func (s *storage) remove(e Entity) {
+	i, ok := s.sparse[e.index()]
+	if !ok || s.entities[i] != e {
+		return
+	}
+	last := len(s.dense) - 1
+	s.dense[i], s.entities[i] = s.dense[last], s.entities[last]
+	s.sparse[s.entities[i].index()] = i
+	s.dense[last] = nil
+	s.dense, s.entities = s.dense[:last], s.entities[:last]
+	delete(s.sparse, e.index())
+}
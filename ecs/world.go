@@ -0,0 +1,300 @@
+package ecs
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/scene"
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+)
+
+var _ scene.Scene = (*World)(nil)
+
+// Runs every Update (in one of the update phases)
+type System interface {
+	Update(w *World) error
+}
+
+// Runs every Draw (in ECS_PHASE_RENDER), draws into the Screen so the Viewport is applied
+type RenderSystem interface {
+	Draw(w *World, screen scr.Screen)
+}
+
+// Adapts a plain func to System
+type SystemFunc func(w *World) error
+
+func (f SystemFunc) Update(w *World) error {
+	return f(w)
+}
+
+type systemEntry struct {
+	phase    string
+	priority int
+	order    int // Insertion order, keeps sort stable for equal priorities
+	update   System
+	render   RenderSystem
+}
+
+var updatePhases = []string{ECS_PHASE_PRE_UPDATE, ECS_PHASE_UPDATE, ECS_PHASE_POST_UPDATE}
+
+/*
+World holds Entities, their Components and the Systems working on them
+It implements scene.Scene, so it can be driven directly by the game loop
+*/
+type World struct {
+	Screen  scr.Screen  // Render Systems draw here, nil means Draw does nothing
+	Overlay ovr.Overlay // Optional, rendered over Screen
+
+	storages    map[ComponentType]*storage
+	generations []uint32
+	alive       []bool
+	free        []uint32
+	systems     []*systemEntry
+	pending     []Entity // Destroyed while iterating, removed after the System finishes
+	iterating   int
+}
+
+func NewWorld(screen scr.Screen) *World {
+	return &World{
+		Screen:   screen,
+		storages: map[ComponentType]*storage{},
+	}
+}
+
+/***************** ENTITIES *********************/
+
+// Creates an Entity with given components
+func (w *World) Create(components ...interface{}) Entity {
+	var index uint32
+	if n := len(w.free); n > 0 {
+		index = w.free[n-1]
+		w.free = w.free[:n-1]
+	} else {
+		index = uint32(len(w.generations))
+		w.generations = append(w.generations, 0)
+		w.alive = append(w.alive, false)
+	}
+	w.alive[index] = true
+	e := newEntity(index, w.generations[index])
+	for _, c := range components {
+		w.Add(e, c)
+	}
+	return e
+}
+
+func (w *World) IsAlive(e Entity) bool {
+	i := e.index()
+	return int(i) < len(w.alive) && w.alive[i] && w.generations[i] == e.generation()
+}
+
+// Removes an Entity and all its components, deferred if a Query is being iterated
+func (w *World) Destroy(e Entity) {
+	if !w.IsAlive(e) {
+		return
+	}
+	if w.iterating > 0 {
+		w.pending = append(w.pending, e)
+		return
+	}
+	for _, s := range w.storages {
+		s.remove(e)
+	}
+	i := e.index()
+	w.alive[i] = false
+	w.generations[i]++
+	w.free = append(w.free, i)
+}
+
+func (w *World) flush() {
+	pending := w.pending
+	w.pending = nil
+	for _, e := range pending {
+		w.Destroy(e)
+	}
+}
+
+// Number of alive Entities
+func (w *World) Count() int {
+	return len(w.generations) - len(w.free)
+}
+
+/***************** COMPONENTS *********************/
+
+// Adds (or replaces) a component, keyed by its type
+func (w *World) Add(e Entity, component interface{}) {
+	if !w.IsAlive(e) {
+		return
+	}
+	t := TypeOf(component)
+	s, ok := w.storages[t]
+	if !ok {
+		s = newStorage()
+		w.storages[t] = s
+	}
+	s.set(e, component)
+}
+
+func (w *World) Remove(e Entity, t ComponentType) {
+	if s, ok := w.storages[t]; ok {
+		s.remove(e)
+	}
+}
+
+// Returns component of type t, or nil
+func (w *World) Get(e Entity, t ComponentType) interface{} {
+	if s, ok := w.storages[t]; ok {
+		if c, ok := s.get(e); ok {
+			return c
+		}
+	}
+	return nil
+}
+
+func (w *World) Has(e Entity, t ComponentType) bool {
+	return w.Get(e, t) != nil
+}
+
+/***************** QUERIES *********************/
+
+// Query matches Entities having all of the Component types
+type Query struct {
+	world *World
+	types []ComponentType
+}
+
+func (w *World) Query(types ...ComponentType) *Query {
+	return &Query{world: w, types: types}
+}
+
+// Calls fn for every matching Entity, Destroy inside fn is deferred till the end
+func (q *Query) Each(fn func(e Entity)) {
+	w := q.world
+	if len(q.types) == 0 {
+		return
+	}
+
+	// Iterate over the smallest storage, check the rest
+	var smallest *storage
+	for _, t := range q.types {
+		s, ok := w.storages[t]
+		if !ok {
+			return
+		}
+		if smallest == nil || len(s.entities) < len(smallest.entities) {
+			smallest = s
+		}
+	}
+
+	entities := make([]Entity, len(smallest.entities))
+	copy(entities, smallest.entities)
+
+	w.iterating++
+	defer func() {
+		w.iterating--
+		if w.iterating == 0 {
+			w.flush()
+		}
+	}()
+
+	for _, e := range entities {
+		if w.matches(e, q.types) {
+			fn(e)
+		}
+	}
+}
+
+func (w *World) matches(e Entity, types []ComponentType) bool {
+	if !w.IsAlive(e) {
+		return false
+	}
+	for _, t := range types {
+		if _, ok := w.storages[t].get(e); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// All matching Entities
+func (q *Query) Entities() []Entity {
+	var result []Entity
+	q.Each(func(e Entity) {
+		result = append(result, e)
+	})
+	return result
+}
+
+/***************** SYSTEMS *********************/
+
+// Adds a System to an update phase (ECS_PHASE_*), lower priority runs first
+func (w *World) AddSystem(phase string, priority int, system System) {
+	w.addEntry(&systemEntry{phase: phase, priority: priority, update: system})
+}
+
+// Adds a Render System, lower priority draws first (below)
+func (w *World) AddRenderSystem(priority int, system RenderSystem) {
+	w.addEntry(&systemEntry{phase: ECS_PHASE_RENDER, priority: priority, render: system})
+}
+
+func (w *World) addEntry(entry *systemEntry) {
+	entry.order = len(w.systems)
+	w.systems = append(w.systems, entry)
+	sort.SliceStable(w.systems, func(i, j int) bool {
+		a, b := w.systems[i], w.systems[j]
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.order < b.order
+	})
+}
+
+// Removes a System (or Render System), SystemFuncs can't be compared so they can't be removed
+func (w *World) RemoveSystem(system interface{}) {
+	if !reflect.TypeOf(system).Comparable() {
+		return
+	}
+	for i, entry := range w.systems {
+		if (entry.update != nil && interface{}(entry.update) == system) ||
+			(entry.render != nil && interface{}(entry.render) == system) {
+			w.systems = append(w.systems[:i], w.systems[i+1:]...)
+			return
+		}
+	}
+}
+
+// Runs all update phases in order, then updates the Screen (shaker)
+func (w *World) Update() error {
+	for _, phase := range updatePhases {
+		for _, entry := range w.systems {
+			if entry.phase != phase || entry.update == nil {
+				continue
+			}
+			if err := entry.update.Update(w); err != nil {
+				return err
+			}
+		}
+	}
+	if w.Screen != nil {
+		return w.Screen.Update()
+	}
+	return nil
+}
+
+// Runs Render Systems into the Screen, then renders Screen (and Overlay) on target
+func (w *World) Draw(target *ebiten.Image) {
+	if w.Screen == nil {
+		return
+	}
+	for _, entry := range w.systems {
+		if entry.render != nil {
+			entry.render.Draw(w, w.Screen)
+		}
+	}
+	w.Screen.Render(target)
+	if w.Overlay != nil {
+		w.Overlay.Render(target)
+	}
+}
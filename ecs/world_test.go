@@ -0,0 +1,137 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+)
+
+type health struct {
+	HP int
+}
+
+type tag struct{}
+
+var (
+	healthType = TypeOf((*health)(nil))
+	tagType    = TypeOf((*tag)(nil))
+)
+
+func TestDestroyReusesIndexWithNewGeneration(t *testing.T) {
+	w := NewWorld(nil)
+	a := w.Create(&health{HP: 1})
+	w.Destroy(a)
+
+	if w.IsAlive(a) {
+		t.Fatal("destroyed entity is still alive")
+	}
+	if w.Has(a, healthType) {
+		t.Fatal("destroyed entity still has its component")
+	}
+
+	b := w.Create(&health{HP: 2})
+	if b.index() != a.index() {
+		t.Fatalf("index not reused, got %d want %d", b.index(), a.index())
+	}
+	if b.generation() != a.generation()+1 {
+		t.Fatalf("generation %d, want %d", b.generation(), a.generation()+1)
+	}
+	if w.IsAlive(a) || !w.IsAlive(b) {
+		t.Fatal("stale handle must be dead and the new one alive")
+	}
+	if w.Get(a, healthType) != nil {
+		t.Fatal("stale handle reads the new entity's component")
+	}
+	w.Add(a, &tag{})
+	if w.Has(b, tagType) {
+		t.Fatal("adding to a stale handle reached the new entity")
+	}
+	w.Destroy(a)
+	if !w.IsAlive(b) {
+		t.Fatal("destroying a stale handle killed the new entity")
+	}
+	if w.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", w.Count())
+	}
+}
+
+func TestDestroyDeferredDuringQuery(t *testing.T) {
+	w := NewWorld(nil)
+	var all []Entity
+	for i := 0; i < 4; i++ {
+		all = append(all, w.Create(&health{HP: i}))
+	}
+
+	visited := 0
+	w.Query(healthType).Each(func(e Entity) {
+		visited++
+		for _, other := range all {
+			w.Destroy(other)
+		}
+		if !w.IsAlive(e) {
+			t.Fatal("entity destroyed while the query is running")
+		}
+		if w.Get(e, healthType) == nil {
+			t.Fatal("component removed while the query is running")
+		}
+	})
+
+	if visited != len(all) {
+		t.Fatalf("visited %d entities, want %d", visited, len(all))
+	}
+	for _, e := range all {
+		if w.IsAlive(e) {
+			t.Fatal("deferred destroy wasn't applied after the query")
+		}
+	}
+	if w.Count() != 0 {
+		t.Fatalf("Count() = %d, want 0", w.Count())
+	}
+	if len(w.Query(healthType).Entities()) != 0 {
+		t.Fatal("query still matches destroyed entities")
+	}
+}
+
+func TestNestedQueryDefersUntilOutermost(t *testing.T) {
+	w := NewWorld(nil)
+	a := w.Create(&health{}, &tag{})
+	b := w.Create(&health{}, &tag{})
+
+	w.Query(healthType).Each(func(e Entity) {
+		w.Query(tagType).Each(func(inner Entity) {
+			w.Destroy(b)
+		})
+		if e == b && !w.IsAlive(b) {
+			t.Fatal("inner query flushed before the outer one finished")
+		}
+	})
+	if !w.IsAlive(a) || w.IsAlive(b) {
+		t.Fatal("only b should be destroyed after the outer query")
+	}
+}
+
+func TestSystemPhaseAndPriorityOrder(t *testing.T) {
+	w := NewWorld(nil)
+	var got []string
+	add := func(phase string, priority int, name string) {
+		w.AddSystem(phase, priority, SystemFunc(func(*World) error {
+			got = append(got, name)
+			return nil
+		}))
+	}
+
+	add(ECS_PHASE_POST_UPDATE, -10, "post")
+	add(ECS_PHASE_UPDATE, 5, "update-5")
+	add(ECS_PHASE_UPDATE, 0, "update-0a")
+	add(ECS_PHASE_PRE_UPDATE, 100, "pre")
+	add(ECS_PHASE_UPDATE, 0, "update-0b")
+
+	if err := w.Update(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"pre", "update-0a", "update-0b", "update-5", "post"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("order %v, want %v", got, want)
+	}
+}
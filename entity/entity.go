@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/math/f64"
+)
+
+// Anything that can be placed in the World (Viewport, Gopher, Transform etc)
+type Entity interface {
+	GetPosition() f64.Vec2 // TopLeft Coordinates in World
+	GetSize() f64.Vec2     // Size of the entity
+	GetScale() f64.Vec2
+	GetRotation() float64 // In degrees
+}
+
+// Entities that provide their own World matrix (like Transform) are drawn with it
+type Transformable interface {
+	GetWorldMatrix() ebiten.GeoM
+}
+
+/*
+Transform is an Entity with a parent-child hierarchy
+Position, Scale and Rotation are Local (relative to Parent), World values are derived
+*/
+type Transform struct {
+	Position f64.Vec2 // TopLeft relative to Parent's Origin
+	Size     f64.Vec2
+	Scale    f64.Vec2
+	Rotation float64  // In degrees, clockwise
+	Origin   f64.Vec2 // Pivot for Scale/Rotation, relative to TopLeft (eg: Size/2 for center)
+	Parent   *Transform
+	Children []*Transform
+}
+
+func New(x, y, width, height float64) *Transform {
+	return &Transform{
+		Position: f64.Vec2{x, y},
+		Size:     f64.Vec2{width, height},
+		Scale:    f64.Vec2{1, 1},
+	}
+}
+
+// Sets Origin to the center of the Transform
+func (t *Transform) CenterOrigin() {
+	t.Origin = f64.Vec2{t.Size[0] / 2, t.Size[1] / 2}
+}
+
+func (t *Transform) AddChild(child *Transform) {
+	child.SetParent(t)
+}
+
+func (t *Transform) RemoveChild(child *Transform) {
+	if child.Parent == t {
+		child.SetParent(nil)
+	}
+}
+
+// Re-parents keeping Local values (so World position may change)
+func (t *Transform) SetParent(parent *Transform) {
+	for p := parent; p != nil; p = p.Parent {
+		if p == t {
+			return // Would create a cycle
+		}
+	}
+	if t.Parent != nil {
+		siblings := t.Parent.Children
+		for i, c := range siblings {
+			if c == t {
+				t.Parent.Children = append(siblings[:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+	t.Parent = parent
+	if parent != nil {
+		parent.Children = append(parent.Children, t)
+	}
+}
+
+// Matrix from Local space of this Transform to its Parent's space
+func (t *Transform) GetLocalMatrix() ebiten.GeoM {
+	matrix := ebiten.GeoM{}
+	matrix.Translate(-t.Origin[0], -t.Origin[1])
+	matrix.Scale(t.Scale[0], t.Scale[1])
+	matrix.Rotate(t.Rotation * 2 * math.Pi / 360)
+	matrix.Translate(t.Origin[0]+t.Position[0], t.Origin[1]+t.Position[1])
+	return matrix
+}
+
+// Matrix from Local space of this Transform to the World
+func (t *Transform) GetWorldMatrix() ebiten.GeoM {
+	matrix := t.GetLocalMatrix()
+	if t.Parent != nil {
+		matrix.Concat(t.Parent.GetWorldMatrix())
+	}
+	return matrix
+}
+
+// Converts a point in Local space to World space
+func (t *Transform) LocalToWorld(x, y float64) f64.Vec2 {
+	matrix := t.GetWorldMatrix()
+	wx, wy := matrix.Apply(x, y)
+	return f64.Vec2{wx, wy}
+}
+
+// Converts a point in World space to Local space
+func (t *Transform) WorldToLocal(x, y float64) f64.Vec2 {
+	matrix := t.GetWorldMatrix()
+	if !matrix.IsInvertible() {
+		return f64.Vec2{math.NaN(), math.NaN()}
+	}
+	matrix.Invert()
+	lx, ly := matrix.Apply(x, y)
+	return f64.Vec2{lx, ly}
+}
+
+// World position of TopLeft (the local 0,0)
+func (t *Transform) GetPosition() f64.Vec2 {
+	return t.LocalToWorld(0, 0)
+}
+
+// World position of the Origin
+func (t *Transform) GetWorldOrigin() f64.Vec2 {
+	return t.LocalToWorld(t.Origin[0], t.Origin[1])
+}
+
+func (t *Transform) GetSize() f64.Vec2 {
+	return t.Size
+}
+
+// World scale (product of all parents' scales)
+func (t *Transform) GetScale() f64.Vec2 {
+	scale := t.Scale
+	for p := t.Parent; p != nil; p = p.Parent {
+		scale[0] *= p.Scale[0]
+		scale[1] *= p.Scale[1]
+	}
+	return scale
+}
+
+// World rotation in degrees (sum of all parents' rotations)
+func (t *Transform) GetRotation() float64 {
+	rotation := t.Rotation
+	for p := t.Parent; p != nil; p = p.Parent {
+		rotation += p.Rotation
+	}
+	return rotation
+}
+
+// Matrix of any Entity, Transformables use their own matrix
+// Others are scaled and rotated around their center, then moved to their position
+func GetMatrix(e Entity) ebiten.GeoM {
+	if t, ok := e.(Transformable); ok {
+		return t.GetWorldMatrix()
+	}
+	position, size, scale := e.GetPosition(), e.GetSize(), e.GetScale()
+	matrix := ebiten.GeoM{}
+	matrix.Translate(-size[0]/2, -size[1]/2)
+	matrix.Scale(scale[0], scale[1])
+	matrix.Rotate(e.GetRotation() * 2 * math.Pi / 360)
+	matrix.Translate(position[0]+size[0]/2, position[1]+size[1]/2)
+	return matrix
+}
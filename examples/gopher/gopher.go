@@ -2,15 +2,38 @@ package gopher
 
 import (
 	"image/color"
-	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"golang.org/x/image/math/f64"
 
+	"github.com/shubhamdwivedii/gopher-engine/assets"
+	"github.com/shubhamdwivedii/gopher-engine/collision"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/input"
+	"github.com/shubhamdwivedii/gopher-engine/physics/character"
 	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
 )
 
+// Actions the Gopher reads from its input.Map
+const (
+	ACTION_MOVE_X = "move_x"
+	ACTION_JUMP   = "jump"
+	ACTION_DROP   = "drop"
+)
+
+// Arrow keys, gamepad DPad and left stick
+func BindDefaults(controls *input.Map) {
+	controls.Bind(ACTION_MOVE_X,
+		input.Key(ebiten.KeyArrowLeft).Negative(),
+		input.Key(ebiten.KeyArrowRight),
+		input.GamepadButton(ebiten.StandardGamepadButtonLeftLeft).Negative(),
+		input.GamepadButton(ebiten.StandardGamepadButtonLeftRight),
+		input.GamepadAxis(ebiten.StandardGamepadAxisLeftStickHorizontal),
+	)
+	controls.Bind(ACTION_JUMP, input.Key(ebiten.KeyArrowUp), input.GamepadButton(ebiten.StandardGamepadButtonRightBottom))
+	controls.Bind(ACTION_DROP, input.Key(ebiten.KeyArrowDown), input.GamepadButton(ebiten.StandardGamepadButtonLeftBottom))
+}
+
 type Gopher struct {
 	Img *ebiten.Image
 	X   float64
@@ -19,62 +42,88 @@ type Gopher struct {
 	CY  float64
 	W   int
 	H   int
-	V   float64
+	V   float64 // Pixels per second, not per tick so it doesn't depend on TPS (eg: of a replay)
 	OP  *ebiten.DrawImageOptions
+
+	Controller *character.Controller
+	Controls   *input.Map
 }
 
-func New(cx, cy, v float64) *Gopher {
-	img, _, err := ebitenutil.NewImageFromFile("./examples/assets/gopher.png")
+// v is the max horizontal speed in pixels per second, the Gopher collides with the level in space
+// Its image is shared through the asset Manager (call Release when the Gopher is removed)
+// controls should have the Gopher's actions bound (see BindDefaults)
+func New(manager *assets.Manager, controls *input.Map, cx, cy, v float64, space *collision.Space) (*Gopher, error) {
+	img, err := manager.LoadImage("gopher.png")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	w, h := img.Bounds().Dx(), img.Bounds().Dy()
 	x, y := cx-float64(w/2), cy-float64(h/2)
 
+	controller := character.New(space, x, y, float64(w), float64(h))
+	controller.MoveSpeed = v
+
 	return &Gopher{
-		Img: img,
-		X:   x,
-		Y:   y,
-		CX:  cx,
-		CY:  cy,
-		W:   w,
-		H:   h,
-		V:   v,
-		OP:  &ebiten.DrawImageOptions{},
-	}
+		Img:        img,
+		X:          x,
+		Y:          y,
+		CX:         cx,
+		CY:         cy,
+		W:          w,
+		H:          h,
+		V:          v,
+		OP:         &ebiten.DrawImageOptions{},
+		Controller: controller,
+		Controls:   controls,
+	}, nil
 }
 
+func (g *Gopher) Release(manager *assets.Manager) {
+	manager.Release(ASSET_IMAGE, "gopher.png")
+}
+
+// TopLeft in World, so the Gopher is an entity.Entity (see Screen.DrawEntity)
 func (g *Gopher) GetPosition() f64.Vec2 {
+	return f64.Vec2{g.X, g.Y}
+}
+
+// Center in World (eg: for the Camera to follow)
+func (g *Gopher) GetCenter() f64.Vec2 {
 	return f64.Vec2{g.CX, g.CY}
 }
 
-func (g *Gopher) Update() error {
-	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-		g.X -= g.V
-		g.CX -= g.V
-	}
+func (g *Gopher) GetSize() f64.Vec2 {
+	return f64.Vec2{float64(g.W), float64(g.H)}
+}
 
-	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-		g.X += g.V
-		g.CX += g.V
-	}
+func (g *Gopher) GetScale() f64.Vec2 {
+	return f64.Vec2{1, 1}
+}
+
+func (g *Gopher) GetRotation() float64 {
+	return 0
+}
 
-	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
-		g.Y += g.V
-		g.CY += g.V
+func (g *Gopher) Update() error {
+	moveX := g.Controls.GetValue(ACTION_MOVE_X)
+	if g.Controls.IsJustPressed(ACTION_DROP) {
+		g.Controller.DropDown()
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
-		g.Y -= g.V
-		g.CY -= g.V
+	jumpPressed := g.Controls.IsJustPressed(ACTION_JUMP)
+	jumpHeld := g.Controls.IsPressed(ACTION_JUMP)
+	if err := g.Controller.Update(moveX, jumpPressed, jumpHeld); err != nil {
+		return err
 	}
+
+	g.X, g.Y, _, _ = g.Controller.GetBounds()
+	g.CX, g.CY = g.X+float64(g.W)/2, g.Y+float64(g.H)/2
 	return nil
 }
 
 func (g *Gopher) Draw(gameScreen scr.Screen) {
 	g.OP.GeoM.Reset()
-	g.OP.GeoM.Translate(g.X, g.Y)
 	gameScreen.DrawRect(g.X, g.Y, float64(g.W), float64(g.H), false, color.RGBA{255, 0, 0, 64})
-	gameScreen.DrawImage(g.Img, g.OP)
+	gameScreen.DrawEntity(g.Img, g, g.OP)
 }
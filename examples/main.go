@@ -1,17 +1,24 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	_ "image/png"
 	"log"
+	"math"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/shubhamdwivedii/gopher-engine/assets"
+	"github.com/shubhamdwivedii/gopher-engine/collision"
 	gop "github.com/shubhamdwivedii/gopher-engine/examples/gopher"
+	"github.com/shubhamdwivedii/gopher-engine/input"
+	"github.com/shubhamdwivedii/gopher-engine/physics"
 	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
 	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
 	vpt "github.com/shubhamdwivedii/gopher-engine/scene/viewport"
+	"golang.org/x/image/math/f64"
 )
 
 type Game struct{}
@@ -21,6 +28,9 @@ const (
 	VIEW_W, VIEW_H   = 320, 240
 )
 
+var assetManager *assets.Manager
+var controls *input.Map
+var replay *input.Playback
 var gameScreen scr.Screen
 var overlayScreen ovr.Overlay
 var viewport *vpt.Viewport
@@ -30,25 +40,98 @@ var healthbars *ebiten.Image
 var worldbg *ebiten.Image
 var gophers *ebiten.Image
 
+var level *physics.World
+var movingPlatform *physics.Body
+
+const ONE_WAY_LAYER = 1 << 1
+
+const GOPHER_SPEED = 180 // Pixels per second
+
+const (
+	ACTION_SHAKE         = "shake"
+	ACTION_PAN_X         = "pan_x"
+	ACTION_PAN_Y         = "pan_y"
+	ACTION_ZOOM          = "zoom"
+	ACTION_ROTATE        = "rotate"
+	ACTION_RESET_VIEW    = "reset_view"
+	ACTION_PIXEL_PERFECT = "pixel_perfect"
+)
+
+const CONTROLS_FILE = "./examples/controls.json"
+
+// Camera controls plus the Gopher's, overridden by CONTROLS_FILE if there is one
+func newControls() (*input.Map, error) {
+	controls := input.New()
+	controls.Bind(ACTION_SHAKE, input.Key(ebiten.KeySpace))
+	controls.Bind(ACTION_PAN_X, input.Key(ebiten.KeyA).Negative(), input.Key(ebiten.KeyD))
+	controls.Bind(ACTION_PAN_Y, input.Key(ebiten.KeyW).Negative(), input.Key(ebiten.KeyS))
+	controls.Bind(ACTION_ZOOM, input.Key(ebiten.KeyQ).Negative(), input.Key(ebiten.KeyE))
+	controls.Bind(ACTION_ROTATE, input.Key(ebiten.KeyR))
+	controls.Bind(ACTION_RESET_VIEW, input.Key(ebiten.KeyZ))
+	controls.Bind(ACTION_PIXEL_PERFECT, input.Key(ebiten.KeyP))
+	gop.BindDefaults(controls)
+
+	if err := controls.Load(CONTROLS_FILE); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return controls, nil
+}
+
+// Floor, walls, a slope, a jump-through ledge and a moving platform
+func newLevel() *physics.World {
+	world := physics.NewWorld(0, 900, 64)
+	solids := []collision.Shape{
+		collision.NewAABB(0, WORLD_H-20, WORLD_W, 20),
+		collision.NewAABB(-20, 0, 20, WORLD_H),
+		collision.NewAABB(WORLD_W, 0, 20, WORLD_H),
+		collision.NewPolygon(220, WORLD_H-20, f64.Vec2{0, 0}, f64.Vec2{100, -60}, f64.Vec2{100, 0}),
+		collision.NewAABB(320, WORLD_H-80, 100, 60),
+	}
+	for _, shape := range solids {
+		world.AddBody(physics.NewStaticBody(shape))
+	}
+
+	ledge := physics.NewStaticBody(collision.NewAABB(40, WORLD_H-120, 100, 8))
+	ledge.Collider.Layer = ONE_WAY_LAYER
+	world.AddBody(ledge)
+
+	movingPlatform = physics.NewKinematicBody(collision.NewAABB(180, WORLD_H-200, 80, 10))
+	movingPlatform.SetVelocity(40, 0)
+	world.AddBody(movingPlatform)
+	return world
+}
+
 func init() {
 	var err error
 
-	healthbars, _, err = ebitenutil.NewImageFromFile("./examples/assets/overlay_320x240.png")
+	assetManager = assets.NewFromDir("./examples/assets")
+
+	healthbars, err = assetManager.LoadImage("overlay_320x240.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	worldbg, err = assetManager.LoadImage("world_420x420.png")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	worldbg, _, err = ebitenutil.NewImageFromFile("./examples/assets/world_420x420.png")
+	gophers, err = assetManager.LoadImage("gopher.png")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	gophers, _, err = ebitenutil.NewImageFromFile("./examples/assets/gopher.png")
+	controls, err = newControls()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	gopher = gop.New(0, 0, 7)
+	level = newLevel()
+	gopher, err = gop.New(assetManager, controls, 60, 60, GOPHER_SPEED, level.Space) // Shares gopher.png with gophers
+	if err != nil {
+		log.Fatal(err)
+	}
+	gopher.Controller.OneWayLayer = ONE_WAY_LAYER
 	viewport = vpt.New(VIEW_W, VIEW_H, WORLD_W, WORLD_H, 160, 120)
 
 	gameScreen, err = scr.New(VIEW_W, VIEW_H, WORLD_W, WORLD_H, viewport)
@@ -60,42 +143,53 @@ func init() {
 }
 
 func (g *Game) Update() error {
-	if ebiten.IsKeyPressed(ebiten.KeySpace) {
-		gameScreen.GetShaker().Shake()
+	if err := assetManager.Update(); err != nil {
+		return err
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyA) {
-		viewport.MoveBy(-1, 0)
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyD) {
-		viewport.MoveBy(1, 0)
+	if err := controls.Update(); err != nil {
+		return err
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyW) {
-		viewport.MoveBy(0, -1)
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyS) {
-		viewport.MoveBy(0, 1)
+	if replay != nil && replay.IsDone() {
+		controls.Source, replay = &input.EbitenSource{}, nil
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyQ) {
-		viewport.ZoomBy(-1)
+	if controls.IsPressed(ACTION_SHAKE) {
+		gameScreen.GetShaker().Shake()
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyE) {
-		viewport.ZoomBy(1)
+
+	viewport.MoveBy(controls.GetValue(ACTION_PAN_X), controls.GetValue(ACTION_PAN_Y))
+
+	if controls.IsPressed(ACTION_ZOOM) {
+		viewport.ZoomBy(int(math.Copysign(1, controls.GetValue(ACTION_ZOOM))))
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyR) {
+	if controls.IsPressed(ACTION_ROTATE) {
 		viewport.RoatateBy(1)
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyZ) {
+	if controls.IsPressed(ACTION_RESET_VIEW) {
 		viewport.Reset()
 	}
 
-	gopher.Update()
+	if controls.IsJustPressed(ACTION_PIXEL_PERFECT) {
+		viewport.SetPixelPerfect(!viewport.PixelPerfect)
+	}
+
+	// Moving platform goes back and forth
+	if x := movingPlatform.GetPosition()[0]; (x > 340 && movingPlatform.Velocity[0] > 0) || (x < 120 && movingPlatform.Velocity[0] < 0) {
+		movingPlatform.SetVelocity(-movingPlatform.Velocity[0], 0)
+	}
+	if err := level.Update(); err != nil {
+		return err
+	}
+
+	if err := gopher.Update(); err != nil {
+		return err
+	}
 	fmt.Println("GOPHER POSISION", gopher.CX, gopher.CY)
 	// Update Camera After FocusEntity has been updated. (Or else you'll see jitter)
-	gopherPos := gopher.GetPosition()
+	gopherPos := gopher.GetCenter()
 	viewport.Camera.Update(gopherPos[0], gopherPos[1])
 	gameScreen.Update()
 	return nil
@@ -109,6 +203,7 @@ func (g *Game) Draw(renderScreen *ebiten.Image) {
 	gameScreen.DrawImage(worldbg, world)
 	// gameScreen.GetImage().DrawImage(worldbg, &ebiten.DrawImageOptions{})
 
+	level.DebugDraw(gameScreen, color.RGBA{0, 0, 255, 255})
 	gopher.Draw(gameScreen)
 
 	g2OP := &ebiten.DrawImageOptions{}
@@ -143,8 +238,9 @@ func (g *Game) Draw(renderScreen *ebiten.Image) {
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
 	// return 1024, 768 // To Test Resolution Independent Scaling
-	return VIEW_W, VIEW_H // Ideally Return Internal Resolution Here.
-	// return 1024, 768 // Scaling of OVerlay broken
+	screenWidth, screenHeight = VIEW_W, VIEW_H      // Ideally Return Internal Resolution Here.
+	overlayScreen.Resize(screenWidth, screenHeight) // Before anything is drawn, resizing clears the Overlay
+	return screenWidth, screenHeight
 }
 
 func main() {
@@ -154,7 +250,39 @@ func main() {
 	// gameScreen.GetViewport().SetMargin(10)
 	viewport.AllowOutOfBounds = false
 	viewport.Camera.OverflowAllowed(viewport.AllowOutOfBounds)
+
+	// go run ./examples -hot reloads edited assets while the game runs
+	hotReload := flag.Bool("hot", false, "reload assets when their files change")
+	// go run ./examples -record bug.json, then -replay bug.json plays the same input back
+	recordFile := flag.String("record", "", "record input to a file until the window is closed")
+	replayFile := flag.String("replay", "", "replay input recorded with -record")
+	flag.Parse()
+	if *replayFile != "" {
+		recording, err := input.LoadRecording(*replayFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if recording.TPS > 0 {
+			ebiten.SetTPS(recording.TPS)
+		}
+		replay = controls.Play(recording)
+	} else if *recordFile != "" {
+		controls.StartRecording()
+	}
+	if *hotReload {
+		assetManager.EnableHotReload(1, func(kind, name string, value interface{}, err error) {
+			if err != nil {
+				log.Println("RELOAD FAILED", kind, name, err)
+			}
+		})
+	}
+
 	if err := ebiten.RunGame(&Game{}); err != nil {
 		log.Fatal(err)
 	}
+	if recording := controls.StopRecording(); recording != nil {
+		if err := recording.Save(*recordFile); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
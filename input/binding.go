@@ -0,0 +1,138 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+)
+
+/*
+Binding maps a key, mouse button, gamepad button or gamepad axis to an Action
+Type is INPUT_KEY, INPUT_MOUSE_BUTTON, INPUT_GAMEPAD_BUTTON or INPUT_GAMEPAD_AXIS
+and Code the matching ebiten.Key, ebiten.MouseButton, ebiten.StandardGamepadButton or ebiten.StandardGamepadAxis
+Buttons give Scale while held, axes give their value times Scale (eg: -1 for the left arrow of "move_x")
+*/
+type Binding struct {
+	Type  string
+	Code  int
+	Scale float64
+}
+
+func Key(key ebiten.Key) Binding {
+	return Binding{Type: INPUT_KEY, Code: int(key), Scale: 1}
+}
+
+func MouseButton(button ebiten.MouseButton) Binding {
+	return Binding{Type: INPUT_MOUSE_BUTTON, Code: int(button), Scale: 1}
+}
+
+func GamepadButton(button ebiten.StandardGamepadButton) Binding {
+	return Binding{Type: INPUT_GAMEPAD_BUTTON, Code: int(button), Scale: 1}
+}
+
+func GamepadAxis(axis ebiten.StandardGamepadAxis) Binding {
+	return Binding{Type: INPUT_GAMEPAD_AXIS, Code: int(axis), Scale: 1}
+}
+
+func (b Binding) WithScale(scale float64) Binding {
+	b.Scale = scale
+	return b
+}
+
+// Same Binding giving negative values (eg: the "left" side of an axis Action)
+func (b Binding) Negative() Binding {
+	b.Scale = -math.Abs(b.Scale)
+	return b
+}
+
+// Same device and button (or axis), Scale is ignored
+func (b Binding) Matches(other Binding) bool {
+	return b.Type == other.Type && b.Code == other.Code
+}
+
+// Matches and gives values of the same sign (eg: the left side of an axis isn't the same as the right one)
+func (b Binding) MatchesDirection(other Binding) bool {
+	return b.Matches(other) && (b.Scale < 0) == (other.Scale < 0)
+}
+
+// Current value from source, deadzone applies to axes
+func (b Binding) read(source Source, deadzone float64) float64 {
+	pressed := false
+	switch b.Type {
+	case INPUT_KEY:
+		pressed = source.IsKeyPressed(ebiten.Key(b.Code))
+	case INPUT_MOUSE_BUTTON:
+		pressed = source.IsMouseButtonPressed(ebiten.MouseButton(b.Code))
+	case INPUT_GAMEPAD_BUTTON:
+		pressed = source.IsGamepadButtonPressed(ebiten.StandardGamepadButton(b.Code))
+	case INPUT_GAMEPAD_AXIS:
+		v := source.GetGamepadAxis(ebiten.StandardGamepadAxis(b.Code))
+		if math.Abs(v) < deadzone {
+			return 0
+		}
+		return v * b.Scale
+	}
+	if pressed {
+		return b.Scale
+	}
+	return 0
+}
+
+func (b Binding) String() string {
+	switch b.Type {
+	case INPUT_KEY:
+		return ebiten.Key(b.Code).String()
+	case INPUT_MOUSE_BUTTON:
+		return fmt.Sprintf("Mouse%d", b.Code)
+	case INPUT_GAMEPAD_BUTTON:
+		return fmt.Sprintf("GamepadButton%d", b.Code)
+	case INPUT_GAMEPAD_AXIS:
+		return fmt.Sprintf("GamepadAxis%d", b.Code)
+	}
+	return b.Type
+}
+
+// Keys are saved by name ("ArrowUp"), everything else by code
+type bindingJSON struct {
+	Type  string  `json:"type"`
+	Key   string  `json:"key,omitempty"`
+	Code  int     `json:"code,omitempty"`
+	Scale float64 `json:"scale,omitempty"`
+}
+
+func (b Binding) MarshalJSON() ([]byte, error) {
+	out := bindingJSON{Type: b.Type, Code: b.Code, Scale: b.Scale}
+	if b.Type == INPUT_KEY {
+		out.Key, out.Code = ebiten.Key(b.Code).String(), 0
+	}
+	if out.Scale == 1 {
+		out.Scale = 0
+	}
+	return json.Marshal(out)
+}
+
+func (b *Binding) UnmarshalJSON(data []byte) error {
+	var in bindingJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*b = Binding{Type: in.Type, Code: in.Code, Scale: in.Scale}
+	if b.Scale == 0 {
+		b.Scale = 1
+	}
+	switch in.Type {
+	case INPUT_KEY:
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(in.Key)); err != nil {
+			return err
+		}
+		b.Code = int(key)
+	case INPUT_MOUSE_BUTTON, INPUT_GAMEPAD_BUTTON, INPUT_GAMEPAD_AXIS:
+	default:
+		return fmt.Errorf("input: unknown binding type %q", in.Type)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+)
+
+type actionConfig struct {
+	Name     string    `json:"name"`
+	Bindings []Binding `json:"bindings"`
+}
+
+type config struct {
+	Deadzone  float64        `json:"deadzone"`
+	Threshold float64        `json:"threshold"`
+	Actions   []actionConfig `json:"actions"`
+}
+
+// Bindings, Deadzone and Threshold as JSON
+func (m *Map) MarshalJSON() ([]byte, error) {
+	c := config{Deadzone: m.Deadzone, Threshold: m.Threshold}
+	for _, name := range m.order {
+		c.Actions = append(c.Actions, actionConfig{Name: name, Bindings: m.actions[name].Bindings})
+	}
+	return json.MarshalIndent(c, "", "  ")
+}
+
+/*
+Applies a JSON config (see MarshalJSON), actions in it replace their current bindings
+Actions missing from it keep theirs, so defaults of actions added after the config was saved still work
+*/
+func (m *Map) UnmarshalJSON(data []byte) error {
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+	if c.Deadzone > 0 {
+		m.Deadzone = c.Deadzone
+	}
+	if c.Threshold > 0 {
+		m.Threshold = c.Threshold
+	}
+	if m.actions == nil {
+		m.actions = map[string]*Action{}
+	}
+	for _, ac := range c.Actions {
+		m.getOrAdd(ac.Name).Bindings = ac.Bindings
+	}
+	return nil
+}
+
+// Saves bindings to a JSON file
+func (m *Map) Save(path string) error {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Loads bindings saved with Save, a missing file returns an error matching os.ErrNotExist (defaults are kept)
+func (m *Map) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return m.UnmarshalJSON(data)
+}
+
+// Same as Load but from a file system (eg: embed.FS)
+func (m *Map) LoadFS(fsys fs.FS, path string) error {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	return m.UnmarshalJSON(data)
+}
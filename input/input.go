@@ -0,0 +1,210 @@
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Named action (eg: "jump", "move_x") and its Bindings
+type Action struct {
+	Name     string
+	Bindings []Binding
+
+	value    float64
+	previous float64
+	held     int // Ticks pressed in a row
+	lastHeld int // Ticks it was held before being released
+}
+
+/*
+Map turns raw input from its Source into named Actions, Update it once per tick before game logic
+Values of all bindings of an Action are added up and clamped to -1..1,
+an Action is pressed while its value is at least Threshold (either direction)
+*/
+type Map struct {
+	Source    Source
+	Deadzone  float64 // Gamepad axis values below it count as 0
+	Threshold float64
+
+	actions map[string]*Action
+	order   []string
+}
+
+func New() *Map {
+	return NewWithSource(&EbitenSource{})
+}
+
+func NewWithSource(source Source) *Map {
+	return &Map{
+		Source:    source,
+		Deadzone:  0.2,
+		Threshold: 0.5,
+		actions:   map[string]*Action{},
+	}
+}
+
+func (m *Map) getOrAdd(name string) *Action {
+	a, ok := m.actions[name]
+	if !ok {
+		a = &Action{Name: name}
+		m.actions[name] = a
+		m.order = append(m.order, name)
+	}
+	return a
+}
+
+// Adds bindings to an action (created if needed)
+func (m *Map) Bind(action string, bindings ...Binding) {
+	a := m.getOrAdd(action)
+	a.Bindings = append(a.Bindings, bindings...)
+}
+
+// Removes every binding of an action matching binding (Scale is ignored)
+func (m *Map) Unbind(action string, binding Binding) {
+	m.unbind(action, binding.Matches)
+}
+
+func (m *Map) unbind(action string, matches func(Binding) bool) {
+	a, ok := m.actions[action]
+	if !ok {
+		return
+	}
+	kept := a.Bindings[:0]
+	for _, b := range a.Bindings {
+		if !matches(b) {
+			kept = append(kept, b)
+		}
+	}
+	a.Bindings = kept
+}
+
+/*
+Replaces binding index of an action (appended if index is out of range), eg: from a rebinding menu
+The same button is removed from other actions, so one press doesn't trigger two of them
+(only in the same direction for axes, see Binding.MatchesDirection)
+*/
+func (m *Map) Rebind(action string, index int, binding Binding) {
+	for _, name := range m.order {
+		if name != action {
+			m.unbind(name, binding.MatchesDirection)
+		}
+	}
+	a := m.getOrAdd(action)
+	if index < 0 || index >= len(a.Bindings) {
+		a.Bindings = append(a.Bindings, binding)
+		return
+	}
+	a.Bindings[index] = binding
+}
+
+// Removes all bindings of an action (it stays defined)
+func (m *Map) ClearBindings(action string) {
+	if a, ok := m.actions[action]; ok {
+		a.Bindings = nil
+	}
+}
+
+func (m *Map) GetBindings(action string) []Binding {
+	if a, ok := m.actions[action]; ok {
+		return a.Bindings
+	}
+	return nil
+}
+
+// Names of all actions, in the order they were added
+func (m *Map) GetActions() []string {
+	return m.order
+}
+
+// Reads the Source and updates every Action, call it once per tick
+func (m *Map) Update() error {
+	if s, ok := m.Source.(interface{ Update() }); ok {
+		s.Update()
+	}
+	for _, name := range m.order {
+		a := m.actions[name]
+		value := 0.0
+		for _, b := range a.Bindings {
+			value += b.read(m.Source, m.Deadzone)
+		}
+		m.SetValue(name, value)
+	}
+	return nil
+}
+
+/*
+Sets the value of an action for this tick (clamped to -1..1), as if it was read from the Source
+Update calls it for every action, it's also handy to drive actions from code (eg: touch controls)
+*/
+func (m *Map) SetValue(action string, value float64) {
+	a := m.getOrAdd(action)
+	a.previous = a.value
+	a.value = math.Max(-1, math.Min(1, value))
+	if math.Abs(a.value) >= m.Threshold {
+		a.held++
+	} else if a.held > 0 {
+		a.lastHeld = a.held
+		a.held = 0
+	}
+}
+
+// -1..1 (0 for unknown actions)
+func (m *Map) GetValue(action string) float64 {
+	if a, ok := m.actions[action]; ok {
+		return a.value
+	}
+	return 0
+}
+
+func (m *Map) IsPressed(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.held > 0
+}
+
+// Pressed this tick but not the previous one
+func (m *Map) IsJustPressed(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.held == 1
+}
+
+// Released this tick
+func (m *Map) IsJustReleased(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.held == 0 && math.Abs(a.previous) >= m.Threshold
+}
+
+// Ticks the action has been held for, 0 if it isn't pressed
+func (m *Map) GetHeldTicks(action string) int {
+	if a, ok := m.actions[action]; ok {
+		return a.held
+	}
+	return 0
+}
+
+// Seconds the action has been held for, 0 if it isn't pressed
+func (m *Map) GetHeldDuration(action string) float64 {
+	return float64(m.GetHeldTicks(action)) / float64(ebiten.TPS())
+}
+
+// Seconds the action was held before its last release (eg: for charged jumps)
+func (m *Map) GetReleasedDuration(action string) float64 {
+	if a, ok := m.actions[action]; ok {
+		return float64(a.lastHeld) / float64(ebiten.TPS())
+	}
+	return 0
+}
+
+func (m *Map) GetCursorPosition() (x, y float64) {
+	return m.Source.GetCursorPosition()
+}
+
+// First input pressed this tick (see EbitenSource.Capture), false if nothing was or the Source can't capture
+func (m *Map) Capture() (Binding, bool) {
+	if s, ok := m.Source.(interface {
+		Capture(threshold float64) (Binding, bool)
+	}); ok {
+		return s.Capture(m.Threshold)
+	}
+	return Binding{}, false
+}
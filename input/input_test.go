@@ -0,0 +1,41 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestRebind(t *testing.T) {
+	m := NewWithSource(nil)
+	m.Bind("move_x", stickX, stickX.Negative(), Key(ebiten.KeyD))
+	m.Bind("dodge", Key(ebiten.KeySpace))
+
+	// Taking the left side of the axis leaves the right one
+	m.Rebind("look_left", 0, stickX.Negative())
+	want := []Binding{stickX, Key(ebiten.KeyD)}
+	if got := m.GetBindings("move_x"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("move_x bindings %v, want %v", got, want)
+	}
+	if got := m.GetBindings("look_left"); !reflect.DeepEqual(got, []Binding{stickX.Negative()}) {
+		t.Fatalf("look_left bindings %v", got)
+	}
+
+	// Only the sign of Scale matters
+	m.Rebind("dodge", 0, Key(ebiten.KeyD).WithScale(0.5))
+	want = []Binding{stickX}
+	if got := m.GetBindings("move_x"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("move_x bindings %v, want %v", got, want)
+	}
+	if got := m.GetBindings("dodge"); !reflect.DeepEqual(got, []Binding{Key(ebiten.KeyD).WithScale(0.5)}) {
+		t.Fatalf("dodge bindings %v, want D replacing space", got)
+	}
+
+	// Unbind still removes both directions
+	m.Bind("move_x", stickX.Negative())
+	m.Unbind("move_x", stickX)
+	if got := m.GetBindings("move_x"); len(got) != 0 {
+		t.Fatalf("move_x bindings %v after Unbind, want none", got)
+	}
+}
@@ -0,0 +1,357 @@
+package input
+
+import (
+	"encoding/json"
+	"io/fs"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+)
+
+// An input changed value at Tick (buttons are 1 or 0, axes -1..1, cursor moves have x in Value)
+type Event struct {
+	Tick  int     `json:"tick"`
+	Type  string  `json:"type"` // INPUT_KEY, INPUT_MOUSE_BUTTON, INPUT_GAMEPAD_BUTTON, INPUT_GAMEPAD_AXIS or INPUT_CURSOR
+	Code  int     `json:"code,omitempty"`
+	Value float64 `json:"value"`
+	Y     float64 `json:"y,omitempty"`
+}
+
+/*
+Recording is per tick input, only changes of inputs that were read are stored
+Only reads through a Map's Source (actions, GetCursorPosition, Capture) are recorded, ebiten read directly
+(eg: ui.Input.Read for typed characters and the wheel) isn't, drive the UI with UpdateWithInput to replay it
+Seed is free for the game to store its random seed, so a replay is fully deterministic
+Recordings can also be scripted (see Press, Release, Hold) to feed input into tests without a window
+*/
+type Recording struct {
+	TPS    int     `json:"tps"`
+	Ticks  int     `json:"ticks"`
+	Seed   int64   `json:"seed"`
+	Events []Event `json:"events"`
+}
+
+func NewRecording() *Recording {
+	return &Recording{TPS: ebiten.TPS()}
+}
+
+// Adds an event keeping Events sorted by Tick (events added for the same tick keep their order)
+func (r *Recording) Add(e Event) {
+	i := sort.Search(len(r.Events), func(i int) bool { return r.Events[i].Tick > e.Tick })
+	r.Events = append(r.Events, Event{})
+	copy(r.Events[i+1:], r.Events[i:])
+	r.Events[i] = e
+	if e.Tick >= r.Ticks {
+		r.Ticks = e.Tick + 1
+	}
+}
+
+// Scripts a button of binding going down at tick
+func (r *Recording) Press(tick int, binding Binding) {
+	r.Add(Event{Tick: tick, Type: binding.Type, Code: binding.Code, Value: 1})
+}
+
+// Scripts a button of binding going up at tick
+func (r *Recording) Release(tick int, binding Binding) {
+	r.Add(Event{Tick: tick, Type: binding.Type, Code: binding.Code, Value: 0})
+}
+
+// Scripts a button held for ticks starting at tick
+func (r *Recording) Hold(tick, ticks int, binding Binding) {
+	r.Press(tick, binding)
+	r.Release(tick+ticks, binding)
+}
+
+// Scripts a gamepad axis moving to value at tick
+func (r *Recording) SetAxis(tick int, axis ebiten.StandardGamepadAxis, value float64) {
+	r.Add(Event{Tick: tick, Type: INPUT_GAMEPAD_AXIS, Code: int(axis), Value: value})
+}
+
+// Scripts the cursor moving to x, y at tick
+func (r *Recording) SetCursor(tick int, x, y float64) {
+	r.Add(Event{Tick: tick, Type: INPUT_CURSOR, Value: x, Y: y})
+}
+
+func (r *Recording) Save(path string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRecording(data)
+}
+
+// Same as LoadRecording but from a file system (eg: embed.FS for test fixtures)
+func LoadRecordingFS(fsys fs.FS, path string) (*Recording, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRecording(data)
+}
+
+func ParseRecording(data []byte) (*Recording, error) {
+	r := &Recording{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(r.Events, func(i, j int) bool { return r.Events[i].Tick < r.Events[j].Tick })
+	return r, nil
+}
+
+type inputKey struct {
+	kind string
+	code int
+}
+
+func boolValue(pressed bool) float64 {
+	if pressed {
+		return 1
+	}
+	return 0
+}
+
+/***************** RECORDING *********************/
+
+/*
+Recorder is a Source wrapping another one, every input read through it is recorded when its value changes
+Update (called by Map.Update) starts a new tick, Capture (Map.Capture) records the captured input
+*/
+type Recorder struct {
+	Source    Source
+	Recording *Recording
+
+	tick int
+	last map[inputKey]Event
+}
+
+func NewRecorder(source Source) *Recorder {
+	return &Recorder{
+		Source:    source,
+		Recording: NewRecording(),
+		tick:      -1,
+		last:      map[inputKey]Event{},
+	}
+}
+
+func (r *Recorder) Update() {
+	if s, ok := r.Source.(interface{ Update() }); ok {
+		s.Update()
+	}
+	r.tick++
+	r.Recording.Ticks = r.tick + 1
+}
+
+func (r *Recorder) record(kind string, code int, value, y float64) {
+	k := inputKey{kind, code}
+	if last, ok := r.last[k]; ok && last.Value == value && last.Y == y {
+		return
+	}
+	e := Event{Tick: r.tick, Type: kind, Code: code, Value: value, Y: y}
+	r.last[k] = e
+	r.Recording.Events = append(r.Recording.Events, e)
+}
+
+func (r *Recorder) IsKeyPressed(key ebiten.Key) bool {
+	pressed := r.Source.IsKeyPressed(key)
+	r.record(INPUT_KEY, int(key), boolValue(pressed), 0)
+	return pressed
+}
+
+func (r *Recorder) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	pressed := r.Source.IsMouseButtonPressed(button)
+	r.record(INPUT_MOUSE_BUTTON, int(button), boolValue(pressed), 0)
+	return pressed
+}
+
+func (r *Recorder) IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool {
+	pressed := r.Source.IsGamepadButtonPressed(button)
+	r.record(INPUT_GAMEPAD_BUTTON, int(button), boolValue(pressed), 0)
+	return pressed
+}
+
+func (r *Recorder) GetGamepadAxis(axis ebiten.StandardGamepadAxis) float64 {
+	value := r.Source.GetGamepadAxis(axis)
+	r.record(INPUT_GAMEPAD_AXIS, int(axis), value, 0)
+	return value
+}
+
+func (r *Recorder) GetCursorPosition() (x, y float64) {
+	x, y = r.Source.GetCursorPosition()
+	r.record(INPUT_CURSOR, 0, x, y)
+	return x, y
+}
+
+// Captures through the wrapped Source (if it can), so Playback.Capture finds the same input
+func (r *Recorder) Capture(threshold float64) (Binding, bool) {
+	s, ok := r.Source.(interface {
+		Capture(threshold float64) (Binding, bool)
+	})
+	if !ok {
+		return Binding{}, false
+	}
+	b, ok := s.Capture(threshold)
+	if !ok {
+		return b, false
+	}
+	// Buttons are just pressed, so they were up last tick even if that wasn't read
+	k := inputKey{b.Type, b.Code}
+	if last, seen := r.last[k]; seen && b.Type != INPUT_GAMEPAD_AXIS && last.Value != 0 && last.Tick < r.tick-1 {
+		e := Event{Tick: r.tick - 1, Type: b.Type, Code: b.Code}
+		r.last[k] = e
+		r.Recording.Add(e)
+	}
+	b.read(r, 0)
+	return b, true
+}
+
+/***************** PLAYBACK *********************/
+
+/*
+Playback is a Source replaying a Recording, inputs keep their last recorded value
+(inputs never recorded are released), Update (called by Map.Update) advances one tick
+*/
+type Playback struct {
+	Recording *Recording
+
+	tick    int
+	next    int // Index of the next Event to apply
+	state   map[inputKey]Event
+	pressed []inputKey // Buttons that went down this tick, for Capture
+}
+
+func NewPlayback(recording *Recording) *Playback {
+	return &Playback{
+		Recording: recording,
+		tick:      -1,
+		state:     map[inputKey]Event{},
+	}
+}
+
+func (p *Playback) Update() {
+	p.tick++
+	p.pressed = p.pressed[:0]
+	events := p.Recording.Events
+	for p.next < len(events) && events[p.next].Tick <= p.tick {
+		e := events[p.next]
+		k := inputKey{e.Type, e.Code}
+		if e.Type != INPUT_GAMEPAD_AXIS && e.Type != INPUT_CURSOR && p.state[k].Value == 0 && e.Value != 0 {
+			p.pressed = append(p.pressed, k)
+		}
+		p.state[k] = e
+		p.next++
+	}
+}
+
+// Current tick, -1 before the first Update
+func (p *Playback) GetTick() int {
+	return p.tick
+}
+
+// True once every tick of the Recording was played
+func (p *Playback) IsDone() bool {
+	return p.tick >= p.Recording.Ticks-1
+}
+
+// Plays the Recording again from its first tick
+func (p *Playback) Rewind() {
+	p.tick, p.next = -1, 0
+	p.state = map[inputKey]Event{}
+	p.pressed = p.pressed[:0]
+}
+
+func (p *Playback) value(kind string, code int) float64 {
+	return p.state[inputKey{kind, code}].Value
+}
+
+func (p *Playback) IsKeyPressed(key ebiten.Key) bool {
+	return p.value(INPUT_KEY, int(key)) != 0
+}
+
+func (p *Playback) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return p.value(INPUT_MOUSE_BUTTON, int(button)) != 0
+}
+
+func (p *Playback) IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool {
+	return p.value(INPUT_GAMEPAD_BUTTON, int(button)) != 0
+}
+
+func (p *Playback) GetGamepadAxis(axis ebiten.StandardGamepadAxis) float64 {
+	return p.value(INPUT_GAMEPAD_AXIS, int(axis))
+}
+
+func (p *Playback) GetCursorPosition() (x, y float64) {
+	e := p.state[inputKey{INPUT_CURSOR, 0}]
+	return e.Value, e.Y
+}
+
+/*
+Same order as EbitenSource.Capture: the lowest key, mouse button then gamepad button that went down this tick,
+else the lowest gamepad axis past threshold
+*/
+func (p *Playback) Capture(threshold float64) (Binding, bool) {
+	for _, kind := range []string{INPUT_KEY, INPUT_MOUSE_BUTTON, INPUT_GAMEPAD_BUTTON} {
+		code := -1
+		for _, k := range p.pressed {
+			if k.kind == kind && (code < 0 || k.code < code) {
+				code = k.code
+			}
+		}
+		if code >= 0 {
+			return Binding{Type: kind, Code: code, Scale: 1}, true
+		}
+	}
+	axis, value := -1, 0.0
+	for k, e := range p.state {
+		if k.kind == INPUT_GAMEPAD_AXIS && math.Abs(e.Value) >= threshold && (axis < 0 || k.code < axis) {
+			axis, value = k.code, e.Value
+		}
+	}
+	if axis >= 0 {
+		return GamepadAxis(ebiten.StandardGamepadAxis(axis)).WithScale(math.Copysign(1, value)), true
+	}
+	return Binding{}, false
+}
+
+/***************** MAP HELPERS *********************/
+
+// Starts recording everything the Map reads, until StopRecording
+func (m *Map) StartRecording() *Recorder {
+	r := NewRecorder(m.Source)
+	m.Source = r
+	return r
+}
+
+// Restores the recorded Source and returns the Recording, nil if the Map wasn't recording
+func (m *Map) StopRecording() *Recording {
+	r, ok := m.Source.(*Recorder)
+	if !ok {
+		return nil
+	}
+	m.Source = r.Source
+	return r.Recording
+}
+
+/*
+Replays a Recording instead of reading devices, restore the Source afterwards (eg: when Playback.IsDone)
+Action states are reset so the replay starts like the recording did
+*/
+func (m *Map) Play(recording *Recording) *Playback {
+	p := NewPlayback(recording)
+	m.Source = p
+	for _, a := range m.actions {
+		a.value, a.previous, a.held, a.lastHeld = 0, 0, 0, 0
+	}
+	return p
+}
@@ -0,0 +1,269 @@
+package input
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var stickX = GamepadAxis(ebiten.StandardGamepadAxisLeftStickHorizontal)
+
+func testMap(source Source) *Map {
+	m := NewWithSource(source)
+	m.Bind("jump", Key(ebiten.KeySpace), GamepadButton(ebiten.StandardGamepadButtonRightBottom))
+	m.Bind("move_x", stickX, Key(ebiten.KeyA).Negative(), Key(ebiten.KeyD))
+	m.Bind("fire", MouseButton(ebiten.MouseButtonLeft))
+	return m
+}
+
+// Fake devices, set directly by the test
+type fakeSource struct {
+	keys    map[ebiten.Key]bool
+	mouse   map[ebiten.MouseButton]bool
+	buttons map[ebiten.StandardGamepadButton]bool
+	axes    map[ebiten.StandardGamepadAxis]float64
+	x, y    float64
+	capture *Binding // Returned by the next Capture
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		keys:    map[ebiten.Key]bool{},
+		mouse:   map[ebiten.MouseButton]bool{},
+		buttons: map[ebiten.StandardGamepadButton]bool{},
+		axes:    map[ebiten.StandardGamepadAxis]float64{},
+	}
+}
+
+func (s *fakeSource) IsKeyPressed(key ebiten.Key) bool {
+	return s.keys[key]
+}
+
+func (s *fakeSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return s.mouse[button]
+}
+
+func (s *fakeSource) IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool {
+	return s.buttons[button]
+}
+
+func (s *fakeSource) GetGamepadAxis(axis ebiten.StandardGamepadAxis) float64 {
+	return s.axes[axis]
+}
+
+func (s *fakeSource) GetCursorPosition() (x, y float64) {
+	return s.x, s.y
+}
+
+func (s *fakeSource) Capture(threshold float64) (Binding, bool) {
+	b := s.capture
+	s.capture = nil
+	if b == nil {
+		return Binding{}, false
+	}
+	return *b, true
+}
+
+type actionState struct {
+	Pressed, JustPressed, JustReleased bool
+	Value                              float64
+}
+
+func getState(m *Map, action string) actionState {
+	return actionState{m.IsPressed(action), m.IsJustPressed(action), m.IsJustReleased(action), m.GetValue(action)}
+}
+
+func TestPlaybackScripted(t *testing.T) {
+	rec := NewRecording()
+	rec.Hold(1, 3, Key(ebiten.KeySpace))
+	rec.SetAxis(2, ebiten.StandardGamepadAxisLeftStickHorizontal, 0.8)
+	rec.Press(3, Key(ebiten.KeyA))
+	rec.SetAxis(5, ebiten.StandardGamepadAxisLeftStickHorizontal, 0.1) // Inside the deadzone
+	rec.Release(6, Key(ebiten.KeyA))
+	rec.SetCursor(2, 10, 20)
+	if rec.Ticks != 7 {
+		t.Fatalf("Ticks = %d, want 7", rec.Ticks)
+	}
+
+	// -0.2 (stick right, A held) is below Threshold
+	want := []struct {
+		jump actionState
+		move float64
+	}{
+		{actionState{false, false, false, 0}, 0},
+		{actionState{true, true, false, 1}, 0},
+		{actionState{true, false, false, 1}, 0.8},
+		{actionState{true, false, false, 1}, -0.2},
+		{actionState{false, false, true, 0}, -0.2},
+		{actionState{false, false, false, 0}, -1},
+		{actionState{false, false, false, 0}, 0},
+	}
+
+	p := NewPlayback(rec)
+	m := testMap(p)
+	for tick, w := range want {
+		if p.IsDone() {
+			t.Fatalf("done before tick %d", tick)
+		}
+		m.Update()
+		if p.GetTick() != tick {
+			t.Fatalf("GetTick() = %d, want %d", p.GetTick(), tick)
+		}
+		if got := getState(m, "jump"); got != w.jump {
+			t.Fatalf("tick %d: jump %+v, want %+v", tick, got, w.jump)
+		}
+		if got := m.GetValue("move_x"); math.Abs(got-w.move) > 1e-9 {
+			t.Fatalf("tick %d: move_x %v, want %v", tick, got, w.move)
+		}
+		if pressed := m.IsPressed("move_x"); pressed != (math.Abs(w.move) >= m.Threshold) {
+			t.Fatalf("tick %d: move_x pressed %v at %v", tick, pressed, w.move)
+		}
+	}
+	if !p.IsDone() {
+		t.Fatal("not done after the last tick")
+	}
+	if x, y := m.GetCursorPosition(); x != 10 || y != 20 {
+		t.Fatalf("cursor %v,%v, want 10,20", x, y)
+	}
+
+	// Rewind plays it again from the start
+	p.Rewind()
+	m.Update()
+	m.Update()
+	if !m.IsJustPressed("jump") {
+		t.Fatal("jump not pressed again after Rewind")
+	}
+}
+
+func TestRecordSaveParsePlayback(t *testing.T) {
+	// Changes of the fake devices at each tick
+	script := []func(s *fakeSource){
+		func(s *fakeSource) {},
+		func(s *fakeSource) { s.keys[ebiten.KeySpace] = true },
+		func(s *fakeSource) { s.axes[ebiten.StandardGamepadAxisLeftStickHorizontal] = -0.7 },
+		func(s *fakeSource) { s.keys[ebiten.KeySpace], s.mouse[ebiten.MouseButtonLeft] = false, true },
+		func(s *fakeSource) { s.keys[ebiten.KeyD] = true },
+		func(s *fakeSource) { s.buttons[ebiten.StandardGamepadButtonRightBottom] = true },
+		func(s *fakeSource) {
+			s.axes[ebiten.StandardGamepadAxisLeftStickHorizontal] = 0
+			s.mouse[ebiten.MouseButtonLeft] = false
+			s.x, s.y = 5, 6
+		},
+		func(s *fakeSource) { s.buttons[ebiten.StandardGamepadButtonRightBottom] = false },
+		func(s *fakeSource) {},
+	}
+	actions := []string{"jump", "move_x", "fire"}
+
+	fake := newFakeSource()
+	m := testMap(fake)
+	recorder := m.StartRecording()
+	recorder.Recording.Seed = 42
+	var recorded [][]actionState
+	for _, change := range script {
+		change(fake)
+		m.Update()
+		m.GetCursorPosition() // Only inputs that are read get recorded
+		states := make([]actionState, len(actions))
+		for i, a := range actions {
+			states[i] = getState(m, a)
+		}
+		recorded = append(recorded, states)
+	}
+	rec := m.StopRecording()
+	if rec != recorder.Recording || m.Source != Source(fake) {
+		t.Fatal("StopRecording() didn't restore the Source")
+	}
+	if rec.Ticks != len(script) {
+		t.Fatalf("Ticks = %d, want %d", rec.Ticks, len(script))
+	}
+
+	path := filepath.Join(t.TempDir(), "replay.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseRecording(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, rec) {
+		t.Fatalf("parsed recording %+v, want %+v", parsed, rec)
+	}
+
+	p := NewPlayback(parsed)
+	replay := testMap(p)
+	for tick, want := range recorded {
+		replay.Update()
+		for i, a := range actions {
+			if got := getState(replay, a); got != want[i] {
+				t.Fatalf("tick %d: %s %+v, want %+v as recorded", tick, a, got, want[i])
+			}
+		}
+	}
+	if !p.IsDone() {
+		t.Fatal("playback not done after every recorded tick")
+	}
+	if x, y := replay.GetCursorPosition(); x != 5 || y != 6 {
+		t.Fatalf("cursor %v,%v, want 5,6", x, y)
+	}
+}
+
+func TestRecordCapture(t *testing.T) {
+	keyB, stickLeft := Key(ebiten.KeyB), stickX.WithScale(-1)
+	// Captures at ticks 1, 3 (B again, released at 2 without being read) and 5
+	script := []func(s *fakeSource){
+		func(s *fakeSource) {},
+		func(s *fakeSource) { s.keys[ebiten.KeyB], s.capture = true, &keyB },
+		func(s *fakeSource) { s.keys[ebiten.KeyB] = false },
+		func(s *fakeSource) { s.keys[ebiten.KeyB], s.capture = true, &keyB },
+		func(s *fakeSource) {},
+		func(s *fakeSource) {
+			s.axes[ebiten.StandardGamepadAxisLeftStickHorizontal], s.capture = -0.9, &stickLeft
+		},
+		// Still pushed, so it's captured again (like EbitenSource does)
+		func(s *fakeSource) { s.capture = &stickLeft },
+	}
+
+	fake := newFakeSource()
+	m := NewWithSource(fake) // Nothing bound, only Capture reads B
+	m.StartRecording()
+	var captured []Binding
+	for tick, change := range script {
+		change(fake)
+		m.Update()
+		if tick == 2 {
+			continue // B released, but not read
+		}
+		b, _ := m.Capture()
+		captured = append(captured, b)
+	}
+	rec := m.StopRecording()
+	want := []Binding{{}, keyB, keyB, {}, stickLeft, stickLeft}
+	if !reflect.DeepEqual(captured, want) {
+		t.Fatalf("captured %v, want %v", captured, want)
+	}
+
+	p := m.Play(rec)
+	var replayed []Binding
+	for tick := range script {
+		m.Update()
+		if tick == 2 {
+			continue
+		}
+		b, _ := m.Capture()
+		replayed = append(replayed, b)
+	}
+	if !reflect.DeepEqual(replayed, want) {
+		t.Fatalf("replayed captures %v, want %v", replayed, want)
+	}
+	if !p.IsDone() {
+		t.Fatal("playback not done")
+	}
+}
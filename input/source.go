@@ -0,0 +1,91 @@
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+/*
+Source is where a Map reads raw input from, EbitenSource reads the real devices
+Other sources can feed scripted or recorded input without a window
+Gamepad queries combine every connected gamepad with a standard layout
+*/
+type Source interface {
+	IsKeyPressed(key ebiten.Key) bool
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool
+	GetGamepadAxis(axis ebiten.StandardGamepadAxis) float64
+	GetCursorPosition() (x, y float64)
+}
+
+// Reads input from ebiten, call Update once per tick before the Map reads it
+type EbitenSource struct {
+	gamepadIDs []ebiten.GamepadID
+}
+
+func (s *EbitenSource) Update() {
+	s.gamepadIDs = ebiten.AppendGamepadIDs(s.gamepadIDs[:0])
+}
+
+func (s *EbitenSource) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (s *EbitenSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+func (s *EbitenSource) IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool {
+	for _, id := range s.gamepadIDs {
+		if ebiten.IsStandardGamepadButtonPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value of the gamepad pushed furthest
+func (s *EbitenSource) GetGamepadAxis(axis ebiten.StandardGamepadAxis) float64 {
+	value := 0.0
+	for _, id := range s.gamepadIDs {
+		if v := ebiten.StandardGamepadAxisValue(id, axis); math.Abs(v) > math.Abs(value) {
+			value = v
+		}
+	}
+	return value
+}
+
+func (s *EbitenSource) GetCursorPosition() (x, y float64) {
+	cx, cy := ebiten.CursorPosition()
+	return float64(cx), float64(cy)
+}
+
+/*
+First key, mouse button or gamepad button pressed this tick, for "press a key" rebinding menus
+Gamepad axes pushed past threshold are captured too (with a Scale of -1 or 1 for their direction)
+*/
+func (s *EbitenSource) Capture(threshold float64) (Binding, bool) {
+	if keys := inpututil.AppendJustPressedKeys(nil); len(keys) > 0 {
+		return Key(keys[0]), true
+	}
+	for b := ebiten.MouseButton0; b <= ebiten.MouseButtonMax; b++ {
+		if inpututil.IsMouseButtonJustPressed(b) {
+			return MouseButton(b), true
+		}
+	}
+	for _, id := range s.gamepadIDs {
+		for b := ebiten.StandardGamepadButton(0); b <= ebiten.StandardGamepadButtonMax; b++ {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, b) {
+				return GamepadButton(b), true
+			}
+		}
+		for a := ebiten.StandardGamepadAxis(0); a <= ebiten.StandardGamepadAxisMax; a++ {
+			if v := ebiten.StandardGamepadAxisValue(id, a); math.Abs(v) >= threshold {
+				return GamepadAxis(a).WithScale(math.Copysign(1, v)), true
+			}
+		}
+	}
+	return Binding{}, false
+}
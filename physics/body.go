@@ -0,0 +1,146 @@
+package physics
+
+import (
+	"github.com/shubhamdwivedii/gopher-engine/collision"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/math/f64"
+)
+
+/*
+Body is a rigid body (without rotation) attached to a collision.Collider
+Type is one of PHYSICS_BODY_STATIC, PHYSICS_BODY_KINEMATIC or PHYSICS_BODY_DYNAMIC
+Static bodies never move, Kinematic bodies move by their Velocity only (and push Dynamic bodies)
+*/
+type Body struct {
+	Type         string
+	Collider     *collision.Collider // Collider.Data is set to the Body
+	Velocity     f64.Vec2            // Pixels per second
+	Force        f64.Vec2            // Accumulated till the next step, then cleared
+	Restitution  float64             // Bounciness, 0 (none) to 1 (perfectly elastic)
+	Friction     float64             // Coulomb friction coefficient, combined as sqrt(a*b)
+	GravityScale float64
+	Damping      float64 // Fraction of velocity lost per second
+	CanSleep     bool
+	Sleeping     bool
+	Data         interface{} // Owner of the Body (eg: *Gopher, ecs.Entity)
+
+	mass    float64
+	invMass float64
+	idle    float64 // Seconds spent below World.SleepVelocity
+	world   *World
+	id      int
+}
+
+// mass is ignored for Static and Kinematic bodies
+func NewBody(bodyType string, shape collision.Shape, mass float64) *Body {
+	b := &Body{
+		Type:         bodyType,
+		Friction:     0.4,
+		GravityScale: 1,
+		CanSleep:     true,
+	}
+	b.Collider = collision.NewCollider(shape, b)
+	b.SetMass(mass)
+	return b
+}
+
+func NewStaticBody(shape collision.Shape) *Body {
+	return NewBody(PHYSICS_BODY_STATIC, shape, 0)
+}
+
+func NewKinematicBody(shape collision.Shape) *Body {
+	return NewBody(PHYSICS_BODY_KINEMATIC, shape, 0)
+}
+
+func NewDynamicBody(shape collision.Shape, mass float64) *Body {
+	return NewBody(PHYSICS_BODY_DYNAMIC, shape, mass)
+}
+
+func (b *Body) IsStatic() bool {
+	return b.Type == PHYSICS_BODY_STATIC
+}
+
+func (b *Body) IsKinematic() bool {
+	return b.Type == PHYSICS_BODY_KINEMATIC
+}
+
+func (b *Body) IsDynamic() bool {
+	return b.Type == PHYSICS_BODY_DYNAMIC
+}
+
+func (b *Body) GetMass() float64 {
+	return b.mass
+}
+
+// Only Dynamic bodies have a (finite) mass, a mass <= 0 defaults to 1
+func (b *Body) SetMass(mass float64) {
+	if !b.IsDynamic() {
+		b.mass, b.invMass = 0, 0
+		return
+	}
+	if mass <= 0 {
+		mass = 1
+	}
+	b.mass, b.invMass = mass, 1/mass
+}
+
+func (b *Body) GetShape() collision.Shape {
+	return b.Collider.Shape
+}
+
+// Center of the Body in World, can be passed to Camera.Update directly
+func (b *Body) GetPosition() f64.Vec2 {
+	return b.Collider.Shape.Center()
+}
+
+// Moves the Body so its center is at x, y
+func (b *Body) SetPosition(x, y float64) {
+	c := b.GetPosition()
+	b.MoveBy(x-c[0], y-c[1])
+}
+
+// Teleports the Body by dx, dy (keeps the Space in sync)
+func (b *Body) MoveBy(dx, dy float64) {
+	if b.world != nil {
+		b.world.Space.MoveBy(b.Collider, dx, dy)
+	} else {
+		b.Collider.Shape.MoveBy(dx, dy)
+	}
+	b.Wake()
+}
+
+func (b *Body) SetVelocity(vx, vy float64) {
+	b.Velocity = f64.Vec2{vx, vy}
+	b.Wake()
+}
+
+// Force in pixels*mass/second^2, applied over the next step
+func (b *Body) ApplyForce(fx, fy float64) {
+	if !b.IsDynamic() {
+		return
+	}
+	b.Force[0] += fx
+	b.Force[1] += fy
+	b.Wake()
+}
+
+// Instant change in momentum
+func (b *Body) ApplyImpulse(jx, jy float64) {
+	if !b.IsDynamic() {
+		return
+	}
+	b.Velocity[0] += jx * b.invMass
+	b.Velocity[1] += jy * b.invMass
+	b.Wake()
+}
+
+func (b *Body) Wake() {
+	b.Sleeping = false
+	b.idle = 0
+}
+
+func (b *Body) Sleep() {
+	b.Sleeping = true
+	b.Velocity = f64.Vec2{}
+	b.Force = f64.Vec2{}
+}
@@ -0,0 +1,269 @@
+package character
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/collision"
+	"golang.org/x/image/math/f64"
+)
+
+/*
+Controller is a kinematic platformer character moved against a collision.Space
+It is not a physics Body, it moves exactly as told and slides along whatever it hits
+Colliders on OneWayLayer only block from above (jump through platforms)
+*/
+type Controller struct {
+	Collider *collision.Collider
+	Space    *collision.Space
+	Velocity f64.Vec2 // Pixels per second
+
+	MoveSpeed       float64 // Max horizontal speed
+	Acceleration    float64 // Horizontal acceleration on ground (pixels/second^2)
+	AirAcceleration float64
+	Deceleration    float64 // When there is no horizontal input
+	Gravity         float64
+	MaxFallSpeed    float64
+	JumpVelocity    float64 // Initial upward speed of a jump
+	JumpCut         float64 // Upward speed is multiplied by this when jump is released early (variable jump height)
+	CoyoteTime      float64 // Seconds after walking off a ledge during which a jump is still allowed
+	JumpBuffer      float64 // Seconds a jump press is remembered before landing
+	MaxSlope        float64 // Steepest walkable slope in degrees
+	SnapDistance    float64 // Max distance to stick to the ground when walking down slopes
+	OneWayLayer     uint32
+
+	Grounded     bool
+	Ground       *collision.Collider // What the Controller is standing on (nil in air)
+	GroundNormal f64.Vec2
+
+	coyote     float64
+	buffer     float64
+	jumping    bool
+	dropping   float64 // Seconds left ignoring one-way platforms
+	groundLast f64.Vec2
+}
+
+// Controller with a box collider, x, y is TopLeft
+func New(space *collision.Space, x, y, width, height float64) *Controller {
+	return &Controller{
+		Collider:        collision.NewCollider(collision.NewAABB(x, y, width, height), nil),
+		Space:           space,
+		MoveSpeed:       160,
+		Acceleration:    1200,
+		AirAcceleration: 800,
+		Deceleration:    1600,
+		Gravity:         900,
+		MaxFallSpeed:    600,
+		JumpVelocity:    360,
+		JumpCut:         0.5,
+		CoyoteTime:      0.1,
+		JumpBuffer:      0.1,
+		MaxSlope:        50,
+		SnapDistance:    4,
+	}
+}
+
+// Center of the Controller in World, can be passed to Camera.Update directly
+func (c *Controller) GetPosition() f64.Vec2 {
+	return c.Collider.Shape.Center()
+}
+
+func (c *Controller) GetBounds() (x, y, width, height float64) {
+	b := c.Collider.Shape.Bounds()
+	return b.X, b.Y, b.W, b.H
+}
+
+func (c *Controller) move(dx, dy float64) {
+	c.Collider.Shape.MoveBy(dx, dy)
+	c.Space.Update(c.Collider) // No-op if the Collider isn't in the Space
+}
+
+// Falls through one-way platforms for a short while
+func (c *Controller) DropDown() {
+	if c.Grounded && c.Ground != nil && c.isOneWay(c.Ground) {
+		c.dropping = 0.2
+		c.Grounded, c.Ground = false, nil
+	}
+}
+
+func (c *Controller) isOneWay(other *collision.Collider) bool {
+	return c.OneWayLayer != 0 && other.Layer&c.OneWayLayer != 0
+}
+
+// Normal pointing up enough to stand on
+func (c *Controller) isWalkable(n f64.Vec2) bool {
+	return -n[1] >= math.Cos(c.MaxSlope*math.Pi/180)
+}
+
+/*
+Steps the Controller by one tick (1/ebiten.TPS())
+moveX is horizontal input in [-1, 1], jumpPressed is true on the tick jump was pressed, jumpHeld while it is held
+*/
+func (c *Controller) Update(moveX float64, jumpPressed, jumpHeld bool) error {
+	dt := 1 / float64(ebiten.TPS())
+
+	// Carried by moving platforms
+	if c.Grounded && c.Ground != nil {
+		center := c.Ground.Shape.Center()
+		c.move(center[0]-c.groundLast[0], center[1]-c.groundLast[1])
+	}
+
+	c.updateJump(dt, jumpPressed, jumpHeld)
+
+	// Horizontal
+	target := math.Max(-1, math.Min(1, moveX)) * c.MoveSpeed
+	accel := c.Acceleration
+	if !c.Grounded {
+		accel = c.AirAcceleration
+	}
+	if moveX == 0 {
+		accel = c.Deceleration
+	}
+	c.Velocity[0] = approach(c.Velocity[0], target, accel*dt)
+
+	// Vertical
+	c.Velocity[1] = math.Min(c.Velocity[1]+c.Gravity*dt, c.MaxFallSpeed)
+	if c.dropping > 0 {
+		c.dropping -= dt
+	}
+
+	wasGrounded := c.Grounded
+	c.Grounded, c.Ground = false, nil
+
+	c.moveX(c.Velocity[0] * dt)
+	c.moveY(c.Velocity[1] * dt)
+
+	// Stick to the ground when walking down slopes or over small steps
+	if wasGrounded && !c.Grounded && !c.jumping && c.Velocity[1] >= 0 {
+		c.snapDown()
+	}
+
+	if c.Grounded && c.Ground != nil {
+		c.groundLast = c.Ground.Shape.Center()
+	}
+	return nil
+}
+
+func (c *Controller) updateJump(dt float64, jumpPressed, jumpHeld bool) {
+	if c.Grounded {
+		c.coyote = c.CoyoteTime
+	} else {
+		c.coyote -= dt
+	}
+	if jumpPressed {
+		c.buffer = c.JumpBuffer
+	} else {
+		c.buffer -= dt
+	}
+
+	if c.buffer > 0 && c.coyote > 0 {
+		c.Velocity[1] = -c.JumpVelocity
+		c.buffer, c.coyote = 0, 0
+		c.jumping = true
+		c.Grounded, c.Ground = false, nil
+	}
+
+	if c.jumping && c.Velocity[1] < 0 && !jumpHeld {
+		c.Velocity[1] *= c.JumpCut
+		c.jumping = false
+	}
+	if c.Velocity[1] >= 0 {
+		c.jumping = false
+	}
+}
+
+func (c *Controller) moveX(dx float64) {
+	if dx == 0 {
+		return
+	}
+	c.move(dx, 0)
+	for _, col := range c.Space.Check(c.Collider) {
+		if c.isOneWay(col.Other) {
+			continue
+		}
+		// Re-test, an earlier push may have already separated them
+		contact, ok := collision.Collide(c.Collider.Shape, col.Other.Shape)
+		if !ok {
+			continue
+		}
+		n := contact.Normal
+		if c.isWalkable(n) {
+			// Walk up slopes by pushing straight up
+			c.move(0, -contact.Depth/-n[1])
+			c.setGround(col.Other, n)
+			continue
+		}
+		mtv := contact.MTV()
+		c.move(mtv[0], mtv[1])
+		if n[0]*c.Velocity[0] < 0 {
+			c.Velocity[0] = 0
+		}
+	}
+}
+
+func (c *Controller) moveY(dy float64) {
+	prevBottom := c.Collider.Shape.Bounds().Bottom()
+	c.move(0, dy)
+	c.resolveY(prevBottom)
+}
+
+// prevBottom is the bottom edge before moving, for one-way platforms
+func (c *Controller) resolveY(prevBottom float64) {
+	for _, col := range c.Space.Check(c.Collider) {
+		if c.isOneWay(col.Other) && !c.canLandOn(col.Other, prevBottom) {
+			continue
+		}
+		contact, ok := collision.Collide(c.Collider.Shape, col.Other.Shape)
+		if !ok {
+			continue
+		}
+		n := contact.Normal
+		switch {
+		case c.isWalkable(n):
+			c.move(0, -contact.Depth/-n[1])
+			c.setGround(col.Other, n)
+			if c.Velocity[1] > 0 {
+				c.Velocity[1] = 0
+			}
+		case c.isOneWay(col.Other):
+			// Only the top of a one-way platform blocks
+		default:
+			mtv := contact.MTV()
+			c.move(mtv[0], mtv[1])
+			if n[1] > 0 && c.Velocity[1] < 0 {
+				c.Velocity[1] = 0 // Bumped head
+				c.jumping = false
+			}
+		}
+	}
+}
+
+// One-way platforms block only if we were above them before moving
+func (c *Controller) canLandOn(platform *collision.Collider, prevBottom float64) bool {
+	const eps = 0.5
+	return c.dropping <= 0 && c.Velocity[1] >= 0 && prevBottom <= platform.Shape.Bounds().Y+eps
+}
+
+func (c *Controller) snapDown() {
+	// Steeper slopes need a longer probe to stay in contact at full speed
+	probe := c.SnapDistance + math.Abs(c.Velocity[0])/float64(ebiten.TPS())*math.Tan(c.MaxSlope*math.Pi/180)
+	prevBottom := c.Collider.Shape.Bounds().Bottom()
+	c.move(0, probe)
+	c.resolveY(prevBottom)
+	if !c.Grounded {
+		c.move(0, -probe)
+	}
+}
+
+func (c *Controller) setGround(other *collision.Collider, n f64.Vec2) {
+	c.Grounded = true
+	c.Ground = other
+	c.GroundNormal = n
+}
+
+func approach(v, target, delta float64) float64 {
+	if v < target {
+		return math.Min(v+delta, target)
+	}
+	return math.Max(v-delta, target)
+}
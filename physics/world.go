@@ -0,0 +1,298 @@
+package physics
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/collision"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"golang.org/x/image/math/f64"
+)
+
+// Contact between two Bodies found during a step, Normal points from B towards A
+type Contact struct {
+	A, B    *Body
+	Contact collision.Contact
+
+	bounce         float64 // Separating speed wanted along the Normal (from Restitution)
+	normalImpulse  float64 // Impulses accumulated over the solver iterations of a step
+	tangentImpulse float64
+}
+
+/*
+World steps Bodies at a fixed rate, Update can be called once per tick (it uses 1/ebiten.TPS())
+Positions are stored in the Colliders' Shapes, so the Space is always up to date for queries
+*/
+type World struct {
+	Gravity        f64.Vec2 // Pixels per second^2
+	Space          *collision.Space
+	TimeStep       float64 // Fixed step in seconds, 0 means 1/ebiten.TPS()
+	Iterations     int     // Impulse solver iterations per step
+	MaxSteps       int     // Most steps per Advance, time past it is dropped (avoids a spiral of death), 0 means no limit
+	SleepVelocity  float64 // Bodies slower than this (pixels/second) ...
+	SleepTime      float64 // ... for this many seconds fall asleep
+	CorrectPercent float64 // Fraction of penetration corrected per step
+	Slop           float64 // Penetration allowed, avoids jitter of resting bodies
+
+	OnContact func(c Contact) // Called for every contact after solving
+
+	bodies      []*Body
+	contacts    []Contact
+	accumulator float64
+	nextID      int
+}
+
+func NewWorld(gravityX, gravityY, cellSize float64) *World {
+	return &World{
+		Gravity:        f64.Vec2{gravityX, gravityY},
+		Space:          collision.NewSpace(cellSize),
+		Iterations:     4,
+		MaxSteps:       8,
+		SleepVelocity:  4,
+		SleepTime:      0.5,
+		CorrectPercent: 0.8,
+		Slop:           0.05,
+	}
+}
+
+func (w *World) AddBody(b *Body) {
+	if b.world != nil {
+		return
+	}
+	w.nextID++
+	b.id = w.nextID
+	b.world = w
+	w.bodies = append(w.bodies, b)
+	w.Space.Add(b.Collider)
+}
+
+func (w *World) RemoveBody(b *Body) {
+	if b.world != w {
+		return
+	}
+	for i, other := range w.bodies {
+		if other == b {
+			w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+			break
+		}
+	}
+	w.Space.Remove(b.Collider)
+	b.world = nil
+}
+
+func (w *World) GetBodies() []*Body {
+	return w.bodies
+}
+
+// Contacts found during the last step
+func (w *World) GetContacts() []Contact {
+	return w.contacts
+}
+
+func (w *World) step() float64 {
+	if w.TimeStep > 0 {
+		return w.TimeStep
+	}
+	return 1 / float64(ebiten.TPS())
+}
+
+// Advances by one tick (1/ebiten.TPS()) in fixed steps of TimeStep
+func (w *World) Update() error {
+	w.Advance(1 / float64(ebiten.TPS()))
+	return nil
+}
+
+/*
+Advances by dt seconds in fixed steps of TimeStep, the remainder is kept for the next call
+At most MaxSteps are taken, if dt needs more (eg: after a long stall) the extra time is dropped
+*/
+func (w *World) Advance(dt float64) {
+	step := w.step()
+	w.accumulator += dt
+	for steps := 0; w.accumulator >= step; steps++ {
+		if w.MaxSteps > 0 && steps == w.MaxSteps {
+			w.accumulator = math.Mod(w.accumulator, step)
+			break
+		}
+		w.Step(step)
+		w.accumulator -= step
+	}
+}
+
+// Fraction of a step left in the accumulator, for interpolating drawn positions
+func (w *World) GetAlpha() float64 {
+	return w.accumulator / w.step()
+}
+
+// Single simulation step of dt seconds
+func (w *World) Step(dt float64) {
+	w.integrate(dt)
+	w.findContacts()
+
+	for i := range w.contacts {
+		w.prepareContact(&w.contacts[i])
+	}
+	for i := 0; i < w.Iterations; i++ {
+		for j := range w.contacts {
+			w.solveVelocity(&w.contacts[j])
+		}
+	}
+	for _, c := range w.contacts {
+		w.correctPosition(c)
+		if w.OnContact != nil {
+			w.OnContact(c)
+		}
+	}
+
+	w.updateSleep(dt)
+}
+
+func (w *World) integrate(dt float64) {
+	for _, b := range w.bodies {
+		if b.IsStatic() || b.Sleeping {
+			continue
+		}
+		if b.IsDynamic() {
+			b.Velocity[0] += (w.Gravity[0]*b.GravityScale + b.Force[0]*b.invMass) * dt
+			b.Velocity[1] += (w.Gravity[1]*b.GravityScale + b.Force[1]*b.invMass) * dt
+			if b.Damping > 0 {
+				d := math.Max(0, 1-b.Damping*dt)
+				b.Velocity[0] *= d
+				b.Velocity[1] *= d
+			}
+			b.Force = f64.Vec2{}
+		}
+		if b.Velocity == (f64.Vec2{}) {
+			continue
+		}
+		w.Space.MoveBy(b.Collider, b.Velocity[0]*dt, b.Velocity[1]*dt)
+
+		// Moving platforms wake up whatever is resting on them
+		if b.IsKinematic() {
+			for _, col := range w.Space.Check(b.Collider) {
+				if other, ok := col.Other.Data.(*Body); ok && other.Sleeping {
+					other.Wake()
+				}
+			}
+		}
+	}
+}
+
+// Narrowphase through the Space, every pair is reported once (A is always Dynamic)
+func (w *World) findContacts() {
+	w.contacts = w.contacts[:0]
+	for _, a := range w.bodies {
+		if !a.IsDynamic() || a.Sleeping {
+			continue
+		}
+		for _, col := range w.Space.Check(a.Collider) {
+			b, ok := col.Other.Data.(*Body)
+			if !ok {
+				// Plain Colliders in the Space act as Static geometry
+				b = &Body{Type: PHYSICS_BODY_STATIC, Collider: col.Other, Friction: a.Friction}
+			}
+			if b.IsDynamic() && !b.Sleeping && b.id < a.id {
+				continue // Already found from b's side
+			}
+			if b.IsDynamic() && b.Sleeping {
+				b.Wake()
+			}
+			w.contacts = append(w.contacts, Contact{A: a, B: b, Contact: col.Contact})
+		}
+	}
+}
+
+// Restitution is decided once per step, from the approach speed before solving
+func (w *World) prepareContact(c *Contact) {
+	n := c.Contact.Normal
+	vn := (c.A.Velocity[0]-c.B.Velocity[0])*n[0] + (c.A.Velocity[1]-c.B.Velocity[1])*n[1]
+
+	// No bounce for slow (resting) contacts, keeps stacks stable
+	if -vn >= w.SleepVelocity*2 {
+		c.bounce = -vn * math.Min(c.A.Restitution, c.B.Restitution)
+	}
+}
+
+/*
+Sequential impulses, each iteration adds to the impulses accumulated for the contact,
+the normal total never pulls (>= 0) and friction's total is clamped by Coulomb's law against it
+*/
+func (w *World) solveVelocity(c *Contact) {
+	a, b, n := c.A, c.B, c.Contact.Normal
+	invSum := a.invMass + b.invMass
+	if invSum == 0 {
+		return
+	}
+
+	rv := f64.Vec2{a.Velocity[0] - b.Velocity[0], a.Velocity[1] - b.Velocity[1]}
+	vn := rv[0]*n[0] + rv[1]*n[1]
+	total := math.Max(c.normalImpulse+(c.bounce-vn)/invSum, 0)
+	j := total - c.normalImpulse
+	c.normalImpulse = total
+	w.applyImpulse(a, b, f64.Vec2{n[0] * j, n[1] * j})
+
+	// Friction along the tangent
+	rv = f64.Vec2{a.Velocity[0] - b.Velocity[0], a.Velocity[1] - b.Velocity[1]}
+	t := f64.Vec2{-n[1], n[0]}
+	limit := c.normalImpulse * math.Sqrt(a.Friction*b.Friction)
+	total = math.Max(-limit, math.Min(limit, c.tangentImpulse-(rv[0]*t[0]+rv[1]*t[1])/invSum))
+	jt := total - c.tangentImpulse
+	c.tangentImpulse = total
+	w.applyImpulse(a, b, f64.Vec2{t[0] * jt, t[1] * jt})
+}
+
+func (w *World) applyImpulse(a, b *Body, j f64.Vec2) {
+	a.Velocity[0] += j[0] * a.invMass
+	a.Velocity[1] += j[1] * a.invMass
+	b.Velocity[0] -= j[0] * b.invMass
+	b.Velocity[1] -= j[1] * b.invMass
+}
+
+// Pushes bodies apart (by their inverse mass) so they don't sink into each other
+func (w *World) correctPosition(c Contact) {
+	a, b := c.A, c.B
+	invSum := a.invMass + b.invMass
+	if invSum == 0 {
+		return
+	}
+	depth := math.Max(c.Contact.Depth-w.Slop, 0) / invSum * w.CorrectPercent
+	if depth == 0 {
+		return
+	}
+	n := c.Contact.Normal
+	if a.invMass > 0 {
+		w.Space.MoveBy(a.Collider, n[0]*depth*a.invMass, n[1]*depth*a.invMass)
+	}
+	if b.invMass > 0 {
+		w.Space.MoveBy(b.Collider, -n[0]*depth*b.invMass, -n[1]*depth*b.invMass)
+	}
+}
+
+func (w *World) updateSleep(dt float64) {
+	for _, b := range w.bodies {
+		if !b.IsDynamic() || b.Sleeping || !b.CanSleep {
+			continue
+		}
+		if math.Hypot(b.Velocity[0], b.Velocity[1]) > w.SleepVelocity {
+			b.idle = 0
+			continue
+		}
+		b.idle += dt
+		if b.idle >= w.SleepTime {
+			b.Sleep()
+		}
+	}
+}
+
+// Draws Bodies' Shapes, Sleeping bodies in grey
+func (w *World) DebugDraw(screen scr.Screen, clr color.Color) {
+	for _, b := range w.bodies {
+		if b.Sleeping {
+			b.Collider.Shape.DebugDraw(screen, color.RGBA{128, 128, 128, 255})
+			continue
+		}
+		b.Collider.Shape.DebugDraw(screen, clr)
+	}
+}
@@ -0,0 +1,190 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shubhamdwivedii/gopher-engine/collision"
+)
+
+func TestAdvanceUsesFixedSteps(t *testing.T) {
+	w := NewWorld(0, 100, 32)
+	w.TimeStep = 0.25
+	ball := NewDynamicBody(collision.NewCircle(0, 0, 1), 1)
+	w.AddBody(ball)
+
+	// Gravity adds 100*0.25 per step
+	w.Advance(0.625)
+	if ball.Velocity[1] != 50 {
+		t.Fatalf("velocity %v after 2.5 step times, want 50 (2 steps)", ball.Velocity[1])
+	}
+	if a := w.GetAlpha(); math.Abs(a-0.5) > 1e-9 {
+		t.Fatalf("GetAlpha() = %v, want 0.5", a)
+	}
+
+	// Leftover time is carried to the next call
+	w.Advance(0.125)
+	if ball.Velocity[1] != 75 {
+		t.Fatalf("velocity %v after carrying the remainder, want 75", ball.Velocity[1])
+	}
+	if w.GetAlpha() != 0 {
+		t.Fatalf("GetAlpha() = %v, want 0", w.GetAlpha())
+	}
+
+	// Less than a step does nothing yet
+	w.Advance(0.1)
+	if ball.Velocity[1] != 75 {
+		t.Fatal("stepped before a full TimeStep was accumulated")
+	}
+
+	// A long stall only takes MaxSteps, the extra time is dropped
+	w.Advance(10)
+	if ball.Velocity[1] != 75+25*float64(w.MaxSteps) {
+		t.Fatalf("velocity %v after a stall, want %d steps", ball.Velocity[1], w.MaxSteps)
+	}
+	if a := w.GetAlpha(); a < 0 || a >= 1 {
+		t.Fatalf("GetAlpha() = %v after a stall, want less than a step", a)
+	}
+}
+
+func TestFrictionClampedByAccumulatedImpulse(t *testing.T) {
+	w := NewWorld(0, 600, 32)
+	w.TimeStep = 1.0 / 60
+	w.AddBody(NewStaticBody(collision.NewAABB(-500, 10, 1000, 10)))
+	// Two boxes stacked on the floor, sliding together
+	bottom := NewDynamicBody(collision.NewAABB(-5, 0.01, 10, 10), 1)
+	top := NewDynamicBody(collision.NewAABB(-5, -9.98, 10, 10), 1)
+	bottom.Velocity[0], top.Velocity[0] = 120, 120
+	w.AddBody(bottom)
+	w.AddBody(top)
+
+	mu := math.Sqrt(bottom.Friction * bottom.Friction)
+	for i := 0; i < 20; i++ {
+		before := bottom.Velocity[0] + top.Velocity[0]
+		w.Step(w.TimeStep)
+		for _, c := range w.GetContacts() {
+			if c.normalImpulse < 0 || math.Abs(c.tangentImpulse) > mu*c.normalImpulse+1e-9 {
+				t.Fatalf("step %d: normal impulse %v, friction %v", i, c.normalImpulse, c.tangentImpulse)
+			}
+		}
+		// Floor friction can't take more than mu * weight (2 * 600 * dt) per step
+		if lost := before - bottom.Velocity[0] - top.Velocity[0]; lost > mu*20+1e-6 {
+			t.Fatalf("step %d: lost %v momentum to friction, want at most %v", i, lost, mu*20)
+		}
+	}
+	if math.Abs(bottom.Velocity[0]-top.Velocity[0]) > 1e-6 {
+		t.Fatalf("stacked boxes slid apart, %v and %v", bottom.Velocity[0], top.Velocity[0])
+	}
+	// About mu*g*dt per step once resting (the first step only partly)
+	if want := 120 - mu*600*20/60; math.Abs(bottom.Velocity[0]-want) > 1 {
+		t.Fatalf("velocity %v after 20 steps, want about %v", bottom.Velocity[0], want)
+	}
+}
+
+// Ball moving down at speed onto a static floor, without gravity
+func bounce(t *testing.T, ballRestitution, floorRestitution, speed float64) float64 {
+	t.Helper()
+	w := NewWorld(0, 0, 32)
+	w.TimeStep = 1.0 / 60
+	floor := NewStaticBody(collision.NewAABB(-50, 6, 100, 10))
+	floor.Restitution = floorRestitution
+	ball := NewDynamicBody(collision.NewCircle(0, 0, 5), 1)
+	ball.Restitution = ballRestitution
+	ball.Velocity[1] = speed
+	w.AddBody(floor)
+	w.AddBody(ball)
+
+	for i := 0; i < 60 && ball.Velocity[1] > 0; i++ {
+		w.Step(w.TimeStep)
+	}
+	return ball.Velocity[1]
+}
+
+func TestRestitution(t *testing.T) {
+	tests := []struct {
+		name        string
+		ball, floor float64
+		speed, want float64
+	}{
+		{"elastic", 1, 1, 120, -120},
+		{"half", 0.5, 1, 120, -60},
+		{"least bouncy wins", 1, 0.25, 120, -30},
+		{"none", 0, 1, 120, 0},
+		// Slower than 2*SleepVelocity counts as resting and never bounces
+		{"resting contact", 1, 1, 6, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := bounce(t, tt.ball, tt.floor, tt.speed); math.Abs(v-tt.want) > 1e-6 {
+				t.Fatalf("velocity after bounce %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodySleepsAndWakes(t *testing.T) {
+	w := NewWorld(0, 400, 32)
+	w.TimeStep = 1.0 / 60
+	floor := NewStaticBody(collision.NewAABB(-50, 10, 100, 10))
+	box := NewDynamicBody(collision.NewAABB(-5, -20, 10, 10), 1)
+	w.AddBody(floor)
+	w.AddBody(box)
+
+	for i := 0; i < 180 && !box.Sleeping; i++ {
+		w.Step(w.TimeStep)
+	}
+	if !box.Sleeping {
+		t.Fatalf("box still awake after 3 seconds at rest, velocity %v", box.Velocity)
+	}
+	if bottom := box.GetShape().Bounds().Bottom(); bottom < 10 || bottom > 10+w.Slop+0.5 {
+		t.Fatalf("box rests at bottom %v, want on the floor at 10", bottom)
+	}
+
+	// Sleeping bodies don't fall
+	y := box.GetPosition()[1]
+	w.Advance(1)
+	if box.GetPosition()[1] != y || !box.Sleeping {
+		t.Fatal("sleeping box moved")
+	}
+
+	box.ApplyImpulse(0, -200)
+	if box.Sleeping {
+		t.Fatal("impulse didn't wake the box")
+	}
+	w.Step(w.TimeStep)
+	if box.GetPosition()[1] >= y {
+		t.Fatal("woken box didn't move")
+	}
+}
+
+func TestKinematicWakesSleepingBody(t *testing.T) {
+	w := NewWorld(0, 0, 32)
+	w.TimeStep = 1.0 / 60
+	box := NewDynamicBody(collision.NewAABB(10, 0, 10, 10), 1)
+	box.Sleep()
+	pusher := NewKinematicBody(collision.NewAABB(0, 0, 10, 10))
+	pusher.Velocity[0] = 60
+	w.AddBody(box)
+	w.AddBody(pusher)
+
+	w.Step(w.TimeStep)
+	if box.Sleeping {
+		t.Fatal("kinematic body moved into a sleeping body without waking it")
+	}
+	w.Step(w.TimeStep)
+	if box.GetPosition()[0] <= 15 {
+		t.Fatal("woken body wasn't pushed")
+	}
+}
+
+func TestCanSleepFalse(t *testing.T) {
+	w := NewWorld(0, 0, 32)
+	w.TimeStep = 1.0 / 60
+	box := NewDynamicBody(collision.NewAABB(0, 0, 10, 10), 1)
+	box.CanSleep = false
+	w.AddBody(box)
+	w.Advance(2)
+	if box.Sleeping {
+		t.Fatal("body with CanSleep false fell asleep")
+	}
+}
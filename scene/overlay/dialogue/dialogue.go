@@ -0,0 +1,311 @@
+package dialogue
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"github.com/shubhamdwivedii/gopher-engine/scene/overlay/ui"
+	"github.com/shubhamdwivedii/gopher-engine/text/richtext"
+	"github.com/shubhamdwivedii/gopher-engine/utils"
+	"golang.org/x/image/font"
+)
+
+// Single line of dialogue, Text can have richtext markup
+type Line struct {
+	Speaker  string
+	Portrait *ebiten.Image
+	Text     string
+	Choices  []string
+	OnChoice func(choice int) // Called with index of the selected choice
+}
+
+/*
+RPG style Dialogue Box, reveals text character-by-character (typewriter)
+Activate (Enter/Space/Click/Gamepad A) completes the page if still revealing, otherwise advances
+Cancel (Escape/Gamepad B) skips to the end of the current line
+*/
+type Box struct {
+	Overlay     ovr.Overlay
+	Layout      ovr.Layout // Placement of the box on the Overlay, Size[0] <= 0 means full width
+	Font        font.Face
+	TextOptions richtext.Options // Width is computed by the Box
+	Speed       float64          // Characters revealed per second, 0 reveals instantly
+	Padding     float64
+
+	Background  color.Color
+	Frame       *ebiten.Image // Optional Nine-Slice frame, drawn instead of Background
+	FrameSlice  utils.NineSlice
+	NameColor   color.Color
+	ChoiceColor color.Color
+	Highlight   color.Color // Background of the selected choice
+
+	Input      *ui.Input
+	LastChoice int // Index of the last selected choice, -1 if none
+
+	queue    []Line
+	pages    []*richtext.Text
+	page     int
+	revealed float64
+	selected int
+	active   bool
+}
+
+func New(overlay ovr.Overlay, fnt font.Face) *Box {
+	size := overlay.GetSize()
+	return &Box{
+		Overlay:     overlay,
+		Layout:      ovr.NewLayout(ANCHOR_BOTTOM, 0, math.Round(size[1]/3), 4, 4),
+		Font:        fnt,
+		TextOptions: richtext.Options{Color: color.White},
+		Speed:       40,
+		Padding:     6,
+		Background:  color.RGBA{16, 16, 32, 220},
+		NameColor:   color.RGBA{255, 220, 120, 255},
+		ChoiceColor: color.White,
+		Highlight:   color.RGBA{72, 84, 140, 255},
+		Input:       &ui.Input{},
+		LastChoice:  -1,
+	}
+}
+
+// Queues lines, the Box becomes active until all lines are done
+func (b *Box) Show(lines ...Line) {
+	b.queue = append(b.queue, lines...)
+	if !b.active {
+		b.next()
+	}
+}
+
+// Shorthand for a line with choices
+func (b *Box) Ask(speaker string, portrait *ebiten.Image, txt string, choices []string, onChoice func(choice int)) {
+	b.Show(Line{Speaker: speaker, Portrait: portrait, Text: txt, Choices: choices, OnChoice: onChoice})
+}
+
+func (b *Box) IsActive() bool {
+	return b.active
+}
+
+// True while the current page is still being revealed
+func (b *Box) IsRevealing() bool {
+	return b.active && int(b.revealed) < b.pages[b.page].Length()
+}
+
+// True when the choices of the current line are shown
+func (b *Box) IsChoosing() bool {
+	return b.active && !b.IsRevealing() && b.page == len(b.pages)-1 && len(b.queue[0].Choices) > 0
+}
+
+// Closes the Box and drops all queued lines
+func (b *Box) Close() {
+	b.queue = nil
+	b.pages = nil
+	b.active = false
+}
+
+// Reveals the rest of the current page
+func (b *Box) Skip() {
+	if b.active {
+		b.revealed = float64(b.pages[b.page].Length())
+	}
+}
+
+// Reveals the whole line, jumping to its last page
+func (b *Box) SkipLine() {
+	if b.active {
+		b.page = len(b.pages) - 1
+		b.Skip()
+	}
+}
+
+// Moves to the next page, or next line if this was the last page
+func (b *Box) Advance() {
+	if !b.active || b.IsRevealing() {
+		return
+	}
+	if b.page < len(b.pages)-1 {
+		b.page++
+		b.revealed = 0
+		return
+	}
+
+	line := b.queue[0]
+	if len(line.Choices) > 0 {
+		b.LastChoice = b.selected
+		if line.OnChoice != nil {
+			line.OnChoice(b.selected)
+		}
+	}
+	b.queue = b.queue[1:]
+	b.next()
+}
+
+func (b *Box) next() {
+	b.active = len(b.queue) > 0
+	b.pages = nil
+	b.page = 0
+	b.revealed = 0
+	b.selected = 0
+	if !b.active {
+		return
+	}
+	_, _, w, h := b.textArea(b.queue[0])
+	opts := b.TextOptions
+	opts.Width = w
+	b.pages = richtext.Layout(b.queue[0].Text, b.Font, &opts).Paginate(h)
+}
+
+func (b *Box) boxRect() (x, y, w, h float64) {
+	layout := b.Layout
+	size := b.Overlay.GetSize()
+	if layout.Size[0] <= 0 {
+		layout.Size[0] = size[0] - 2*layout.Margin[0]
+	}
+	x, y = b.Overlay.Anchor(layout)
+	return x, y, layout.Size[0], layout.Size[1]
+}
+
+// Area for the text, right of the portrait (if any)
+func (b *Box) textArea(line Line) (x, y, w, h float64) {
+	x, y, w, h = b.boxRect()
+	x, y, w, h = x+b.Padding, y+b.Padding, w-2*b.Padding, h-2*b.Padding
+	if line.Portrait != nil {
+		x += h + b.Padding
+		w -= h + b.Padding
+	}
+	return
+}
+
+// Reads input from ebiten and updates the Box
+func (b *Box) Update() error {
+	b.Input.Read(b.Overlay.GetScale())
+	return b.UpdateWithInput(b.Input)
+}
+
+// Same as Update but with an already filled Input, useful for scripted input
+func (b *Box) UpdateWithInput(in *ui.Input) error {
+	if !b.active {
+		return nil
+	}
+
+	if b.IsRevealing() {
+		if b.Speed <= 0 {
+			b.Skip()
+		} else {
+			b.revealed += b.Speed / float64(ebiten.TPS())
+		}
+	}
+
+	if b.IsChoosing() {
+		choices := len(b.queue[0].Choices)
+		if in.Up || in.Previous {
+			b.selected = (b.selected - 1 + choices) % choices
+		}
+		if in.Down || in.Next {
+			b.selected = (b.selected + 1) % choices
+		}
+		if in.MousePressed {
+			if i := b.choiceAt(in.CursorX, in.CursorY); i >= 0 {
+				b.selected = i
+			} else {
+				return nil // Clicks outside choices don't select
+			}
+		}
+	}
+
+	switch {
+	case in.Cancel:
+		b.SkipLine()
+	case in.Activate || in.MousePressed:
+		if b.IsRevealing() {
+			b.Skip()
+		} else {
+			b.Advance()
+		}
+	}
+	return nil
+}
+
+// Choices are listed in a box above the right side of the Dialogue Box
+func (b *Box) choiceRect(index int) (x, y, w, h float64) {
+	line := b.queue[0]
+	bx, by, bw, _ := b.boxRect()
+	lineH := float64(b.Font.Metrics().Height.Ceil()) + b.Padding
+
+	w = 0
+	for _, c := range line.Choices {
+		cw, _ := richtext.Measure(c, b.Font, nil)
+		w = math.Max(w, cw+2*b.Padding)
+	}
+	x = bx + bw - w
+	y = by - float64(len(line.Choices))*lineH - b.Padding + float64(index)*lineH
+	return x, y, w, lineH
+}
+
+func (b *Box) choiceAt(cx, cy float64) int {
+	for i := range b.queue[0].Choices {
+		x, y, w, h := b.choiceRect(i)
+		if cx >= x && cy >= y && cx < x+w && cy < y+h {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Box) drawBackground(x, y, w, h float64) {
+	if b.Frame != nil {
+		b.Overlay.DrawNineSlice(b.Frame, x, y, w, h, b.FrameSlice, &ebiten.DrawImageOptions{})
+		return
+	}
+	b.Overlay.DrawRect(x, y, w, h, true, b.Background)
+}
+
+// Draws the Box on its Overlay (nothing if not active)
+func (b *Box) Draw() {
+	if !b.active {
+		return
+	}
+	line := b.queue[0]
+	bx, by, bw, bh := b.boxRect()
+	b.drawBackground(bx, by, bw, bh)
+
+	if line.Portrait != nil {
+		size := bh - 2*b.Padding
+		pw, ph := float64(line.Portrait.Bounds().Dx()), float64(line.Portrait.Bounds().Dy())
+		scale := math.Min(size/pw, size/ph)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate(bx+b.Padding, by+b.Padding)
+		b.Overlay.DrawImage(line.Portrait, op)
+	}
+
+	if line.Speaker != "" {
+		nw, nh := richtext.Measure(line.Speaker, b.Font, nil)
+		nx, ny := bx+b.Padding, by-nh-b.Padding
+		b.drawBackground(nx-b.Padding/2, ny-b.Padding/2, nw+b.Padding, nh+b.Padding)
+		b.Overlay.DrawRichText(line.Speaker, b.Font, int(nx), int(ny), &richtext.Options{Color: b.NameColor})
+	}
+
+	tx, ty, _, _ := b.textArea(line)
+	b.pages[b.page].DrawPartial(b.Overlay.GetImage(), tx, ty, int(b.revealed))
+
+	// Indicator that more pages follow
+	if !b.IsRevealing() && b.page < len(b.pages)-1 {
+		b.Overlay.DrawRect(bx+bw-b.Padding-4, by+bh-b.Padding-4, 4, 4, true, b.NameColor)
+	}
+
+	if b.IsChoosing() {
+		x, y, w, lineH := b.choiceRect(0)
+		b.drawBackground(x, y-b.Padding/2, w, float64(len(line.Choices))*lineH+b.Padding)
+		for i, c := range line.Choices {
+			cx, cy, cw, ch := b.choiceRect(i)
+			if i == b.selected {
+				b.Overlay.DrawRect(cx, cy, cw, ch, true, b.Highlight)
+			}
+			opts := &richtext.Options{Color: b.ChoiceColor}
+			b.Overlay.DrawRichText(c, b.Font, int(cx+b.Padding), int(cy+b.Padding/2), opts)
+		}
+	}
+}
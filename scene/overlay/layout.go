@@ -0,0 +1,58 @@
+package overlay
+
+import (
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/math/f64"
+)
+
+// Layout places an element of given Size inside a container (usually the Overlay)
+type Layout struct {
+	Anchor string   // One of the ANCHOR_* constants, defaults to ANCHOR_TOP_LEFT
+	Margin f64.Vec2 // Distance from the anchored edges, +ve is always inwards
+	Size   f64.Vec2 // Width, Height of the element
+}
+
+func NewLayout(anchor string, width, height, marginX, marginY float64) Layout {
+	return Layout{
+		Anchor: anchor,
+		Margin: f64.Vec2{marginX, marginY},
+		Size:   f64.Vec2{width, height},
+	}
+}
+
+// Returns normalized anchor point, (0,0) is TopLeft and (1,1) is BottomRight
+func AnchorPoint(anchor string) (ax, ay float64) {
+	switch anchor {
+	case ANCHOR_TOP:
+		return 0.5, 0
+	case ANCHOR_TOP_RIGHT:
+		return 1, 0
+	case ANCHOR_LEFT:
+		return 0, 0.5
+	case ANCHOR_CENTER:
+		return 0.5, 0.5
+	case ANCHOR_RIGHT:
+		return 1, 0.5
+	case ANCHOR_BOTTOM_LEFT:
+		return 0, 1
+	case ANCHOR_BOTTOM:
+		return 0.5, 1
+	case ANCHOR_BOTTOM_RIGHT:
+		return 1, 1
+	}
+	return 0, 0
+}
+
+// Returns TopLeft of the element within a container of given size
+func (l Layout) Position(containerSize f64.Vec2) (x, y float64) {
+	ax, ay := AnchorPoint(l.Anchor)
+
+	x = (containerSize[0]-l.Size[0])*ax + l.Margin[0]*(1-2*ax)
+	y = (containerSize[1]-l.Size[1])*ay + l.Margin[1]*(1-2*ay)
+
+	/* NOTE :-
+	(1 - 2*ax) is +1 on left edge, -1 on right edge and 0 in the middle,
+	so margins always push the element away from the edge it is anchored to.
+	*/
+	return x, y
+}
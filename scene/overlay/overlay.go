@@ -2,8 +2,11 @@ package overlay
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/text/richtext"
 	"github.com/shubhamdwivedii/gopher-engine/utils"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/f64"
@@ -12,22 +15,45 @@ import (
 // Overlay is like a Static Screen over Game Screen, used for UI elements like health-bar etc
 type Overlay interface {
 	Render(screen *ebiten.Image)
+	Resize(resWidth, resHeight int)
+	SetScaleMode(mode string)
+	GetImage() *ebiten.Image
+	GetSize() f64.Vec2
+	GetScale() f64.Vec2
+	Anchor(layout Layout) (x, y float64)
+	DrawImageAnchored(image *ebiten.Image, anchor string, marginX, marginY float64, op *ebiten.DrawImageOptions)
 	DrawImage(image *ebiten.Image, op *ebiten.DrawImageOptions)
 	DrawLine(x1, y1, x2, y2 float64, col color.Color)
 	DrawRect(x, y, width, height float64, fill bool, col color.Color)
+	DrawNineSlice(image *ebiten.Image, x, y, width, height float64, slice utils.NineSlice, op *ebiten.DrawImageOptions)
+	DrawThreeSlice(image *ebiten.Image, x, y, length float64, slice utils.ThreeSlice, op *ebiten.DrawImageOptions)
 	Fill(col color.Color)
 	DebugPrint(text string)
 	DebugPrintAt(text string, x, y int)
 	DrawText(text string, fnt font.Face, x, y int, clr color.Color)
+	DrawRichText(text string, fnt font.Face, x, y int, opts *richtext.Options)
+	DrawTextNative(text string, fnt font.Face, x, y int, clr color.Color)
+}
+
+// Text queued by DrawTextNative
+type nativeText struct {
+	text string
+	fnt  font.Face
+	x, y int
+	clr  color.Color
 }
 
 // Can be used for Overlay, Effects or Transisions
 type StaticScreen struct {
-	ScreenSize  f64.Vec2
-	Image       *ebiten.Image
-	DrawOP      *ebiten.DrawImageOptions
-	Debug       bool
-	AutoScaling bool
+	ReferenceSize f64.Vec2 // Resolution the Overlay is designed for
+	ScreenSize    f64.Vec2 // Current size of the Overlay (same as ReferenceSize unless ScaleMode is SCALE_FIT)
+	Scale         f64.Vec2 // Scale applied while rendering to Render Resolution
+	Image         *ebiten.Image
+	DrawOP        *ebiten.DrawImageOptions
+	Debug         bool
+	AutoScaling   bool
+	ScaleMode     string // OVERLAY_SCALE_STRETCH or OVERLAY_SCALE_FIT
+	nativeTexts   []nativeText
 }
 
 func New(width, height int) Overlay {
@@ -37,12 +63,75 @@ func New(width, height int) Overlay {
 	screenImg.Fill(color.RGBA{64, 220, 14, 64})
 
 	return &StaticScreen{
-		Image:       screenImg,
-		ScreenSize:  f64.Vec2{float64(width), float64(height)},
-		DrawOP:      &ebiten.DrawImageOptions{},
-		AutoScaling: true,
+		Image:         screenImg,
+		ReferenceSize: f64.Vec2{float64(width), float64(height)},
+		ScreenSize:    f64.Vec2{float64(width), float64(height)},
+		Scale:         f64.Vec2{1, 1},
+		DrawOP:        &ebiten.DrawImageOptions{},
+		AutoScaling:   true,
+		ScaleMode:     OVERLAY_SCALE_STRETCH,
+	}
+
+}
+
+/*
+Adjusts Overlay for the given Render Resolution, call it before drawing (eg: from Game.Layout) as a size change clears the Image
+SCALE_STRETCH: Overlay stays at ReferenceSize and is stretched (non-uniformly) to the Render Resolution
+SCALE_FIT: Overlay is scaled uniformly and grows along the extra axis, so Anchored elements stick to actual edges
+*/
+func (s *StaticScreen) Resize(resWidth, resHeight int) {
+	size, scale, ok := s.getLayout(resWidth, resHeight)
+	if !ok {
+		return
 	}
 
+	s.Scale = scale
+
+	if size != s.ScreenSize {
+		// Contents are lost, Overlay is expected to be redrawn every frame
+		s.Image.Dispose()
+		s.Image = ebiten.NewImage(int(size[0]), int(size[1]))
+		s.ScreenSize = size
+	}
+}
+
+// Size and Scale of the Overlay for a Render Resolution (see Resize)
+func (s *StaticScreen) getLayout(resWidth, resHeight int) (size, scale f64.Vec2, ok bool) {
+	resX, resY := float64(resWidth), float64(resHeight)
+	if resX <= 0 || resY <= 0 {
+		return size, scale, false
+	}
+
+	size = s.ReferenceSize
+	scaleX, scaleY := resX/s.ReferenceSize[0], resY/s.ReferenceSize[1]
+
+	if s.ScaleMode == OVERLAY_SCALE_FIT {
+		fit := math.Min(scaleX, scaleY)
+		scaleX, scaleY = fit, fit
+		size = f64.Vec2{math.Ceil(resX / fit), math.Ceil(resY / fit)}
+	}
+	return size, f64.Vec2{scaleX, scaleY}, true
+}
+
+func (s *StaticScreen) SetScaleMode(mode string) {
+	s.ScaleMode = mode
+}
+
+func (s *StaticScreen) GetImage() *ebiten.Image {
+	return s.Image
+}
+
+func (s *StaticScreen) GetSize() f64.Vec2 {
+	return s.ScreenSize
+}
+
+func (s *StaticScreen) GetScale() f64.Vec2 {
+	return s.Scale
+}
+
+// Returns TopLeft (in Overlay coordinates) of element placed using layout
+func (s *StaticScreen) Anchor(layout Layout) (x, y float64) {
+	return layout.Position(s.ScreenSize)
 }
 
 // Renders Overlay on RenderScreen (target)
@@ -50,16 +139,29 @@ func (s *StaticScreen) Render(targetScreen *ebiten.Image) {
 	s.DrawOP.GeoM.Reset()
 
 	// Scaling Screen Image to Render Resolution
-	if !s.AutoScaling {
-		resX, resY := targetScreen.Bounds().Dx(), targetScreen.Bounds().Dy()
-		if resX != int(s.ScreenSize[0]) || resY != int(s.ScreenSize[1]) {
-			scaleX, scaleY := float64(resX)/float64(s.ScreenSize[0]), float64(resY)/float64(s.ScreenSize[1])
-			s.DrawOP.GeoM.Scale(scaleX, scaleY)
+	if s.AutoScaling {
+		// The Image isn't resized here, it already holds this frame. Until Resize is called it's stretched to fit
+		resW, resH := targetScreen.Bounds().Dx(), targetScreen.Bounds().Dy()
+		if size, scale, ok := s.getLayout(resW, resH); ok {
+			if size != s.ScreenSize {
+				scale = f64.Vec2{float64(resW) / s.ScreenSize[0], float64(resH) / s.ScreenSize[1]}
+			}
+			s.Scale = scale
+		}
+		if s.Scale[0] != 1 || s.Scale[1] != 1 {
+			s.DrawOP.GeoM.Scale(s.Scale[0], s.Scale[1])
 		}
 	}
 
 	utils.DrawImage(s.Image, targetScreen, s.DrawOP)
 	// screen.DrawImage(s.Image, s.DrawOP)
+
+	// Native Text skips the scaling of the Overlay Image, so it stays crisp
+	for _, t := range s.nativeTexts {
+		x, y := s.DrawOP.GeoM.Apply(float64(t.x), float64(t.y))
+		utils.DrawText(targetScreen, t.text, t.fnt, int(math.Round(x)), int(math.Round(y)), t.clr)
+	}
+	s.nativeTexts = s.nativeTexts[:0]
 }
 
 func (s *StaticScreen) Fill(col color.Color) {
@@ -70,6 +172,14 @@ func (s *StaticScreen) DrawImage(image *ebiten.Image, op *ebiten.DrawImageOption
 	utils.DrawImage(image, s.Image, op)
 }
 
+// Draws image relative to anchor, any translation in op is applied before anchoring
+func (s *StaticScreen) DrawImageAnchored(image *ebiten.Image, anchor string, marginX, marginY float64, op *ebiten.DrawImageOptions) {
+	w, h := image.Bounds().Dx(), image.Bounds().Dy()
+	x, y := s.Anchor(NewLayout(anchor, float64(w), float64(h), marginX, marginY))
+	op.GeoM.Translate(x, y)
+	utils.DrawImage(image, s.Image, op)
+}
+
 func (s *StaticScreen) DrawLine(x1, y1, x2, y2 float64, col color.Color) {
 	utils.DrawLine(s.Image, x1, y1, x2, y2, col)
 }
@@ -78,6 +188,14 @@ func (s *StaticScreen) DrawRect(x, y, width, height float64, solid bool, clr col
 	utils.DrawRect(s.Image, x, y, width, height, solid, clr)
 }
 
+func (s *StaticScreen) DrawNineSlice(image *ebiten.Image, x, y, width, height float64, slice utils.NineSlice, op *ebiten.DrawImageOptions) {
+	utils.DrawNineSlice(image, s.Image, x, y, width, height, slice, op)
+}
+
+func (s *StaticScreen) DrawThreeSlice(image *ebiten.Image, x, y, length float64, slice utils.ThreeSlice, op *ebiten.DrawImageOptions) {
+	utils.DrawThreeSlice(image, s.Image, x, y, length, slice, op)
+}
+
 func (s *StaticScreen) DebugPrint(text string) {
 	utils.DebugPrint(s.Image, text)
 }
@@ -89,3 +207,16 @@ func (s *StaticScreen) DebugPrintAt(text string, x, y int) {
 func (s *StaticScreen) DrawText(txt string, fnt font.Face, x, y int, clr color.Color) {
 	utils.DrawText(s.Image, txt, fnt, x, y, clr)
 }
+
+// Wrapped, aligned text with markup (see richtext), x, y is TopLeft (not baseline)
+func (s *StaticScreen) DrawRichText(txt string, fnt font.Face, x, y int, opts *richtext.Options) {
+	utils.DrawRichText(s.Image, txt, fnt, x, y, opts)
+}
+
+/*
+Text is drawn directly on the Render Screen (at Render Resolution) during Render, instead of on the Overlay Image.
+x, y are in Overlay coordinates, fnt should already be sized for Render Resolution (see text/fonts Registry.SyncScale)
+*/
+func (s *StaticScreen) DrawTextNative(txt string, fnt font.Face, x, y int, clr color.Color) {
+	s.nativeTexts = append(s.nativeTexts, nativeText{text: txt, fnt: fnt, x: x, y: y, clr: clr})
+}
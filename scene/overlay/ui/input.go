@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/math/f64"
+)
+
+// Input state for a single Update, in Overlay coordinates
+// Filled from ebiten by Read, or manually for scripted input
+type Input struct {
+	CursorX, CursorY float64
+	MouseDown        bool
+	MousePressed     bool // Just pressed this Update
+	MouseReleased    bool // Just released this Update
+	WheelX, WheelY   float64
+
+	Chars     []rune // Typed characters
+	Backspace bool
+	Delete    bool
+	Left      bool
+	Right     bool
+	Up        bool
+	Down      bool
+	Home      bool
+	End       bool
+	Activate  bool // Enter, Space or Gamepad (A)
+	Submit    bool // Enter only
+	Next      bool // Tab or Gamepad DPad Down
+	Previous  bool // Shift+Tab or Gamepad DPad Up
+	Cancel    bool // Escape or Gamepad (B)
+
+	gamepadIDs []ebiten.GamepadID
+}
+
+// Reads current input from ebiten, scale is the Overlay scale (Render Resolution / Overlay Size)
+// Not read through an input.Source, so an input.Recorder doesn't record it (replay UI input with UpdateWithInput)
+func (in *Input) Read(scale f64.Vec2) {
+	cx, cy := ebiten.CursorPosition()
+	in.CursorX, in.CursorY = float64(cx)/scale[0], float64(cy)/scale[1]
+	in.MouseDown = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	in.MousePressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	in.MouseReleased = inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	in.WheelX, in.WheelY = ebiten.Wheel()
+
+	in.Chars = ebiten.AppendInputChars(in.Chars[:0])
+	in.Backspace = repeatingKeyPressed(ebiten.KeyBackspace)
+	in.Delete = repeatingKeyPressed(ebiten.KeyDelete)
+	in.Left = repeatingKeyPressed(ebiten.KeyArrowLeft)
+	in.Right = repeatingKeyPressed(ebiten.KeyArrowRight)
+	in.Up = repeatingKeyPressed(ebiten.KeyArrowUp)
+	in.Down = repeatingKeyPressed(ebiten.KeyArrowDown)
+	in.Home = inpututil.IsKeyJustPressed(ebiten.KeyHome)
+	in.End = inpututil.IsKeyJustPressed(ebiten.KeyEnd)
+	in.Submit = inpututil.IsKeyJustPressed(ebiten.KeyEnter)
+	in.Activate = in.Submit || inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	in.Cancel = inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	tab := inpututil.IsKeyJustPressed(ebiten.KeyTab)
+	in.Next = tab && !shift
+	in.Previous = tab && shift
+
+	in.gamepadIDs = ebiten.AppendGamepadIDs(in.gamepadIDs[:0])
+	for _, id := range in.gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		pressed := func(b ebiten.StandardGamepadButton) bool {
+			return inpututil.IsStandardGamepadButtonJustPressed(id, b)
+		}
+		in.Next = in.Next || pressed(ebiten.StandardGamepadButtonLeftBottom)
+		in.Previous = in.Previous || pressed(ebiten.StandardGamepadButtonLeftTop)
+		in.Left = in.Left || pressed(ebiten.StandardGamepadButtonLeftLeft)
+		in.Right = in.Right || pressed(ebiten.StandardGamepadButtonLeftRight)
+		in.Activate = in.Activate || pressed(ebiten.StandardGamepadButtonRightBottom)
+		in.Cancel = in.Cancel || pressed(ebiten.StandardGamepadButtonRightRight)
+	}
+}
+
+// Pressed now, and then repeatedly while held down (like a text editor)
+func repeatingKeyPressed(key ebiten.Key) bool {
+	const (
+		delay    = 30
+		interval = 3
+	)
+	d := inpututil.KeyPressDuration(key)
+	if d == 1 {
+		return true
+	}
+	if d >= delay && (d-delay)%interval == 0 {
+		return true
+	}
+	return false
+}
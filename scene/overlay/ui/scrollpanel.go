@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/math/f64"
+)
+
+// ScrollPanel clips its children to its bounds and scrolls them (vertically) using mouse wheel or focus
+type ScrollPanel struct {
+	Element
+	Theme       *Theme
+	Background  color.Color // nil means Theme.Normal
+	Scroll      f64.Vec2
+	WheelSpeed  float64
+	ScrollbarW  float64
+	canvas      ovr.Overlay // Children are drawn here and then clipped onto the target
+	canvasSize  f64.Vec2
+	contentSize f64.Vec2
+}
+
+func NewScrollPanel(x, y, width, height float64, theme *Theme) *ScrollPanel {
+	return &ScrollPanel{
+		Element:    Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}},
+		Theme:      theme,
+		WheelSpeed: 16,
+		ScrollbarW: 4,
+	}
+}
+
+func (p *ScrollPanel) Add(w Widget) {
+	p.AddChild(p, w)
+}
+
+func (p *ScrollPanel) GetScroll() f64.Vec2 {
+	return p.Scroll
+}
+
+// Size of the area covered by children
+func (p *ScrollPanel) ContentSize() f64.Vec2 {
+	size := f64.Vec2{}
+	for _, child := range p.Children {
+		ce := child.GetElement()
+		if ce.Hidden {
+			continue
+		}
+		cx, cy := ce.LocalPosition(p.Size)
+		size[0] = math.Max(size[0], cx+ce.Size[0])
+		size[1] = math.Max(size[1], cy+ce.Size[1])
+	}
+	return size
+}
+
+func (p *ScrollPanel) ScrollBy(dx, dy float64) {
+	content := p.ContentSize()
+	p.Scroll[0] = math.Max(0, math.Min(p.Scroll[0]+dx, content[0]-p.Size[0]))
+	p.Scroll[1] = math.Max(0, math.Min(p.Scroll[1]+dy, content[1]-p.Size[1]))
+}
+
+// Scrolls just enough for an absolute rect (eg: bounds of a child) to be visible
+func (p *ScrollPanel) ScrollTo(r Rect) {
+	dx, dy := 0.0, 0.0
+	if r.Y < p.bounds.Y {
+		dy = r.Y - p.bounds.Y
+	} else if r.Y+r.H > p.bounds.Y+p.bounds.H {
+		dy = r.Y + r.H - (p.bounds.Y + p.bounds.H)
+	}
+	if r.X < p.bounds.X {
+		dx = r.X - p.bounds.X
+	} else if r.X+r.W > p.bounds.X+p.bounds.W {
+		dx = r.X + r.W - (p.bounds.X + p.bounds.W)
+	}
+	p.ScrollBy(dx, dy)
+}
+
+func (p *ScrollPanel) Update(in *Input) error {
+	if p.Disabled {
+		return nil
+	}
+	// Wheel works even if a child is hovered
+	if p.bounds.Contains(in.CursorX, in.CursorY) && p.clip.Contains(in.CursorX, in.CursorY) {
+		if in.WheelX != 0 || in.WheelY != 0 {
+			p.ScrollBy(-in.WheelX*p.WheelSpeed, -in.WheelY*p.WheelSpeed)
+		}
+	}
+	return nil
+}
+
+func (p *ScrollPanel) Draw(target ovr.Overlay, x, y float64) {
+	if p.canvas == nil || p.canvasSize != p.Size {
+		if p.canvas != nil {
+			p.canvas.GetImage().Dispose()
+		}
+		p.canvas = ovr.New(int(math.Max(1, p.Size[0])), int(math.Max(1, p.Size[1])))
+		p.canvasSize = p.Size
+	}
+
+	bg := p.Background
+	if bg == nil {
+		bg = p.Theme.Normal
+	}
+	p.canvas.GetImage().Clear()
+	p.canvas.Fill(bg)
+
+	DrawChildren(&p.Element, p.canvas, -p.Scroll[0], -p.Scroll[1])
+
+	content := p.ContentSize()
+	if content[1] > p.Size[1] {
+		ratio := p.Size[1] / content[1]
+		barH := p.Size[1] * ratio
+		barY := p.Scroll[1] * ratio
+		p.canvas.DrawRect(p.Size[0]-p.ScrollbarW, barY, p.ScrollbarW, barH, true, p.Theme.Accent)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x, y)
+	target.DrawImage(p.canvas.GetImage(), op)
+}
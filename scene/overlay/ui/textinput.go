@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"unicode"
+
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/math/f64"
+)
+
+// Single line text field, receives typed characters while focused
+type TextInput struct {
+	Element
+	Theme       *Theme
+	Text        []rune
+	Placeholder string
+	MaxLength   int // 0 means no limit
+	Caret       int // Index in Text
+	OnChange    func(text string)
+	OnSubmit    func(text string) // Enter pressed while focused
+
+	blink int
+}
+
+func NewTextInput(x, y, width, height float64, placeholder string, theme *Theme) *TextInput {
+	return &TextInput{
+		Element:     Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}, Focusable: true},
+		Theme:       theme,
+		Placeholder: placeholder,
+	}
+}
+
+func (t *TextInput) GetText() string {
+	return string(t.Text)
+}
+
+func (t *TextInput) SetText(txt string) {
+	t.Text = []rune(txt)
+	t.Caret = len(t.Text)
+}
+
+func (t *TextInput) Update(in *Input) error {
+	if !t.Focused || t.Disabled {
+		t.blink = 0
+		return nil
+	}
+	t.blink++
+
+	changed := false
+	for _, r := range in.Chars {
+		if !unicode.IsPrint(r) || (t.MaxLength > 0 && len(t.Text) >= t.MaxLength) {
+			continue
+		}
+		t.Text = append(t.Text[:t.Caret], append([]rune{r}, t.Text[t.Caret:]...)...)
+		t.Caret++
+		changed = true
+	}
+
+	if in.Backspace && t.Caret > 0 {
+		t.Text = append(t.Text[:t.Caret-1], t.Text[t.Caret:]...)
+		t.Caret--
+		changed = true
+	}
+	if in.Delete && t.Caret < len(t.Text) {
+		t.Text = append(t.Text[:t.Caret], t.Text[t.Caret+1:]...)
+		changed = true
+	}
+
+	if in.Left && t.Caret > 0 {
+		t.Caret--
+	}
+	if in.Right && t.Caret < len(t.Text) {
+		t.Caret++
+	}
+	if in.Home {
+		t.Caret = 0
+	}
+	if in.End {
+		t.Caret = len(t.Text)
+	}
+
+	if changed && t.OnChange != nil {
+		t.OnChange(string(t.Text))
+	}
+
+	if in.Submit && t.OnSubmit != nil {
+		t.OnSubmit(string(t.Text))
+	}
+	return nil
+}
+
+func (t *TextInput) Draw(target ovr.Overlay, x, y float64) {
+	t.Theme.DrawFrame(target, &t.Element, x, y)
+
+	if len(t.Text) == 0 && !t.Focused {
+		t.Theme.DrawLabel(target, t.Placeholder, x, y, t.Size[0], t.Size[1], false, t.Theme.Disabled)
+		return
+	}
+
+	// Scroll text to the left if caret would go out of the field
+	visible := t.Text
+	caretX, _ := t.Theme.MeasureText(string(t.Text[:t.Caret]))
+	offset := 0.0
+	if maxW := t.Size[0] - 2*t.Theme.Padding; caretX > maxW {
+		offset = caretX - maxW
+	}
+	for len(visible) > 0 && offset > 0 {
+		w, _ := t.Theme.MeasureText(string(visible[:1]))
+		visible = visible[1:]
+		offset -= w
+		caretX -= w
+	}
+	t.Theme.DrawLabel(target, string(visible), x, y, t.Size[0], t.Size[1], false, t.Theme.TextColor)
+
+	if t.Focused && (t.blink/30)%2 == 0 {
+		_, h := t.Theme.MeasureText("|")
+		cx := x + t.Theme.Padding + caretX
+		target.DrawRect(cx, y+(t.Size[1]-h)/2, 1, h, true, t.Theme.Accent)
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"github.com/shubhamdwivedii/gopher-engine/utils"
+	"golang.org/x/image/font"
+)
+
+// Size of a glyph of ebitenutil's debug font, used when Theme has no Font
+const debugGlyphW, debugGlyphH = 6, 16
+
+type Theme struct {
+	Font      font.Face // nil means ebitenutil's debug font is used
+	TextColor color.Color
+	Padding   float64
+
+	Normal   color.Color
+	Hover    color.Color
+	Pressed  color.Color
+	Disabled color.Color
+	Focus    color.Color // Outline of the focused Widget
+	Accent   color.Color // Checkmarks, Slider knobs, Caret etc
+
+	Frame      *ebiten.Image // Optional Nine-Slice image for Panels and Buttons, tinted by state color
+	FrameSlice utils.NineSlice
+}
+
+func DefaultTheme() *Theme {
+	return &Theme{
+		TextColor: color.White,
+		Padding:   4,
+		Normal:    color.RGBA{48, 56, 72, 220},
+		Hover:     color.RGBA{72, 84, 108, 230},
+		Pressed:   color.RGBA{32, 36, 48, 240},
+		Disabled:  color.RGBA{40, 40, 40, 160},
+		Focus:     color.RGBA{255, 200, 64, 255},
+		Accent:    color.RGBA{96, 200, 120, 255},
+	}
+}
+
+// Background color for the current state of the Element
+func (t *Theme) StateColor(e *Element) color.Color {
+	switch {
+	case e.Disabled:
+		return t.Disabled
+	case e.Pressed:
+		return t.Pressed
+	case e.Hovered:
+		return t.Hover
+	}
+	return t.Normal
+}
+
+// Draws the background and focus outline of a Widget
+func (t *Theme) DrawFrame(target ovr.Overlay, e *Element, x, y float64) {
+	t.DrawBackground(target, x, y, e.Size[0], e.Size[1], t.StateColor(e))
+	if e.Focused {
+		target.DrawRect(x, y, e.Size[0], e.Size[1], false, t.Focus)
+	}
+}
+
+// Nine-Slice Frame (tinted by clr) if Theme has one, plain rect otherwise
+func (t *Theme) DrawBackground(target ovr.Overlay, x, y, width, height float64, clr color.Color) {
+	if t.Frame == nil {
+		target.DrawRect(x, y, width, height, true, clr)
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleWithColor(clr)
+	target.DrawNineSlice(t.Frame, x, y, width, height, t.FrameSlice, op)
+}
+
+func (t *Theme) MeasureText(txt string) (w, h float64) {
+	if t.Font == nil {
+		return float64(len([]rune(txt)) * debugGlyphW), debugGlyphH
+	}
+	// Advance width, BoundString would ignore trailing spaces (and the caret would lag behind them)
+	return float64(font.MeasureString(t.Font, txt).Ceil()), float64(t.Font.Metrics().Height.Ceil())
+}
+
+// x, y is TopLeft of the text (not the baseline)
+func (t *Theme) DrawText(target ovr.Overlay, txt string, x, y float64, clr color.Color) {
+	if t.Font == nil {
+		// Debug font is always white
+		target.DebugPrintAt(txt, int(x), int(y))
+		return
+	}
+	ascent := t.Font.Metrics().Ascent.Ceil()
+	target.DrawText(txt, t.Font, int(x), int(y)+ascent, clr)
+}
+
+// Draws text centered vertically, and horizontally if center is true
+func (t *Theme) DrawLabel(target ovr.Overlay, txt string, x, y, w, h float64, center bool, clr color.Color) {
+	tw, th := t.MeasureText(txt)
+	tx := x + t.Padding
+	if center {
+		tx = x + (w-tw)/2
+	}
+	t.DrawText(target, txt, tx, y+(h-th)/2, clr)
+}
@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"image/color"
+
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/math/f64"
+)
+
+// Every Widget embeds an Element, the UI works on Elements and lets Widgets react in Update
+type Widget interface {
+	GetElement() *Element
+	Update(in *Input) error
+	Draw(target ovr.Overlay, x, y float64) // x, y is TopLeft of the Widget on target
+}
+
+type Element struct {
+	Position  f64.Vec2 // Relative to Parent (Margin from the anchored edge if Anchor is set)
+	Size      f64.Vec2
+	Anchor    string // Optional, one of the ANCHOR_* constants
+	Hidden    bool
+	Disabled  bool
+	Focusable bool
+
+	// State, maintained by the UI every Update
+	Hovered bool
+	Pressed bool
+	Focused bool
+	Clicked bool // true only for the Update in which the Widget was clicked/activated
+
+	Children []Widget
+	Parent   Widget
+
+	bounds Rect // Absolute bounds on the Overlay
+	clip   Rect // Visible portion of the Overlay for this Element
+}
+
+func (e *Element) GetElement() *Element {
+	return e
+}
+
+// Absolute bounds of the Element on the Overlay (valid after UI.Update)
+func (e *Element) GetBounds() Rect {
+	return e.bounds
+}
+
+// Position of the Element within a parent of given size
+func (e *Element) LocalPosition(parentSize f64.Vec2) (x, y float64) {
+	if e.Anchor == "" {
+		return e.Position[0], e.Position[1]
+	}
+	layout := ovr.NewLayout(e.Anchor, e.Size[0], e.Size[1], e.Position[0], e.Position[1])
+	return layout.Position(parentSize)
+}
+
+func (e *Element) AddChild(parent Widget, child Widget) {
+	child.GetElement().Parent = parent
+	e.Children = append(e.Children, child)
+}
+
+func (e *Element) RemoveChild(child Widget) {
+	for i, c := range e.Children {
+		if c == child {
+			e.Children = append(e.Children[:i], e.Children[i+1:]...)
+			child.GetElement().Parent = nil
+			return
+		}
+	}
+}
+
+// Containers that move their children (like ScrollPanel) implement this
+type scroller interface {
+	GetScroll() f64.Vec2
+}
+
+// Draws all visible children, used by containers in their Draw
+func DrawChildren(e *Element, target ovr.Overlay, x, y float64) {
+	for _, child := range e.Children {
+		ce := child.GetElement()
+		if ce.Hidden {
+			continue
+		}
+		cx, cy := ce.LocalPosition(e.Size)
+		child.Draw(target, x+cx, y+cy)
+	}
+}
+
+// UI is the root of the Widget tree, rendered on an Overlay (StaticScreen)
+type UI struct {
+	Overlay ovr.Overlay
+	Theme   *Theme
+	Root    *Panel
+	Focused Widget
+	Input   *Input
+
+	pressed Widget
+	order   []Widget // Widgets in tree (draw) order, rebuilt every Update
+}
+
+func New(overlay ovr.Overlay, theme *Theme) *UI {
+	if theme == nil {
+		theme = DefaultTheme()
+	}
+	root := NewPanel(0, 0, 0, 0, theme)
+	root.Background = color.Transparent
+	return &UI{
+		Overlay: overlay,
+		Theme:   theme,
+		Root:    root,
+		Input:   &Input{},
+	}
+}
+
+func (u *UI) Add(w Widget) {
+	u.Root.AddChild(u.Root, w)
+}
+
+func (u *UI) Remove(w Widget) {
+	if u.Focused == w {
+		u.SetFocus(nil)
+	}
+	u.Root.RemoveChild(w)
+}
+
+func (u *UI) SetFocus(w Widget) {
+	if u.Focused != nil {
+		u.Focused.GetElement().Focused = false
+	}
+	u.Focused = w
+	if w != nil {
+		w.GetElement().Focused = true
+	}
+}
+
+// Reads input (mouse, keyboard, gamepad) and updates all Widgets
+func (u *UI) Update() error {
+	u.Input.Read(u.Overlay.GetScale())
+	return u.UpdateWithInput(u.Input)
+}
+
+// Same as Update but with an already filled Input, useful for scripted input
+func (u *UI) UpdateWithInput(in *Input) error {
+	u.Root.Size = u.Overlay.GetSize()
+	u.order = u.order[:0]
+	full := Rect{0, 0, u.Root.Size[0], u.Root.Size[1]}
+	u.layout(u.Root, 0, 0, full)
+
+	// Topmost hovered widget is the last one in draw order
+	var hovered Widget
+	for _, w := range u.order {
+		e := w.GetElement()
+		e.Hovered = false
+		e.Clicked = false
+		if !e.Disabled && e.bounds.Contains(in.CursorX, in.CursorY) && e.clip.Contains(in.CursorX, in.CursorY) {
+			hovered = w
+		}
+	}
+	if hovered == Widget(u.Root) {
+		hovered = nil
+	}
+	if hovered != nil {
+		hovered.GetElement().Hovered = true
+	}
+
+	if in.MousePressed {
+		u.pressed = hovered
+		if hovered != nil {
+			hovered.GetElement().Pressed = true
+			if hovered.GetElement().Focusable {
+				u.SetFocus(hovered)
+			}
+		} else {
+			u.SetFocus(nil)
+		}
+	}
+
+	if in.MouseReleased && u.pressed != nil {
+		e := u.pressed.GetElement()
+		e.Pressed = false
+		e.Clicked = u.pressed == hovered
+		u.pressed = nil
+	}
+
+	// Arrow Up/Down also navigate, Left/Right are left for Widgets (Slider, TextInput)
+	if in.Next || in.Down {
+		u.moveFocus(1)
+	} else if in.Previous || in.Up {
+		u.moveFocus(-1)
+	}
+
+	if u.Focused != nil && in.Activate {
+		u.Focused.GetElement().Clicked = true
+	}
+
+	for _, w := range u.order {
+		if err := w.Update(in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Computes absolute bounds of w and its children
+func (u *UI) layout(w Widget, x, y float64, clip Rect) {
+	e := w.GetElement()
+	if e.Hidden {
+		return
+	}
+	e.bounds = Rect{x, y, e.Size[0], e.Size[1]}
+	e.clip = clip
+	if e.Disabled && u.Focused == w {
+		u.SetFocus(nil)
+	}
+	u.order = append(u.order, w)
+
+	childClip := clip
+	if s, ok := w.(scroller); ok {
+		scroll := s.GetScroll()
+		x, y = x-scroll[0], y-scroll[1]
+		childClip = clip.Intersect(e.bounds)
+	}
+
+	for _, child := range e.Children {
+		cx, cy := child.GetElement().LocalPosition(e.Size)
+		u.layout(child, x+cx, y+cy, childClip)
+	}
+}
+
+func (u *UI) moveFocus(dir int) {
+	var focusable []Widget
+	current := -1
+	for _, w := range u.order {
+		e := w.GetElement()
+		if e.Focusable && !e.Disabled {
+			if w == u.Focused {
+				current = len(focusable)
+			}
+			focusable = append(focusable, w)
+		}
+	}
+	if len(focusable) == 0 {
+		return
+	}
+
+	next := 0
+	if current >= 0 {
+		next = (current + dir + len(focusable)) % len(focusable)
+	} else if dir < 0 {
+		next = len(focusable) - 1
+	}
+	u.SetFocus(focusable[next])
+	u.scrollIntoView(focusable[next])
+}
+
+// Scrolls parent ScrollPanels so that w is visible
+func (u *UI) scrollIntoView(w Widget) {
+	e := w.GetElement()
+	for p := e.Parent; p != nil; p = p.GetElement().Parent {
+		if sp, ok := p.(*ScrollPanel); ok {
+			sp.ScrollTo(e.bounds)
+			return
+		}
+	}
+}
+
+// Draws the Widget tree on the Overlay
+func (u *UI) Draw() {
+	u.Root.Draw(u.Overlay, 0, 0)
+}
+
+type Rect struct {
+	X, Y, W, H float64
+}
+
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
+}
+
+func (r Rect) Intersect(o Rect) Rect {
+	x1, y1 := maxf(r.X, o.X), maxf(r.Y, o.Y)
+	x2, y2 := minf(r.X+r.W, o.X+o.W), minf(r.Y+r.H, o.Y+o.H)
+	if x2 < x1 || y2 < y1 {
+		return Rect{x1, y1, 0, 0}
+	}
+	return Rect{x1, y1, x2 - x1, y2 - y1}
+}
+
+func minf(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"testing"
+
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/math/f64"
+)
+
+// Only the size is needed to lay widgets out, nothing is drawn
+type fakeOverlay struct {
+	ovr.Overlay
+	size f64.Vec2
+}
+
+func (o *fakeOverlay) GetSize() f64.Vec2 {
+	return o.size
+}
+
+func (o *fakeOverlay) GetScale() f64.Vec2 {
+	return f64.Vec2{1, 1}
+}
+
+func newTestUI() *UI {
+	return New(&fakeOverlay{size: f64.Vec2{320, 240}}, nil)
+}
+
+func step(t *testing.T, u *UI, in Input) {
+	t.Helper()
+	if err := u.UpdateWithInput(&in); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFocusNavigation(t *testing.T) {
+	u := newTestUI()
+	clicks := 0
+	play := NewButton(10, 10, 80, 20, "Play", u.Theme, func() { clicks++ })
+	sound := NewCheckbox(10, 40, 80, 20, "Sound", u.Theme, nil)
+	locked := NewButton(10, 70, 80, 20, "Locked", u.Theme, nil)
+	locked.Disabled = true
+	hidden := NewButton(10, 100, 80, 20, "Hidden", u.Theme, nil)
+	hidden.Hidden = true
+	panel := NewPanel(100, 0, 100, 100, u.Theme)
+	volume := NewSlider(0, 10, 80, 20, 0, 10, 5, u.Theme, nil)
+	panel.Add(volume)
+	label := NewLabel(10, 130, "Not focusable", u.Theme)
+	for _, w := range []Widget{play, sound, locked, hidden, panel, label} {
+		u.Add(w)
+	}
+
+	// Tree order, skipping disabled, hidden and unfocusable widgets, wrapping around
+	want := []Widget{play, sound, volume, play}
+	for i, w := range want {
+		step(t, u, Input{Next: true})
+		if u.Focused != w || !w.GetElement().Focused {
+			t.Fatalf("Next %d focused %T at %v, want %T", i+1, u.Focused, u.Focused.GetElement().Position, w)
+		}
+	}
+	if sound.Focused {
+		t.Fatal("previously focused widget still has Focused set")
+	}
+	step(t, u, Input{Previous: true})
+	if u.Focused != volume {
+		t.Fatal("Previous didn't wrap back to the last widget")
+	}
+	step(t, u, Input{Up: true})
+	if u.Focused != sound {
+		t.Fatal("arrow Up didn't move focus back")
+	}
+
+	// Activate clicks the focused widget
+	step(t, u, Input{Activate: true})
+	if !sound.Checked {
+		t.Fatal("Activate didn't toggle the focused checkbox")
+	}
+	step(t, u, Input{})
+	if sound.Clicked {
+		t.Fatal("Clicked lasted longer than one Update")
+	}
+
+	// Clicking focuses, releasing elsewhere doesn't click
+	step(t, u, Input{CursorX: 20, CursorY: 15, MousePressed: true, MouseDown: true})
+	if u.Focused != play || !play.Pressed {
+		t.Fatal("pressing a button didn't focus it")
+	}
+	step(t, u, Input{CursorX: 300, CursorY: 200, MouseReleased: true})
+	if clicks != 0 || play.Pressed {
+		t.Fatalf("%d clicks after releasing outside, want 0", clicks)
+	}
+	step(t, u, Input{CursorX: 20, CursorY: 15, MousePressed: true, MouseDown: true})
+	step(t, u, Input{CursorX: 25, CursorY: 20, MouseReleased: true})
+	if clicks != 1 {
+		t.Fatalf("%d clicks, want 1", clicks)
+	}
+
+	// Disabled widgets never get the cursor, and lose focus
+	step(t, u, Input{CursorX: 20, CursorY: 75})
+	if locked.Hovered {
+		t.Fatal("disabled button is hovered")
+	}
+	play.Disabled = true
+	step(t, u, Input{})
+	if u.Focused != nil || play.Focused {
+		t.Fatal("disabled widget kept the focus")
+	}
+
+	// Clicking empty space clears the focus
+	step(t, u, Input{Next: true})
+	step(t, u, Input{CursorX: 300, CursorY: 200, MousePressed: true, MouseDown: true})
+	if u.Focused != nil {
+		t.Fatal("clicking nothing kept the focus")
+	}
+}
+
+func TestScrollPanel(t *testing.T) {
+	u := newTestUI()
+	panel := NewScrollPanel(0, 0, 100, 50, u.Theme)
+	var buttons []*Button
+	for i := 0; i < 4; i++ {
+		b := NewButton(0, float64(i)*30, 80, 20, "Item", u.Theme, nil)
+		buttons = append(buttons, b)
+		panel.Add(b)
+	}
+	u.Add(panel)
+	step(t, u, Input{})
+
+	if c := panel.ContentSize(); c != (f64.Vec2{80, 110}) {
+		t.Fatalf("ContentSize() = %v, want 80, 110", c)
+	}
+	panel.ScrollBy(0, 1000)
+	if panel.Scroll != (f64.Vec2{0, 60}) {
+		t.Fatalf("Scroll %v, want clamped to 0, 60", panel.Scroll)
+	}
+	panel.ScrollBy(-10, -1000)
+	if panel.Scroll != (f64.Vec2{}) {
+		t.Fatalf("Scroll %v, want clamped to 0, 0", panel.Scroll)
+	}
+
+	// Wheel over a child still scrolls, and moves children
+	step(t, u, Input{CursorX: 10, CursorY: 10, WheelY: -1})
+	if panel.Scroll[1] != panel.WheelSpeed {
+		t.Fatalf("Scroll %v after one wheel step, want %v", panel.Scroll, panel.WheelSpeed)
+	}
+	step(t, u, Input{})
+	if b := buttons[1].GetBounds(); b.Y != 30-panel.WheelSpeed {
+		t.Fatalf("scrolled child at y %v, want %v", b.Y, 30-panel.WheelSpeed)
+	}
+	step(t, u, Input{CursorX: 200, CursorY: 10, WheelY: -1})
+	if panel.Scroll[1] != panel.WheelSpeed {
+		t.Fatal("wheel outside the panel scrolled it")
+	}
+
+	// Scrolled out children can't be hovered
+	step(t, u, Input{CursorX: 10, CursorY: 60})
+	if buttons[2].Hovered {
+		t.Fatal("child outside the panel's clip is hovered")
+	}
+
+	// Focus scrolls just enough to show the widget
+	panel.Scroll = f64.Vec2{}
+	step(t, u, Input{Previous: true})
+	if u.Focused != buttons[3] || panel.Scroll[1] != 60 {
+		t.Fatalf("focused last item with Scroll %v, want 60", panel.Scroll)
+	}
+	step(t, u, Input{Next: true})
+	if u.Focused != buttons[0] || panel.Scroll[1] != 0 {
+		t.Fatalf("focused first item with Scroll %v, want 0", panel.Scroll)
+	}
+
+	// Content smaller than the panel never scrolls
+	small := NewScrollPanel(0, 0, 100, 200, u.Theme)
+	small.Add(NewButton(0, 0, 80, 20, "Only", u.Theme, nil))
+	small.ScrollBy(0, 50)
+	if small.Scroll != (f64.Vec2{}) {
+		t.Fatalf("Scroll %v of a panel larger than its content", small.Scroll)
+	}
+}
+
+func TestTextInputEditing(t *testing.T) {
+	u := newTestUI()
+	field := NewTextInput(10, 10, 100, 20, "Name", u.Theme)
+	var changes []string
+	field.OnChange = func(text string) { changes = append(changes, text) }
+	submitted := ""
+	field.OnSubmit = func(text string) { submitted = text }
+	u.Add(field)
+
+	// Unfocused fields ignore typing
+	step(t, u, Input{Chars: []rune("x")})
+	if field.GetText() != "" {
+		t.Fatal("unfocused field took input")
+	}
+	step(t, u, Input{Next: true})
+
+	edits := []struct {
+		name  string
+		in    Input
+		text  string
+		caret int
+	}{
+		{"type", Input{Chars: []rune("abc")}, "abc", 3},
+		{"control characters are dropped", Input{Chars: []rune("\t\n")}, "abc", 3},
+		{"left", Input{Left: true}, "abc", 2},
+		{"insert at caret", Input{Chars: []rune("X")}, "abXc", 3},
+		{"backspace", Input{Backspace: true}, "abc", 2},
+		{"home", Input{Home: true}, "abc", 0},
+		{"backspace at start", Input{Backspace: true}, "abc", 0},
+		{"delete", Input{Delete: true}, "bc", 0},
+		{"left at start", Input{Left: true}, "bc", 0},
+		{"end", Input{End: true}, "bc", 2},
+		{"delete at end", Input{Delete: true}, "bc", 2},
+		{"right at end", Input{Right: true}, "bc", 2},
+		{"unicode", Input{Chars: []rune("é")}, "bcé", 3},
+	}
+	for _, e := range edits {
+		step(t, u, e.in)
+		if field.GetText() != e.text || field.Caret != e.caret {
+			t.Fatalf("%s: %q caret %d, want %q caret %d", e.name, field.GetText(), field.Caret, e.text, e.caret)
+		}
+	}
+	if len(changes) != 5 || changes[len(changes)-1] != "bcé" {
+		t.Fatalf("OnChange calls %q, want one per edit", changes)
+	}
+
+	field.MaxLength = 4
+	step(t, u, Input{Chars: []rune("def")})
+	if field.GetText() != "bcéd" {
+		t.Fatalf("text %q, want cut at MaxLength 4", field.GetText())
+	}
+
+	step(t, u, Input{Submit: true})
+	if submitted != "bcéd" {
+		t.Fatalf("OnSubmit(%q), want %q", submitted, "bcéd")
+	}
+
+	field.SetText("reset")
+	if field.Caret != 5 {
+		t.Fatalf("caret %d after SetText, want at the end", field.Caret)
+	}
+}
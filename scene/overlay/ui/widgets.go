@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/math/f64"
+)
+
+/***************** PANEL *********************/
+
+// Panel groups Widgets, children are positioned relative to the Panel
+type Panel struct {
+	Element
+	Theme      *Theme
+	Background color.Color // nil means Theme.Normal
+}
+
+func NewPanel(x, y, width, height float64, theme *Theme) *Panel {
+	return &Panel{
+		Element: Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}},
+		Theme:   theme,
+	}
+}
+
+func (p *Panel) Add(w Widget) {
+	p.AddChild(p, w)
+}
+
+func (p *Panel) Update(in *Input) error {
+	return nil
+}
+
+func (p *Panel) Draw(target ovr.Overlay, x, y float64) {
+	bg := p.Background
+	if bg == nil {
+		bg = p.Theme.Normal
+	}
+	if _, _, _, a := bg.RGBA(); a > 0 {
+		p.Theme.DrawBackground(target, x, y, p.Size[0], p.Size[1], bg)
+	}
+	DrawChildren(&p.Element, target, x, y)
+}
+
+/***************** LABEL *********************/
+
+type Label struct {
+	Element
+	Theme  *Theme
+	Text   string
+	Color  color.Color // nil means Theme.TextColor
+	Center bool
+}
+
+// Label is sized to fit its text
+func NewLabel(x, y float64, txt string, theme *Theme) *Label {
+	l := &Label{
+		Element: Element{Position: f64.Vec2{x, y}},
+		Theme:   theme,
+	}
+	l.SetText(txt)
+	return l
+}
+
+func (l *Label) SetText(txt string) {
+	l.Text = txt
+	w, h := l.Theme.MeasureText(txt)
+	l.Size = f64.Vec2{w + 2*l.Theme.Padding, h}
+}
+
+func (l *Label) Update(in *Input) error {
+	return nil
+}
+
+func (l *Label) Draw(target ovr.Overlay, x, y float64) {
+	clr := l.Color
+	if clr == nil {
+		clr = l.Theme.TextColor
+	}
+	l.Theme.DrawLabel(target, l.Text, x, y, l.Size[0], l.Size[1], l.Center, clr)
+}
+
+/***************** BUTTON *********************/
+
+type Button struct {
+	Element
+	Theme   *Theme
+	Text    string
+	OnClick func()
+}
+
+func NewButton(x, y, width, height float64, txt string, theme *Theme, onClick func()) *Button {
+	return &Button{
+		Element: Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}, Focusable: true},
+		Theme:   theme,
+		Text:    txt,
+		OnClick: onClick,
+	}
+}
+
+func (b *Button) Update(in *Input) error {
+	if b.Clicked && !b.Disabled && b.OnClick != nil {
+		b.OnClick()
+	}
+	return nil
+}
+
+func (b *Button) Draw(target ovr.Overlay, x, y float64) {
+	b.Theme.DrawFrame(target, &b.Element, x, y)
+	b.Theme.DrawLabel(target, b.Text, x, y, b.Size[0], b.Size[1], true, b.Theme.TextColor)
+}
+
+/***************** CHECKBOX *********************/
+
+type Checkbox struct {
+	Element
+	Theme    *Theme
+	Text     string
+	Checked  bool
+	OnChange func(checked bool)
+}
+
+// Height is also the size of the box, text is drawn to the right of it
+func NewCheckbox(x, y, width, height float64, txt string, theme *Theme, onChange func(checked bool)) *Checkbox {
+	return &Checkbox{
+		Element:  Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}, Focusable: true},
+		Theme:    theme,
+		Text:     txt,
+		OnChange: onChange,
+	}
+}
+
+func (c *Checkbox) Update(in *Input) error {
+	if c.Clicked && !c.Disabled {
+		c.Checked = !c.Checked
+		if c.OnChange != nil {
+			c.OnChange(c.Checked)
+		}
+	}
+	return nil
+}
+
+func (c *Checkbox) Draw(target ovr.Overlay, x, y float64) {
+	box := c.Size[1]
+	target.DrawRect(x, y, box, box, true, c.Theme.StateColor(&c.Element))
+	if c.Checked {
+		inset := math.Max(2, box/4)
+		target.DrawRect(x+inset, y+inset, box-2*inset, box-2*inset, true, c.Theme.Accent)
+	}
+	if c.Focused {
+		target.DrawRect(x, y, c.Size[0], c.Size[1], false, c.Theme.Focus)
+	}
+	c.Theme.DrawLabel(target, c.Text, x+box, y, c.Size[0]-box, c.Size[1], false, c.Theme.TextColor)
+}
+
+/***************** SLIDER *********************/
+
+type Slider struct {
+	Element
+	Theme    *Theme
+	Min      float64
+	Max      float64
+	Value    float64
+	Step     float64 // Used for keyboard/gamepad, and to snap dragged values if > 0
+	OnChange func(value float64)
+}
+
+func NewSlider(x, y, width, height, min, max, value float64, theme *Theme, onChange func(value float64)) *Slider {
+	return &Slider{
+		Element:  Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}, Focusable: true},
+		Theme:    theme,
+		Min:      min,
+		Max:      max,
+		Value:    value,
+		Step:     (max - min) / 10,
+		OnChange: onChange,
+	}
+}
+
+func (s *Slider) SetValue(value float64) {
+	if s.Step > 0 {
+		value = s.Min + math.Round((value-s.Min)/s.Step)*s.Step
+	}
+	value = math.Max(s.Min, math.Min(s.Max, value))
+	if value == s.Value {
+		return
+	}
+	s.Value = value
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
+
+func (s *Slider) Update(in *Input) error {
+	if s.Disabled {
+		return nil
+	}
+	if s.Pressed && in.MouseDown && s.Max > s.Min {
+		t := (in.CursorX - s.bounds.X) / s.bounds.W
+		s.SetValue(s.Min + t*(s.Max-s.Min))
+	}
+	if s.Focused {
+		if in.Left {
+			s.SetValue(s.Value - s.Step)
+		}
+		if in.Right {
+			s.SetValue(s.Value + s.Step)
+		}
+	}
+	return nil
+}
+
+func (s *Slider) Draw(target ovr.Overlay, x, y float64) {
+	trackH := math.Max(2, s.Size[1]/4)
+	target.DrawRect(x, y+(s.Size[1]-trackH)/2, s.Size[0], trackH, true, s.Theme.StateColor(&s.Element))
+
+	t := 0.0
+	if s.Max > s.Min {
+		t = (s.Value - s.Min) / (s.Max - s.Min)
+	}
+	knobW := math.Max(4, s.Size[1]/2)
+	target.DrawRect(x+t*(s.Size[0]-knobW), y, knobW, s.Size[1], true, s.Theme.Accent)
+
+	if s.Focused {
+		target.DrawRect(x, y, s.Size[0], s.Size[1], false, s.Theme.Focus)
+	}
+}
+
+/***************** PROGRESS BAR *********************/
+
+// ProgressBar can be drawn with plain colors, or with Images (eg: health bars)
+type ProgressBar struct {
+	Element
+	Theme     *Theme
+	Value     float64
+	Max       float64
+	FillColor color.Color   // nil means Theme.Accent
+	BackColor color.Color   // nil means Theme.Normal
+	FillImage *ebiten.Image // Cropped (not scaled) to the filled portion, drawn instead of FillColor
+	BackImage *ebiten.Image // Drawn instead of BackColor
+	ShowText  bool          // Shows "Value/Max" over the bar
+}
+
+func NewProgressBar(x, y, width, height, value, max float64, theme *Theme) *ProgressBar {
+	return &ProgressBar{
+		Element: Element{Position: f64.Vec2{x, y}, Size: f64.Vec2{width, height}},
+		Theme:   theme,
+		Value:   value,
+		Max:     max,
+	}
+}
+
+// Normalized progress (0 to 1)
+func (p *ProgressBar) Progress() float64 {
+	if p.Max <= 0 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, p.Value/p.Max))
+}
+
+func (p *ProgressBar) Update(in *Input) error {
+	return nil
+}
+
+func (p *ProgressBar) Draw(target ovr.Overlay, x, y float64) {
+	progress := p.Progress()
+
+	if p.BackImage != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(x, y)
+		target.DrawImage(p.BackImage, op)
+	} else {
+		back := p.BackColor
+		if back == nil {
+			back = p.Theme.Normal
+		}
+		target.DrawRect(x, y, p.Size[0], p.Size[1], true, back)
+	}
+
+	if p.FillImage != nil {
+		bounds := p.FillImage.Bounds()
+		w := int(float64(bounds.Dx()) * progress)
+		if w > 0 {
+			filled := p.FillImage.SubImage(image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X+w, bounds.Max.Y)).(*ebiten.Image)
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(x, y)
+			target.DrawImage(filled, op)
+		}
+	} else if progress > 0 {
+		fill := p.FillColor
+		if fill == nil {
+			fill = p.Theme.Accent
+		}
+		target.DrawRect(x, y, p.Size[0]*progress, p.Size[1], true, fill)
+	}
+
+	if p.ShowText {
+		txt := fmt.Sprintf("%.0f/%.0f", p.Value, p.Max)
+		p.Theme.DrawLabel(target, txt, x, y, p.Size[0], p.Size[1], true, p.Theme.TextColor)
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/entity"
 	shk "github.com/shubhamdwivedii/gopher-engine/scene/screen/shaker"
 	vpt "github.com/shubhamdwivedii/gopher-engine/scene/viewport"
+	"github.com/shubhamdwivedii/gopher-engine/text/richtext"
 	"github.com/shubhamdwivedii/gopher-engine/utils"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/f64"
@@ -23,12 +26,18 @@ type Screen interface {
 	GetShaker() (shaker shk.ScreenShaker)
 
 	DrawImage(image *ebiten.Image, op *ebiten.DrawImageOptions)
+	DrawEntity(image *ebiten.Image, e entity.Entity, op *ebiten.DrawImageOptions)
+	DrawIso(image *ebiten.Image, x, y, z float64, op *ebiten.DrawImageOptions)
+	DrawIsoTile(image *ebiten.Image, tx, ty, tz float64, op *ebiten.DrawImageOptions)
 	DrawLine(x1, y1, x2, y2 float64, col color.Color)
 	DrawRect(x, y, width, height float64, fill bool, col color.Color)
+	DrawNineSlice(image *ebiten.Image, x, y, width, height float64, slice utils.NineSlice, op *ebiten.DrawImageOptions)
+	DrawThreeSlice(image *ebiten.Image, x, y, length float64, slice utils.ThreeSlice, op *ebiten.DrawImageOptions)
 	Fill(col color.Color)
 	DebugPrint(text string)
 	DebugPrintAt(text string, x, y int)
 	DrawText(text string, fnt font.Face, x, y int, clr color.Color)
+	DrawRichText(text string, fnt font.Face, x, y int, opts *richtext.Options)
 }
 
 type CustomScreen struct {
@@ -89,7 +98,12 @@ func (s *CustomScreen) Update() error {
 func (s *CustomScreen) Render(targetScreen *ebiten.Image) {
 	s.DrawOP.GeoM.Reset()
 
+	pixelPerfect := s.Viewport != nil && s.Viewport.PixelPerfect
+
 	sdx, sdy := s.Shaker.GetOffsets()
+	if pixelPerfect {
+		sdx, sdy = math.Round(sdx), math.Round(sdy)
+	}
 	s.DrawOP.GeoM.Translate(-sdx, -sdy)
 
 	if s.AutoPadding && s.Viewport == nil {
@@ -101,14 +115,26 @@ func (s *CustomScreen) Render(targetScreen *ebiten.Image) {
 	}
 
 	// Scaling Screen Image to Render Resolution
+	scaleX, scaleY := 1.0, 1.0
 	if s.AutoScaling {
 		resX, resY := targetScreen.Bounds().Dx(), targetScreen.Bounds().Dy()
 		if resX != int(s.ScreenSize[0]) || resY != int(s.ScreenSize[1]) {
-			scaleX, scaleY := float64(resX)/s.ScreenSize[0], float64(resY)/s.ScreenSize[1]
+			scaleX, scaleY = float64(resX)/s.ScreenSize[0], float64(resY)/s.ScreenSize[1]
 			s.DrawOP.GeoM.Scale(scaleX, scaleY)
 		}
 	}
 
+	/* NOTE :-
+	In Pixel-Perfect mode the World is drawn snapped to whole pixels (see Viewport.GetOffsets),
+	the left over sub-pixel offset is applied here after upscaling, so scrolling stays smooth on
+	a bigger Render Resolution without texels swimming (Layout should return the window size for this).
+	*/
+	if pixelPerfect {
+		fx, fy := s.Viewport.GetSubPixelOffsets()
+		s.DrawOP.GeoM.Translate(-math.Round(fx*scaleX), -math.Round(fy*scaleY))
+		s.DrawOP.Filter = ebiten.FilterNearest
+	}
+
 	s.drawCameraFocusArea()
 
 	// Render Screen Image to Real Render Screen
@@ -165,6 +191,40 @@ func (s *CustomScreen) DrawImage(image *ebiten.Image, op *ebiten.DrawImageOption
 	utils.DrawImage(image, s.Image, op)
 }
 
+// Draws image with the Entity's World transform, op.GeoM (if any) is applied before it (in Entity's Local space)
+func (s *CustomScreen) DrawEntity(image *ebiten.Image, e entity.Entity, op *ebiten.DrawImageOptions) {
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+	op.GeoM.Concat(entity.GetMatrix(e))
+	s.DrawImage(image, op)
+}
+
+// Draws image with its bottom-center at iso point x, y, z (eg: feet of a character), Viewport must be isometric
+func (s *CustomScreen) DrawIso(image *ebiten.Image, x, y, z float64, op *ebiten.DrawImageOptions) {
+	if s.Viewport == nil || s.Viewport.Iso == nil {
+		return
+	}
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+	sx, sy := s.Viewport.Iso.ToScreen(x, y, z)
+	op.GeoM.Translate(sx-float64(image.Bounds().Dx())/2, sy-float64(image.Bounds().Dy()))
+	s.DrawImage(image, op)
+}
+
+// Draws a tile image on iso tile tx, ty at height tz (see iso.Projection.GetTileMatrix), Viewport must be isometric
+func (s *CustomScreen) DrawIsoTile(image *ebiten.Image, tx, ty, tz float64, op *ebiten.DrawImageOptions) {
+	if s.Viewport == nil || s.Viewport.Iso == nil {
+		return
+	}
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+	op.GeoM.Concat(s.Viewport.Iso.GetTileMatrix(tx, ty, tz, image.Bounds().Dx(), image.Bounds().Dy()))
+	s.DrawImage(image, op)
+}
+
 func (s *CustomScreen) Fill(col color.Color) {
 	utils.Fill(s.Image, col)
 }
@@ -179,6 +239,16 @@ func (s *CustomScreen) DrawRect(x, y, width, height float64, solid bool, clr col
 	utils.DrawRect(s.Image, x+offx, y+offy, width, height, solid, clr)
 }
 
+func (s *CustomScreen) DrawNineSlice(image *ebiten.Image, x, y, width, height float64, slice utils.NineSlice, op *ebiten.DrawImageOptions) {
+	offx, offy := s.GetOffsets()
+	utils.DrawNineSlice(image, s.Image, x+offx, y+offy, width, height, slice, op)
+}
+
+func (s *CustomScreen) DrawThreeSlice(image *ebiten.Image, x, y, length float64, slice utils.ThreeSlice, op *ebiten.DrawImageOptions) {
+	offx, offy := s.GetOffsets()
+	utils.DrawThreeSlice(image, s.Image, x+offx, y+offy, length, slice, op)
+}
+
 func (s *CustomScreen) DebugPrint(text string) {
 	utils.DebugPrint(s.Image, text)
 }
@@ -193,6 +263,12 @@ func (s *CustomScreen) DrawText(txt string, fnt font.Face, x, y int, clr color.C
 	utils.DrawText(s.Image, txt, fnt, x+int(offx), y+int(offy), clr)
 }
 
+// Wrapped, aligned text with markup (see richtext), x, y is TopLeft (not baseline)
+func (s *CustomScreen) DrawRichText(txt string, fnt font.Face, x, y int, opts *richtext.Options) {
+	offx, offy := s.GetOffsets()
+	utils.DrawRichText(s.Image, txt, fnt, x+int(offx), y+int(offy), opts)
+}
+
 // TRASH FUNC
 
 func (s *CustomScreen) drawCameraFocusArea() {
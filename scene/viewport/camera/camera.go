@@ -8,6 +8,7 @@ type Camera interface {
 	Update(x, y float64) error
 	OverflowAllowed(allowed bool)
 	GetFocus() (focusPoint f64.Vec2, focusSize f64.Vec2)
+	SetWorldSize(width, height float64)
 }
 
 type FocusBoxCam struct {
@@ -36,6 +37,10 @@ func (c *FocusBoxCam) GetFocus() (focusPoint f64.Vec2, focusSize f64.Vec2) {
 	return c.FocusPoint, c.FocusSize
 }
 
+func (c *FocusBoxCam) SetWorldSize(width, height float64) {
+	c.WorldSize = f64.Vec2{width, height}
+}
+
 func (c *FocusBoxCam) OverflowAllowed(allowed bool) {
 	c.AllowOutOfBounds = allowed
 }
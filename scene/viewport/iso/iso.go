@@ -0,0 +1,94 @@
+package iso
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/math/f64"
+)
+
+/*
+Projection maps isometric World coordinates (tile x, y on the ground plane, z is height) to Screen pixels
+Matrix holds the ground plane projection (where one tile step along x and y lands), Origin is added after it
+Any axonometric projection can be used, New gives the usual 2:1 diamond
+*/
+type Projection struct {
+	Matrix     ebiten.GeoM
+	Origin     f64.Vec2 // Pixel position of tile 0, 0 (its top corner)
+	HeightStep float64  // Pixels one unit of z moves up
+	TileSize   f64.Vec2 // Pixel size of a tile's diamond (its bounding box)
+}
+
+// Diamond tiles, tile x goes right-down and tile y goes left-down
+func New(tileWidth, tileHeight float64) *Projection {
+	return NewFromAxes(
+		f64.Vec2{tileWidth / 2, tileHeight / 2},
+		f64.Vec2{-tileWidth / 2, tileHeight / 2},
+		tileHeight,
+	)
+}
+
+// Custom projection, xAxis and yAxis are pixel steps of one tile along x and y, heightStep is pixels per unit of z
+func NewFromAxes(xAxis, yAxis f64.Vec2, heightStep float64) *Projection {
+	p := &Projection{HeightStep: heightStep}
+	p.Matrix.SetElement(0, 0, xAxis[0])
+	p.Matrix.SetElement(1, 0, xAxis[1])
+	p.Matrix.SetElement(0, 1, yAxis[0])
+	p.Matrix.SetElement(1, 1, yAxis[1])
+	p.TileSize = f64.Vec2{math.Abs(xAxis[0]) + math.Abs(yAxis[0]), math.Abs(xAxis[1]) + math.Abs(yAxis[1])}
+	return p
+}
+
+// Pixel position of an iso point (used for drawing and for Camera.Update)
+func (p *Projection) ToScreen(x, y, z float64) (sx, sy float64) {
+	sx, sy = p.Matrix.Apply(x, y)
+	return sx + p.Origin[0], sy + p.Origin[1] - z*p.HeightStep
+}
+
+// Iso point on the plane at height z under a pixel position, ok is false for degenerate projections
+func (p *Projection) ToWorld(sx, sy, z float64) (x, y float64, ok bool) {
+	inverse := p.Matrix
+	if !inverse.IsInvertible() {
+		return 0, 0, false
+	}
+	inverse.Invert()
+	x, y = inverse.Apply(sx-p.Origin[0], sy-p.Origin[1]+z*p.HeightStep)
+	return x, y, true
+}
+
+// Tile on the plane at height z under a pixel position
+func (p *Projection) TileAt(sx, sy, z float64) (tx, ty int) {
+	x, y, _ := p.ToWorld(sx, sy, z)
+	return int(math.Floor(x)), int(math.Floor(y))
+}
+
+/*
+Topmost tile under a pixel position for stacked/elevated maps
+Heights are tested from maxZ down to 0, hasTile reports if a tile exists at tx, ty, tz
+*/
+func (p *Projection) Pick(sx, sy float64, maxZ int, hasTile func(tx, ty, tz int) bool) (tx, ty, tz int, ok bool) {
+	for tz = maxZ; tz >= 0; tz-- {
+		tx, ty = p.TileAt(sx, sy, float64(tz))
+		if hasTile(tx, ty, tz) {
+			return tx, ty, tz, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+/*
+Matrix for drawing a tile image at tile x, y, z
+Tile images are expected to have the diamond's top corner at the horizontal center of the image,
+with anything taller than the diamond (walls, trees) sticking out above the diamond
+*/
+func (p *Projection) GetTileMatrix(x, y, z float64, imageWidth, imageHeight int) ebiten.GeoM {
+	sx, sy := p.ToScreen(x, y, z)
+	m := ebiten.GeoM{}
+	m.Translate(sx-float64(imageWidth)/2, sy+p.TileSize[1]-float64(imageHeight))
+	return m
+}
+
+// Depth of an iso point, higher is nearer (drawn later)
+func Depth(x, y, z float64) float64 {
+	return x + y + z
+}
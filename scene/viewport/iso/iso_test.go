@@ -0,0 +1,100 @@
+package iso
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+func TestToScreenToWorld(t *testing.T) {
+	p := New(64, 32)
+	p.Origin = f64.Vec2{100, 50}
+	tests := []struct {
+		name    string
+		x, y, z float64
+		sx, sy  float64
+	}{
+		{"origin", 0, 0, 0, 100, 50},
+		{"x goes right-down", 1, 0, 0, 132, 66},
+		{"y goes left-down", 0, 1, 0, 68, 66},
+		{"height goes up", 0, 0, 1, 100, 18},
+		{"fractional", 2.5, 1.5, 0.5, 132, 98},
+		{"negative", -1, -2, 0, 132, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sx, sy := p.ToScreen(tt.x, tt.y, tt.z)
+			if sx != tt.sx || sy != tt.sy {
+				t.Fatalf("ToScreen() = %v, %v, want %v, %v", sx, sy, tt.sx, tt.sy)
+			}
+			x, y, ok := p.ToWorld(sx, sy, tt.z)
+			if !ok || math.Abs(x-tt.x) > 1e-9 || math.Abs(y-tt.y) > 1e-9 {
+				t.Fatalf("ToWorld() = %v, %v, %v, want %v, %v", x, y, ok, tt.x, tt.y)
+			}
+		})
+	}
+
+	// Parallel axes can't be inverted
+	flat := NewFromAxes(f64.Vec2{32, 16}, f64.Vec2{64, 32}, 16)
+	if _, _, ok := flat.ToWorld(10, 10, 0); ok {
+		t.Fatal("ToWorld() of a degenerate projection reported ok")
+	}
+}
+
+func TestTileAt(t *testing.T) {
+	p := New(64, 32)
+	tests := []struct {
+		name   string
+		sx, sy float64
+		z      float64
+		tx, ty int
+	}{
+		{"top corner", 0, 0.5, 0, 0, 0},
+		{"center", 0, 16, 0, 0, 0},
+		{"right neighbour", 32, 17, 0, 1, 0},
+		{"left neighbour", -32, 17, 0, 0, 1},
+		{"above the origin", 0, -1, 0, -1, -1},
+		{"raised plane", 0, -16, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tx, ty := p.TileAt(tt.sx, tt.sy, tt.z); tx != tt.tx || ty != tt.ty {
+				t.Fatalf("TileAt() = %d, %d, want %d, %d", tx, ty, tt.tx, tt.ty)
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	p := New(64, 32)
+	p.Origin = f64.Vec2{100, 50}
+	// Top of tile 1, 1 at z 1, the same pixel is tile 0, 0 on the ground and tile 2, 2 at z 2
+	sx, sy := p.ToScreen(1.5, 1.5, 1)
+
+	tests := []struct {
+		name       string
+		tiles      [][3]int
+		maxZ       int
+		tx, ty, tz int
+		ok         bool
+	}{
+		{"raised tile hides the ground", [][3]int{{1, 1, 1}, {0, 0, 0}}, 2, 1, 1, 1, true},
+		{"ground only", [][3]int{{0, 0, 0}}, 2, 0, 0, 0, true},
+		{"highest first", [][3]int{{2, 2, 2}, {1, 1, 1}}, 2, 2, 2, 2, true},
+		{"above maxZ is ignored", [][3]int{{1, 1, 1}}, 0, 0, 0, 0, false},
+		{"nothing", nil, 2, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			has := map[[3]int]bool{}
+			for _, tile := range tt.tiles {
+				has[tile] = true
+			}
+			tx, ty, tz, ok := p.Pick(sx, sy, tt.maxZ, func(tx, ty, tz int) bool { return has[[3]int{tx, ty, tz}] })
+			if tx != tt.tx || ty != tt.ty || tz != tt.tz || ok != tt.ok {
+				t.Fatalf("Pick() = %d, %d, %d, %v, want %d, %d, %d, %v", tx, ty, tz, ok, tt.tx, tt.ty, tt.tz, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package iso
+
+import (
+	"sort"
+)
+
+// Bounding box of an object in iso World units, X, Y, Z is the corner nearest to tile 0, 0 at ground level
+type Box struct {
+	X, Y, Z float64
+	W, D, H float64 // Size along x, y and z
+}
+
+// Anything that can be depth sorted
+type Sortable interface {
+	GetIsoBox() Box
+}
+
+// a is entirely behind b along some axis (so a must be drawn first)
+func behind(a, b Box) bool {
+	const eps = 1e-9
+	return a.X+a.W <= b.X+eps || a.Y+a.D <= b.Y+eps || a.Z+a.H <= b.Z+eps
+}
+
+func (b Box) depth() float64 {
+	return Depth(b.X+b.W/2, b.Y+b.D/2, b.Z+b.H/2)
+}
+
+/*
+Sorts items back to front following iso rules:
+an item entirely behind another on any axis is drawn first, otherwise their center depth decides
+Boxes are compared pairwise (topological sort), fine for the few hundred items usually on screen
+*/
+func Sort(items []Sortable) {
+	n := len(items)
+	if n < 2 {
+		return
+	}
+	boxes := make([]Box, n)
+	for i, it := range items {
+		boxes[i] = it.GetIsoBox()
+	}
+
+	// Start from depth order, so ties and cycles fall back to it
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return boxes[order[i]].depth() < boxes[order[j]].depth()
+	})
+
+	// behindOf[i] lists items that must be drawn before i
+	behindOf := make([][]int, n)
+	for _, i := range order {
+		for _, j := range order {
+			if i == j {
+				continue
+			}
+			bi, bj := boxes[i], boxes[j]
+			if behind(bj, bi) && !behind(bi, bj) {
+				behindOf[i] = append(behindOf[i], j)
+			}
+		}
+	}
+
+	visited := make([]uint8, n) // 0 new, 1 visiting, 2 done
+	sorted := make([]Sortable, 0, n)
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] != 0 {
+			return // Done, or a cycle (broken by depth order)
+		}
+		visited[i] = 1
+		for _, j := range behindOf[i] {
+			visit(j)
+		}
+		visited[i] = 2
+		sorted = append(sorted, items[i])
+	}
+	for _, i := range order {
+		visit(i)
+	}
+	copy(items, sorted)
+}
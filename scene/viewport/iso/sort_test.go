@@ -0,0 +1,69 @@
+package iso
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testItem struct {
+	name string
+	box  Box
+}
+
+func (i testItem) GetIsoBox() Box {
+	return i.box
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []testItem
+		want  []string
+	}{
+		{"ground tiles", []testItem{
+			{"front", Box{1, 1, 0, 1, 1, 0}},
+			{"right", Box{1, 0, 0, 1, 1, 0}},
+			{"back", Box{0, 0, 0, 1, 1, 0}},
+		}, []string{"back", "right", "front"}},
+		// Center depth of the long wall is nearer, but it's entirely behind the box along y
+		{"long wall behind a box", []testItem{
+			{"wall", Box{0, 1, 0, 10, 1, 2}},
+			{"box", Box{0, 2, 0, 1, 1, 1}},
+		}, []string{"wall", "box"}},
+		// Standing on a wide platform, the center of the character is farther
+		{"character on a platform", []testItem{
+			{"character", Box{0, 0, 1, 1, 1, 2}},
+			{"platform", Box{0, 0, 0, 3, 3, 1}},
+		}, []string{"platform", "character"}},
+		{"overlapping boxes use depth", []testItem{
+			{"b", Box{1, 1, 0, 2, 2, 1}},
+			{"a", Box{0, 0, 0, 2, 2, 1}},
+		}, []string{"a", "b"}},
+		{"touching boxes", []testItem{
+			{"b", Box{2, 0, 0, 2, 2, 1}},
+			{"a", Box{0, 0, 0, 2, 2, 1}},
+		}, []string{"a", "b"}},
+		{"mixed", []testItem{
+			{"character", Box{3, 2.5, 1, 1, 1, 2}},
+			{"tree", Box{6, 0, 0, 1, 1, 3}},
+			{"wall", Box{0, 2, 0, 10, 0.5, 2}},
+			{"platform", Box{2, 2.5, 0, 3, 3, 1}},
+		}, []string{"tree", "wall", "platform", "character"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]Sortable, len(tt.items))
+			for i := range tt.items {
+				items[i] = tt.items[i]
+			}
+			Sort(items)
+			var got []string
+			for _, it := range items {
+				got = append(got, it.(testItem).name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Sort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
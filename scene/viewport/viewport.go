@@ -7,6 +7,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	. "github.com/shubhamdwivedii/gopher-engine/constants"
 	cam "github.com/shubhamdwivedii/gopher-engine/scene/viewport/camera"
+	"github.com/shubhamdwivedii/gopher-engine/scene/viewport/iso"
 	"golang.org/x/image/math/f64"
 )
 
@@ -20,7 +21,9 @@ type Viewport struct {
 	ZoomFactor       int      // Used to Zoom in and out of World
 	Rotation         int      // Used to Rotate the Viewport
 	AllowOutOfBounds bool     // Viewport can go outside of the World
+	PixelPerfect     bool     // World is drawn at whole pixels, sub-pixel remainder is applied when upscaling
 	Camera           cam.Camera
+	Iso              *iso.Projection // Isometric mode if not nil, World pixels are projected iso coordinates
 }
 
 func New(screenWidth, screenHeight, worldWidth, worldHeight int, centreX, centreY float64) *Viewport {
@@ -41,6 +44,35 @@ func (v *Viewport) SetMargin(margin float64) {
 	v.Margin = margin
 }
 
+// Changes the World dimensions (eg: when a new map is loaded), Camera bounds are updated too
+func (v *Viewport) SetWorldSize(width, height float64) {
+	v.WorldSize = f64.Vec2{width, height}
+	v.WorldCenter = f64.Vec2{width / 2, height / 2}
+	if v.Camera != nil {
+		v.Camera.SetWorldSize(width, height)
+	}
+}
+
+// Pixel-Perfect mode is meant for Pixel-Art, avoids texel swimming/shimmering
+func (v *Viewport) SetPixelPerfect(pixelPerfect bool) {
+	v.PixelPerfect = pixelPerfect
+}
+
+// Position of the Viewport snapped to whole pixels (floored)
+func (v *Viewport) GetSnappedPosition() f64.Vec2 {
+	return f64.Vec2{math.Floor(v.Position[0]), math.Floor(v.Position[1])}
+}
+
+// Fractional part of the Position that is lost when snapping (always 0 <= fx, fy < 1)
+// Applied by Screen after upscaling to get smooth scrolling in Pixel-Perfect mode
+func (v *Viewport) GetSubPixelOffsets() (fx, fy float64) {
+	if !v.PixelPerfect {
+		return 0, 0
+	}
+	snapped := v.GetSnappedPosition()
+	return v.Position[0] - snapped[0], v.Position[1] - snapped[1]
+}
+
 // Get Center point Of Viewport in the World
 func (v *Viewport) GetCenter() (cx, cy float64) {
 	return v.Position[0] + v.ViewSize[0]/2, v.Position[1] + v.ViewSize[1]/2
@@ -97,6 +129,26 @@ func (v *Viewport) GetRotation() float64 {
 	return float64(v.Rotation)
 }
 
+/*
+Area of the World that is visible (x, y is TopLeft), adjusted for Zoom and Rotation
+Meant for culling, it's the bounding box of the World points at the corners of the Screen
+*/
+func (v *Viewport) GetVisibleRect() (x, y, w, h float64) {
+	width, height := int(math.Ceil(v.ViewSize[0])), int(math.Ceil(v.ViewSize[1]))
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [4][2]int{{0, 0}, {width, 0}, {0, height}, {width, height}} {
+		world := v.ScreenToWorldPosition(corner[0], corner[1])
+		if math.IsNaN(world[0]) {
+			// Not invertible, nothing sensible to cull against
+			return v.Position[0], v.Position[1], v.ViewSize[0], v.ViewSize[1]
+		}
+		minX, minY = math.Min(minX, world[0]), math.Min(minY, world[1])
+		maxX, maxY = math.Max(maxX, world[0]), math.Max(maxY, world[1])
+	}
+	return minX, minY, maxX - minX, maxY - minY
+}
+
 func (v *Viewport) GetMatrix() ebiten.GeoM {
 	matrix := ebiten.GeoM{}
 	// matrix.Translate(-v.Position[0], -v.Position[1])
@@ -148,6 +200,33 @@ func (v *Viewport) ScreenToWorld(posX, posY int) f64.Vec2 {
 	return f64.Vec2{math.NaN(), math.NaN()}
 }
 
+/*
+Like ScreenToWorld but includes the Viewport's Position,
+Screen Image is drawn offset by it (see GetOffsets), so this is the World point under the cursor
+*/
+func (v *Viewport) ScreenToWorldPosition(posX, posY int) f64.Vec2 {
+	world := v.ScreenToWorld(posX, posY)
+	return f64.Vec2{world[0] + v.Position[0], world[1] + v.Position[1]}
+}
+
+// Switches to isometric mode (nil switches back), Camera still follows World pixels (see iso.Projection.ToScreen)
+func (v *Viewport) SetIsometric(projection *iso.Projection) {
+	v.Iso = projection
+}
+
+// Iso tile (on the ground plane) under a Screen position, ok is false if not in isometric mode
+func (v *Viewport) ScreenToTile(posX, posY int) (tx, ty int, ok bool) {
+	if v.Iso == nil {
+		return 0, 0, false
+	}
+	world := v.ScreenToWorldPosition(posX, posY)
+	if math.IsNaN(world[0]) {
+		return 0, 0, false
+	}
+	tx, ty = v.Iso.TileAt(world[0], world[1], 0)
+	return tx, ty, true
+}
+
 func (v *Viewport) Reset() {
 	v.Position[0] = v.InitialPosition[0]
 	v.Position[1] = v.InitialPosition[1]
@@ -204,7 +283,13 @@ func (v *Viewport) RoatateBy(dr int) {
 func (v *Viewport) GetOffsets() (float64, float64) {
 	// Right +ve, Left -ve, Up -ve, Down +ve
 
-	cx, cy := v.Position[0]+v.ViewSize[0]/2, v.Position[1]+v.ViewSize[1]/2 // Center point of the Viewport
+	position := v.Position
+	if v.PixelPerfect {
+		// World is always drawn at whole pixels, remainder is handled by Screen while rendering
+		position = v.GetSnappedPosition()
+	}
+
+	cx, cy := position[0]+v.ViewSize[0]/2, position[1]+v.ViewSize[1]/2 // Center point of the Viewport
 	// dx, dy := cx-v.WorldSize[0]/2, cy-v.WorldSize[1]/2
 	dx, dy := cx-v.ViewSize[0]/2, cy-v.ViewSize[1]/2
 
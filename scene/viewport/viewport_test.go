@@ -0,0 +1,83 @@
+package viewport
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shubhamdwivedii/gopher-engine/scene/viewport/iso"
+	"golang.org/x/image/math/f64"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestGetVisibleRect(t *testing.T) {
+	// Screen point q is World (q - center) / scale (then rotated back) + center + Position,
+	// center is GetCenter() (Position + ViewSize/2) like in GetMatrix
+	s := math.Pow(1.01, 70) // ZoomFactor 70, about 2x
+	tests := []struct {
+		name       string
+		position   f64.Vec2
+		zoom, rot  int
+		x, y, w, h float64
+	}{
+		{"none", f64.Vec2{50, 30}, 0, 0, 50, 30, 320, 240},
+		{"zoom", f64.Vec2{50, 0}, 70, 0, 260 - 210/s, 120 - 120/s, 320 / s, 240 / s},
+		{"rotation", f64.Vec2{50, 30}, 0, 90, 110, 70, 240, 320},
+		{"zoom and rotation", f64.Vec2{50, 30}, 70, 90, 260 - 150/s, 180 - 110/s, 240 / s, 320 / s},
+		{"zoom out", f64.Vec2{-20, 10}, -70, 0, 120 - 140*s, 140 - 130*s, 320 * s, 240 * s},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(320, 240, 1000, 1000, 160, 120)
+			v.Position = tt.position
+			v.ZoomFactor, v.Rotation = tt.zoom, tt.rot
+			x, y, w, h := v.GetVisibleRect()
+			if !near(x, tt.x) || !near(y, tt.y) || !near(w, tt.w) || !near(h, tt.h) {
+				t.Fatalf("GetVisibleRect() = %v %v %v %v, want %v %v %v %v", x, y, w, h, tt.x, tt.y, tt.w, tt.h)
+			}
+
+			// Everything on Screen is inside
+			for sx := 0; sx <= 320; sx += 40 {
+				for sy := 0; sy <= 240; sy += 40 {
+					p := v.ScreenToWorldPosition(sx, sy)
+					if p[0] < x-1e-6 || p[0] > x+w+1e-6 || p[1] < y-1e-6 || p[1] > y+h+1e-6 {
+						t.Fatalf("Screen %d,%d is World %v, outside the visible rect", sx, sy, p)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestScreenToTile(t *testing.T) {
+	v := New(320, 240, 1000, 1000, 160, 120)
+	if _, _, ok := v.ScreenToTile(10, 10); ok {
+		t.Fatal("ScreenToTile() without a projection reported ok")
+	}
+	v.SetIsometric(iso.New(64, 32))
+
+	tests := []struct {
+		name     string
+		sx, sy   int
+		position f64.Vec2
+		zoom     int
+		tx, ty   int
+	}{
+		{"no zoom", 10, 150, f64.Vec2{50, 30}, 0, 6, 4},
+		{"zoom in", 10, 150, f64.Vec2{50, 30}, 70, 8, 3},
+		{"zoom in at 0, 0", 10, 150, f64.Vec2{}, 70, 5, 2},
+		{"zoom out", 300, 20, f64.Vec2{50, 30}, -70, 4, -10},
+		{"negative position", 300, 20, f64.Vec2{-100, 0}, 0, 3, -3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v.Position, v.ZoomFactor = tt.position, tt.zoom
+			tx, ty, ok := v.ScreenToTile(tt.sx, tt.sy)
+			if !ok || tx != tt.tx || ty != tt.ty {
+				t.Fatalf("ScreenToTile() = %d, %d, %v, want %d, %d", tx, ty, ok, tt.tx, tt.ty)
+			}
+		})
+	}
+}
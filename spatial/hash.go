@@ -0,0 +1,300 @@
+package spatial
+
+import (
+	"math"
+
+	vpt "github.com/shubhamdwivedii/gopher-engine/scene/viewport"
+)
+
+// Handle of an item in the Hash, returned by Insert
+type ID uint32
+
+type cell struct {
+	x, y int
+}
+
+type item struct {
+	bounds AABB
+	data   interface{}
+	min    cell // Range of cells the item is in (inclusive)
+	max    cell
+	mark   uint32 // Last query that visited this item, used to skip duplicates
+}
+
+/*
+Hash is a uniform Spatial Hash Grid, items (AABBs) are bucketed into every cell they overlap
+CellSize should be around the size of a typical item (eg: 2x the size of a character)
+*/
+type Hash struct {
+	CellSize float64
+	cells    map[cell][]ID
+	items    map[ID]*item
+	nextID   ID
+	query    uint32
+	lo, hi   cell // Range of cells that ever had items (inclusive), bounds the Raycast walk
+}
+
+func NewHash(cellSize float64) *Hash {
+	if cellSize <= 0 {
+		cellSize = 64
+	}
+	return &Hash{
+		CellSize: cellSize,
+		cells:    map[cell][]ID{},
+		items:    map[ID]*item{},
+		nextID:   1,
+	}
+}
+
+func (h *Hash) cellAt(x, y float64) cell {
+	return cell{int(math.Floor(x / h.CellSize)), int(math.Floor(y / h.CellSize))}
+}
+
+// Cells overlapped by an AABB (right/bottom edges are exclusive)
+func (h *Hash) cellRange(b AABB) (min, max cell) {
+	min = h.cellAt(b.X, b.Y)
+	max = h.cellAt(math.Max(b.X, math.Nextafter(b.Right(), b.X)), math.Max(b.Y, math.Nextafter(b.Bottom(), b.Y)))
+	return min, max
+}
+
+func (h *Hash) Len() int {
+	return len(h.items)
+}
+
+// Adds an AABB with any user data (eg: an ecs.Entity or a *Gopher)
+func (h *Hash) Insert(bounds AABB, data interface{}) ID {
+	id := h.nextID
+	h.nextID++
+	it := &item{bounds: bounds, data: data}
+	it.min, it.max = h.cellRange(bounds)
+	h.items[id] = it
+	h.addToCells(id, it.min, it.max)
+	return id
+}
+
+// Updates bounds of an item, only touches cells if the covered range changed
+func (h *Hash) Move(id ID, bounds AABB) {
+	it, ok := h.items[id]
+	if !ok {
+		return
+	}
+	it.bounds = bounds
+	min, max := h.cellRange(bounds)
+	if min == it.min && max == it.max {
+		return
+	}
+	h.removeFromCells(id, it.min, it.max)
+	it.min, it.max = min, max
+	h.addToCells(id, min, max)
+}
+
+func (h *Hash) Remove(id ID) {
+	it, ok := h.items[id]
+	if !ok {
+		return
+	}
+	h.removeFromCells(id, it.min, it.max)
+	delete(h.items, id)
+}
+
+func (h *Hash) Clear() {
+	h.cells = map[cell][]ID{}
+	h.items = map[ID]*item{}
+	h.lo, h.hi = cell{}, cell{}
+}
+
+func (h *Hash) Get(id ID) (bounds AABB, data interface{}, ok bool) {
+	it, ok := h.items[id]
+	if !ok {
+		return AABB{}, nil, false
+	}
+	return it.bounds, it.data, true
+}
+
+func (h *Hash) addToCells(id ID, min, max cell) {
+	if len(h.cells) == 0 {
+		h.lo, h.hi = min, max
+	} else {
+		h.lo = cell{minInt(h.lo.x, min.x), minInt(h.lo.y, min.y)}
+		h.hi = cell{maxInt(h.hi.x, max.x), maxInt(h.hi.y, max.y)}
+	}
+	for y := min.y; y <= max.y; y++ {
+		for x := min.x; x <= max.x; x++ {
+			c := cell{x, y}
+			h.cells[c] = append(h.cells[c], id)
+		}
+	}
+}
+
+func (h *Hash) removeFromCells(id ID, min, max cell) {
+	for y := min.y; y <= max.y; y++ {
+		for x := min.x; x <= max.x; x++ {
+			c := cell{x, y}
+			ids := h.cells[c]
+			for i, other := range ids {
+				if other == id {
+					ids[i] = ids[len(ids)-1]
+					ids = ids[:len(ids)-1]
+					break
+				}
+			}
+			if len(ids) == 0 {
+				delete(h.cells, c)
+			} else {
+				h.cells[c] = ids
+			}
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// True once a ray stepping from c along (dx, dy) can't reach any occupied cell
+func (h *Hash) leftRange(c cell, dx, dy float64) bool {
+	past := func(i, lo, hi int, d float64) bool {
+		return (d >= 0 && i > hi) || (d <= 0 && i < lo)
+	}
+	return past(c.x, h.lo.x, h.hi.x, dx) || past(c.y, h.lo.y, h.hi.y, dy)
+}
+
+/***************** QUERIES *********************/
+
+// Calls fn for every item overlapping area, return false from fn to stop early
+func (h *Hash) QueryRect(area AABB, fn func(id ID, bounds AABB, data interface{}) bool) {
+	h.query++
+	min, max := h.cellRange(area)
+	for y := min.y; y <= max.y; y++ {
+		for x := min.x; x <= max.x; x++ {
+			for _, id := range h.cells[cell{x, y}] {
+				it := h.items[id]
+				if it.mark == h.query {
+					continue
+				}
+				it.mark = h.query
+				if it.bounds.Overlaps(area) && !fn(id, it.bounds, it.data) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IDs of all items overlapping area
+func (h *Hash) Rect(area AABB) []ID {
+	var ids []ID
+	h.QueryRect(area, func(id ID, bounds AABB, data interface{}) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+// IDs of all items containing the point
+func (h *Hash) Point(x, y float64) []ID {
+	var ids []ID
+	for _, id := range h.cells[h.cellAt(x, y)] {
+		if h.items[id].bounds.Contains(x, y) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Items visible in the Viewport (for culling)
+func (h *Hash) QueryViewport(v *vpt.Viewport, fn func(id ID, bounds AABB, data interface{}) bool) {
+	x, y, w, hh := v.GetVisibleRect()
+	h.QueryRect(AABB{x, y, w, hh}, fn)
+}
+
+// Result of a Raycast
+type Hit struct {
+	ID   ID
+	Data interface{}
+	T    float64 // Distance along the (normalized) ray
+	X, Y float64 // Point of entry
+}
+
+/*
+Casts a ray from (ox, oy) in direction (dx, dy) up to maxDist, returns the nearest hit
+filter (optional) can skip items, eg: the caster itself
+Cells are walked in order (DDA), so it stops as soon as the nearest hit is certain,
+or when the ray leaves the cells that hold items (so maxDist can be math.Inf(1))
+*/
+func (h *Hash) Raycast(ox, oy, dx, dy, maxDist float64, filter func(id ID, data interface{}) bool) (Hit, bool) {
+	length := math.Hypot(dx, dy)
+	if length == 0 || len(h.cells) == 0 {
+		return Hit{}, false
+	}
+	dx, dy = dx/length, dy/length
+	h.query++
+
+	c := h.cellAt(ox, oy)
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+
+	// t at which the ray crosses the next cell boundary on each axis
+	boundary := func(o, d float64, index, step int) (next, delta float64) {
+		if d == 0 {
+			return math.Inf(1), math.Inf(1)
+		}
+		edge := float64(index) * h.CellSize
+		if step > 0 {
+			edge += h.CellSize
+		}
+		return (edge - o) / d, h.CellSize / math.Abs(d)
+	}
+	nextX, deltaX := boundary(ox, dx, c.x, stepX)
+	nextY, deltaY := boundary(oy, dy, c.y, stepY)
+
+	best := Hit{T: math.Inf(1)}
+	found := false
+	cellStart := 0.0
+
+	for cellStart <= maxDist && !h.leftRange(c, dx, dy) {
+		for _, id := range h.cells[c] {
+			it := h.items[id]
+			if it.mark == h.query {
+				continue
+			}
+			it.mark = h.query
+			if filter != nil && !filter(id, it.data) {
+				continue
+			}
+			if t, ok := it.bounds.Raycast(ox, oy, dx, dy, maxDist); ok && t < best.T {
+				best = Hit{ID: id, Data: it.data, T: t, X: ox + dx*t, Y: oy + dy*t}
+				found = true
+			}
+		}
+
+		cellEnd := math.Min(nextX, nextY)
+		if found && best.T <= cellEnd {
+			break // Nothing in later cells can be nearer
+		}
+		cellStart = cellEnd
+		if nextX < nextY {
+			c.x += stepX
+			nextX += deltaX
+		} else {
+			c.y += stepY
+			nextY += deltaY
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,185 @@
+package spatial
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func sortedIDs(ids []ID) []ID {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestQueryRectVisitsItemOnce(t *testing.T) {
+	h := NewHash(10)
+	// Spans 4x4 cells
+	big := h.Insert(NewAABB(5, 5, 30, 30), "big")
+	small := h.Insert(NewAABB(1, 1, 2, 2), "small")
+	far := h.Insert(NewAABB(100, 100, 5, 5), "far")
+
+	visits := map[ID]int{}
+	h.QueryRect(NewAABB(0, 0, 50, 50), func(id ID, bounds AABB, data interface{}) bool {
+		visits[id]++
+		return true
+	})
+	if visits[big] != 1 || visits[small] != 1 {
+		t.Fatalf("visits %v, want big and small once each", visits)
+	}
+	if visits[far] != 0 {
+		t.Fatal("item outside the area was visited")
+	}
+
+	// Second query must not be affected by marks of the first one
+	if ids := h.Rect(NewAABB(20, 20, 1, 1)); len(ids) != 1 || ids[0] != big {
+		t.Fatalf("Rect() = %v, want [%d]", ids, big)
+	}
+}
+
+func TestQueryRectStopsEarly(t *testing.T) {
+	h := NewHash(10)
+	for i := 0; i < 5; i++ {
+		h.Insert(NewAABB(float64(i*10), 0, 5, 5), i)
+	}
+	calls := 0
+	h.QueryRect(NewAABB(0, 0, 50, 10), func(id ID, bounds AABB, data interface{}) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("fn called %d times after returning false", calls)
+	}
+}
+
+func TestMoveAcrossCells(t *testing.T) {
+	h := NewHash(10)
+	id := h.Insert(NewAABB(1, 1, 5, 5), nil)
+
+	// Within the same cell
+	h.Move(id, NewAABB(2, 2, 5, 5))
+	if ids := h.Point(3, 3); len(ids) != 1 {
+		t.Fatalf("Point() = %v after moving inside a cell", ids)
+	}
+
+	// Into a range of other cells
+	h.Move(id, NewAABB(25, 25, 15, 5))
+	if ids := h.Rect(NewAABB(0, 0, 10, 10)); len(ids) != 0 {
+		t.Fatalf("item still found in its old cell: %v", ids)
+	}
+	if ids := h.Point(38, 27); len(ids) != 1 || ids[0] != id {
+		t.Fatalf("Point() = %v, want [%d] in a new cell", ids, id)
+	}
+	if _, ok := h.cells[cell{0, 0}]; ok {
+		t.Fatal("empty old cell wasn't deleted")
+	}
+	for c := range h.cells {
+		if c.x < 2 || c.x > 3 || c.y != 2 {
+			t.Fatalf("item is in unexpected cell %v", c)
+		}
+	}
+
+	h.Remove(id)
+	if len(h.cells) != 0 || h.Len() != 0 {
+		t.Fatal("Remove left the item behind")
+	}
+}
+
+func TestRaycastNearestAndOrdered(t *testing.T) {
+	h := NewHash(10)
+	near := h.Insert(NewAABB(30, 0, 5, 10), "near")
+	farther := h.Insert(NewAABB(60, 0, 5, 10), "far")
+	h.Insert(NewAABB(30, 50, 5, 5), "off-ray")
+
+	hit, ok := h.Raycast(0, 5, 1, 0, 1000, nil)
+	if !ok || hit.ID != near {
+		t.Fatalf("hit %+v, want %d", hit, near)
+	}
+	if hit.T != 30 || hit.X != 30 || hit.Y != 5 {
+		t.Fatalf("hit at t=%v (%v, %v), want t=30 (30, 5)", hit.T, hit.X, hit.Y)
+	}
+
+	// Filter skips the nearest, the next one along the ray is returned
+	hit, ok = h.Raycast(0, 5, 1, 0, 1000, func(id ID, data interface{}) bool { return id != near })
+	if !ok || hit.ID != farther || hit.T != 60 {
+		t.Fatalf("filtered hit %+v, want %d at t=60", hit, farther)
+	}
+
+	// Direction is normalized, maxDist is a distance
+	if _, ok := h.Raycast(0, 5, 10, 0, 25, nil); ok {
+		t.Fatal("hit beyond maxDist")
+	}
+
+	// Backwards
+	hit, ok = h.Raycast(100, 5, -1, 0, 1000, nil)
+	if !ok || hit.ID != farther || hit.T != 35 {
+		t.Fatalf("backwards hit %+v, want %d at t=35", hit, farther)
+	}
+}
+
+func TestRaycastEarlyOut(t *testing.T) {
+	h := NewHash(10)
+	h.Insert(NewAABB(12, 0, 2, 10), "first")
+	h.Insert(NewAABB(500, 0, 2, 10), "far")
+
+	visited := map[interface{}]bool{}
+	hit, ok := h.Raycast(0, 5, 1, 0, 1000, func(id ID, data interface{}) bool {
+		visited[data] = true
+		return true
+	})
+	if !ok || hit.Data != "first" {
+		t.Fatalf("hit %+v, want first", hit)
+	}
+	if visited["far"] {
+		t.Fatal("cells past the nearest hit were walked")
+	}
+}
+
+func TestRaycastSpanningItemFoundInLaterCell(t *testing.T) {
+	h := NewHash(10)
+	// Starts in an earlier cell than the one where the ray enters it
+	long := h.Insert(NewAABB(0, 20, 10, 40), "long")
+	short := h.Insert(NewAABB(3, 45, 4, 4), "short")
+
+	hit, ok := h.Raycast(5, 0, 0, 1, 100, nil)
+	if !ok || hit.ID != long || math.Abs(hit.T-20) > 1e-9 {
+		t.Fatalf("hit %+v, want %d at t=20", hit, long)
+	}
+	if ids := sortedIDs(h.Point(5, 46)); len(ids) != 2 || ids[0] != long || ids[1] != short {
+		t.Fatalf("Point() = %v, want both items", ids)
+	}
+}
+
+func TestRaycastInfiniteMiss(t *testing.T) {
+	h := NewHash(10)
+	if _, ok := h.Raycast(0, 0, 1, 0, math.Inf(1), nil); ok {
+		t.Fatal("hit in an empty Hash")
+	}
+	h.Insert(NewAABB(30, 0, 5, 10), "box")
+
+	rays := []struct {
+		name           string
+		ox, oy, dx, dy float64
+		hit            bool
+	}{
+		{"away", 0, 5, -1, 0, false},
+		{"passing by", 0, 50, 1, 0, false},
+		{"diagonal miss", 0, 0, 1, 3, false},
+		{"vertical outside", 100, -50, 0, 1, false},
+		{"from far outside", -1000, 5, 1, 0, true},
+		{"diagonal hit", 20, -2, 1, 1, true},
+	}
+	for _, r := range rays {
+		t.Run(r.name, func(t *testing.T) {
+			if _, ok := h.Raycast(r.ox, r.oy, r.dx, r.dy, math.Inf(1), nil); ok != r.hit {
+				t.Fatalf("Raycast() hit %v, want %v", ok, r.hit)
+			}
+		})
+	}
+
+	// Removed items still widen the range, rays past them stop anyway
+	id := h.Insert(NewAABB(-500, 0, 5, 5), "far")
+	h.Remove(id)
+	if _, ok := h.Raycast(0, 50, -1, 0, math.Inf(1), nil); ok {
+		t.Fatal("hit after Remove")
+	}
+}
@@ -0,0 +1,59 @@
+package spatial
+
+import (
+	"math"
+)
+
+// Axis Aligned Bounding Box, X, Y is TopLeft
+type AABB struct {
+	X, Y, W, H float64
+}
+
+func NewAABB(x, y, width, height float64) AABB {
+	return AABB{x, y, width, height}
+}
+
+func (a AABB) Right() float64 {
+	return a.X + a.W
+}
+
+func (a AABB) Bottom() float64 {
+	return a.Y + a.H
+}
+
+func (a AABB) Center() (cx, cy float64) {
+	return a.X + a.W/2, a.Y + a.H/2
+}
+
+func (a AABB) Overlaps(b AABB) bool {
+	return a.X < b.Right() && b.X < a.Right() && a.Y < b.Bottom() && b.Y < a.Bottom()
+}
+
+func (a AABB) Contains(x, y float64) bool {
+	return x >= a.X && y >= a.Y && x < a.Right() && y < a.Bottom()
+}
+
+/*
+Intersects ray (ox, oy) + t*(dx, dy) with the box (slab method)
+Returns the entry t (0 if the ray starts inside), ok is false if there is no hit within [0, maxT]
+*/
+func (a AABB) Raycast(ox, oy, dx, dy, maxT float64) (t float64, ok bool) {
+	tmin, tmax := 0.0, maxT
+
+	slab := func(o, d, min, max float64) bool {
+		if d == 0 {
+			return o >= min && o <= max
+		}
+		t1, t2 := (min-o)/d, (max-o)/d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tmin, tmax = math.Max(tmin, t1), math.Min(tmax, t2)
+		return tmin <= tmax
+	}
+
+	if !slab(ox, dx, a.X, a.Right()) || !slab(oy, dy, a.Y, a.Bottom()) {
+		return 0, false
+	}
+	return tmin, true
+}
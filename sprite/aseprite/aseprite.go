@@ -0,0 +1,256 @@
+package aseprite
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shubhamdwivedii/gopher-engine/animation"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+	"golang.org/x/image/math/f64"
+)
+
+const (
+	LayerVisible    = 1
+	LayerEditable   = 2
+	LayerLocked     = 4
+	LayerBackground = 8
+
+	LayerImage   = 0
+	LayerGroup   = 1
+	LayerTilemap = 2
+)
+
+/*
+File is a decoded .aseprite/.ase file
+Tags and Slices use the sprite types so they carry over to the Atlas built by ToAtlas
+Only the normal blend mode is used when compositing, tilemap layers are skipped
+*/
+type File struct {
+	Width, Height    int
+	Depth            int // Bits per pixel: 32 (RGBA), 16 (grayscale) or 8 (indexed)
+	TransparentIndex int // Palette index that's transparent in indexed files
+	Palette          []color.NRGBA
+	Layers           []Layer
+	Frames           []Frame
+	Tags             []sprite.Tag
+	Slices           []sprite.Slice
+}
+
+type Layer struct {
+	Name       string
+	Flags      int // LayerVisible, LayerBackground...
+	Type       int // LayerImage, LayerGroup or LayerTilemap
+	ChildLevel int
+	Parent     int // Index of the group this layer is in, -1 for top level
+	BlendMode  int
+	Opacity    byte
+	Data       string // User data text
+}
+
+func (l *Layer) IsBackground() bool {
+	return l.Flags&LayerBackground != 0
+}
+
+type Frame struct {
+	Duration int // Milliseconds
+	Cels     []Cel
+}
+
+// Image of a Layer in a Frame, X, Y is its position on the canvas
+type Cel struct {
+	Layer   int
+	X, Y    int
+	Opacity byte
+	ZIndex  int
+	Image   *image.NRGBA // Shared with the original for linked cels
+	Link    int          // Frame this cel was linked to, -1 if it isn't
+	Data    string
+}
+
+// Cel of a layer, nil if the layer is empty in this frame
+func (fr *Frame) GetCel(layer int) *Cel {
+	for i := range fr.Cels {
+		if fr.Cels[i].Layer == layer {
+			return &fr.Cels[i]
+		}
+	}
+	return nil
+}
+
+// Loads a .aseprite/.ase file from disk
+func Load(filePath string) (*File, error) {
+	dir, name := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	return LoadFS(os.DirFS(dir), name)
+}
+
+// Same as Load but from any fs.FS (eg: embed.FS)
+func LoadFS(fsys fs.FS, name string) (*File, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return f, nil
+}
+
+// Index of a layer by name, -1 if there is none
+func (f *File) GetLayer(name string) int {
+	for i := range f.Layers {
+		if f.Layers[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// A layer is visible if it and all of its parent groups are
+func (f *File) IsLayerVisible(layer int) bool {
+	for i := layer; i >= 0 && i < len(f.Layers); i = f.Layers[i].Parent {
+		if f.Layers[i].Flags&LayerVisible == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Composites a frame onto a canvas sized image
+layers (names) limits it to those layers (eg: to split a body and a weapon), all visible layers if empty
+*/
+func (f *File) Flatten(frame int, layers ...string) *image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, f.Width, f.Height))
+	if frame < 0 || frame >= len(f.Frames) {
+		return canvas
+	}
+
+	var only map[int]bool
+	if len(layers) > 0 {
+		only = map[int]bool{}
+		for _, name := range layers {
+			only[f.GetLayer(name)] = true
+		}
+	}
+
+	cels := make([]*Cel, 0, len(f.Frames[frame].Cels))
+	for i := range f.Frames[frame].Cels {
+		c := &f.Frames[frame].Cels[i]
+		if c.Image == nil || c.Layer >= len(f.Layers) {
+			continue
+		}
+		if only != nil && !only[c.Layer] || only == nil && !f.IsLayerVisible(c.Layer) {
+			continue
+		}
+		cels = append(cels, c)
+	}
+	// Cels are drawn in layer order, z-index moves a cel up or down
+	sort.SliceStable(cels, func(i, j int) bool {
+		a, b := cels[i].Layer+cels[i].ZIndex, cels[j].Layer+cels[j].ZIndex
+		if a != b {
+			return a < b
+		}
+		return cels[i].ZIndex < cels[j].ZIndex
+	})
+
+	for _, c := range cels {
+		opacity := int(c.Opacity) * int(f.layerOpacity(c.Layer)) / 255
+		if opacity == 0 {
+			continue
+		}
+		r := c.Image.Bounds().Add(image.Pt(c.X, c.Y))
+		mask := image.NewUniform(color.Alpha{uint8(opacity)})
+		draw.DrawMask(canvas, r, c.Image, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	return canvas
+}
+
+// Opacity of a layer multiplied by its parent groups
+func (f *File) layerOpacity(layer int) byte {
+	opacity := 255
+	for i := layer; i >= 0 && i < len(f.Layers); i = f.Layers[i].Parent {
+		opacity = opacity * int(f.Layers[i].Opacity) / 255
+	}
+	return byte(opacity)
+}
+
+/*
+Flattens every frame into one texture (a grid of canvas sized cells) and returns it as an Atlas
+Frames are named by their index ("0", "1"...) with Duration set, Tags and Slices are copied
+layers works like Flatten
+*/
+func (f *File) ToAtlas(layers ...string) *sprite.Atlas {
+	n := len(f.Frames)
+	columns := int(math.Ceil(math.Sqrt(float64(n))))
+	if columns == 0 {
+		columns = 1
+	}
+	rows := (n + columns - 1) / columns
+	texture := image.NewRGBA(image.Rect(0, 0, maxInt(columns*f.Width, 1), maxInt(rows*f.Height, 1)))
+
+	atlas := sprite.NewAtlas(nil)
+	for i := 0; i < n; i++ {
+		x, y := (i%columns)*f.Width, (i/columns)*f.Height
+		rect := image.Rect(x, y, x+f.Width, y+f.Height)
+		draw.Draw(texture, rect, f.Flatten(i, layers...), image.Point{}, draw.Src)
+		frame := atlas.AddFrame(strconv.Itoa(i), rect)
+		frame.Duration = float64(f.Frames[i].Duration)
+	}
+	atlas.Tags = append(atlas.Tags, f.Tags...)
+	atlas.Slices = append(atlas.Slices, f.Slices...)
+	atlas.SetImage(ebiten.NewImageFromImage(texture))
+	return atlas
+}
+
+/*
+Animation clips for every tag of an Atlas built by ToAtlas, keyed by tag name
+Files without tags get a single looping clip of all frames named ""
+pivot (normalized, eg: 0.5, 1 for bottom center) is set on every frame
+*/
+func GetClips(atlas *sprite.Atlas, pivotX, pivotY float64) (map[string]*animation.Clip, error) {
+	for _, name := range atlas.Order {
+		atlas.Frames[name].Pivot = f64.Vec2{pivotX, pivotY}
+	}
+
+	clips := map[string]*animation.Clip{}
+	if len(atlas.Tags) == 0 {
+		clip, err := animation.FromAtlas(atlas, "", ANIMATION_LOOP, 0.1, atlas.Order...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range clip.Frames {
+			clip.Frames[i].Duration = clip.Frames[i].Sprite.Duration / 1000
+		}
+		clips[""] = clip
+		return clips, nil
+	}
+	for _, t := range atlas.Tags {
+		clip, err := animation.FromTag(atlas, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		clips[t.Name] = clip
+	}
+	return clips, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,405 @@
+package aseprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+
+	"github.com/shubhamdwivedii/gopher-engine/sprite"
+)
+
+const (
+	fileMagic  = 0xA5E0
+	frameMagic = 0xF1FA
+
+	chunkOldPalette = 0x0004
+	chunkLayer      = 0x2004
+	chunkCel        = 0x2005
+	chunkTags       = 0x2018
+	chunkPalette    = 0x2019
+	chunkUserData   = 0x2020
+	chunkSlice      = 0x2022
+
+	celRaw        = 0
+	celLinked     = 1
+	celCompressed = 2
+
+	headerLayerOpacity = 1
+)
+
+// Binary reader of little-endian Aseprite values, the first error sticks
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *reader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) word() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) short() int16 {
+	return int16(r.word())
+}
+
+func (r *reader) dword() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) long() int32 {
+	return int32(r.dword())
+}
+
+func (r *reader) string() string {
+	return string(r.bytes(int(r.word())))
+}
+
+// Reads a .aseprite/.ase file
+func Read(r io.Reader) (*File, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parses the contents of a .aseprite/.ase file
+func Parse(data []byte) (*File, error) {
+	r := &reader{data: data}
+	r.dword() // File size
+	if r.word() != fileMagic {
+		return nil, errors.New("aseprite: not an aseprite file")
+	}
+	frames := int(r.word())
+	f := &File{
+		Width:  int(r.word()),
+		Height: int(r.word()),
+		Depth:  int(r.word()),
+	}
+	flags := r.dword()
+	r.skip(2 + 4 + 4) // Speed (deprecated) and reserved
+	f.TransparentIndex = int(r.byte())
+	r.skip(3)
+	r.word() // Number of colors
+	r.skip(2 + 8 + 84)
+	if r.err != nil {
+		return nil, fmt.Errorf("aseprite: header: %w", r.err)
+	}
+	if f.Depth != 32 && f.Depth != 16 && f.Depth != 8 {
+		return nil, fmt.Errorf("aseprite: unsupported color depth %d", f.Depth)
+	}
+
+	p := &parser{file: f, flags: flags}
+	for i := 0; i < frames; i++ {
+		if err := p.parseFrame(r); err != nil {
+			return nil, fmt.Errorf("aseprite: frame %d: %w", i, err)
+		}
+	}
+	if err := p.resolveLinks(); err != nil {
+		return nil, fmt.Errorf("aseprite: %w", err)
+	}
+	return f, nil
+}
+
+// State while parsing, user data chunks apply to the last layer/cel/tag/slice read
+type parser struct {
+	file  *File
+	flags uint32
+
+	userData func(text string)
+	tagIndex int // Next tag for user data after a tags chunk
+}
+
+func (p *parser) parseFrame(r *reader) error {
+	start := r.pos
+	size := int(r.dword())
+	if r.word() != frameMagic {
+		return errors.New("bad frame magic")
+	}
+	chunks := int(r.word())
+	duration := int(r.word())
+	r.skip(2)
+	if n := int(r.dword()); n != 0 {
+		chunks = n
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	p.file.Frames = append(p.file.Frames, Frame{Duration: duration})
+	frame := len(p.file.Frames) - 1
+	for i := 0; i < chunks; i++ {
+		chunkStart := r.pos
+		chunkSize := int(r.dword())
+		chunkType := r.word()
+		if r.err != nil || chunkSize < 6 || chunkStart+chunkSize > len(r.data) {
+			return fmt.Errorf("chunk %d: %w", i, io.ErrUnexpectedEOF)
+		}
+		chunk := &reader{data: r.data[chunkStart+6 : chunkStart+chunkSize]}
+		if err := p.parseChunk(chunk, chunkType, frame); err != nil {
+			return fmt.Errorf("chunk %#x: %w", chunkType, err)
+		}
+		r.pos = chunkStart + chunkSize
+	}
+	r.pos = start + size
+	return nil
+}
+
+func (p *parser) parseChunk(r *reader, chunkType uint16, frame int) error {
+	f := p.file
+	if chunkType != chunkUserData {
+		p.userData = nil
+	}
+	switch chunkType {
+	case chunkLayer:
+		l := Layer{Flags: int(r.word()), Type: int(r.word()), ChildLevel: int(r.word())}
+		r.skip(4) // Default width and height (ignored)
+		l.BlendMode = int(r.word())
+		l.Opacity = r.byte()
+		if p.flags&headerLayerOpacity == 0 {
+			l.Opacity = 255
+		}
+		r.skip(3)
+		l.Name = r.string()
+		l.Parent = -1
+		for i := len(f.Layers) - 1; i >= 0; i-- {
+			if f.Layers[i].ChildLevel < l.ChildLevel {
+				l.Parent = i
+				break
+			}
+		}
+		f.Layers = append(f.Layers, l)
+		index := len(f.Layers) - 1
+		p.userData = func(text string) { f.Layers[index].Data = text }
+
+	case chunkCel:
+		c := Cel{Layer: int(r.word()), X: int(r.short()), Y: int(r.short()), Opacity: r.byte(), Link: -1}
+		celType := r.word()
+		c.ZIndex = int(r.short())
+		r.skip(5)
+		switch celType {
+		case celRaw, celCompressed:
+			w, h := int(r.word()), int(r.word())
+			pixels := r.bytes(len(r.data) - r.pos)
+			if r.err != nil {
+				return r.err
+			}
+			if celType == celCompressed {
+				zr, err := zlib.NewReader(bytes.NewReader(pixels))
+				if err != nil {
+					return err
+				}
+				pixels, err = ioutil.ReadAll(zr)
+				if err != nil {
+					return err
+				}
+			}
+			img, err := p.decodePixels(pixels, w, h, c.Layer)
+			if err != nil {
+				return err
+			}
+			c.Image = img
+		case celLinked:
+			c.Link = int(r.word())
+		default:
+			return nil // Tilemap cels aren't supported
+		}
+		f.Frames[frame].Cels = append(f.Frames[frame].Cels, c)
+		index := len(f.Frames[frame].Cels) - 1
+		p.userData = func(text string) { f.Frames[frame].Cels[index].Data = text }
+
+	case chunkTags:
+		count := int(r.word())
+		r.skip(8)
+		p.tagIndex = len(f.Tags)
+		for i := 0; i < count; i++ {
+			t := sprite.Tag{From: int(r.word()), To: int(r.word())}
+			switch r.byte() {
+			case 1:
+				t.Direction = "reverse"
+			case 2:
+				t.Direction = "pingpong"
+			case 3:
+				t.Direction = "pingpong_reverse"
+			default:
+				t.Direction = "forward"
+			}
+			t.Repeat = int(r.word())
+			r.skip(6 + 3 + 1) // Reserved, deprecated color
+			t.Name = r.string()
+			f.Tags = append(f.Tags, t)
+		}
+		// Tags are followed by one user data chunk per tag
+		p.userData = func(text string) {
+			if p.tagIndex < len(f.Tags) {
+				f.Tags[p.tagIndex].Data = text
+			}
+		}
+
+	case chunkPalette:
+		size := int(r.dword())
+		first, last := int(r.dword()), int(r.dword())
+		r.skip(8)
+		f.growPalette(size)
+		for i := first; i <= last && r.err == nil; i++ {
+			flags := r.word()
+			rgba := r.bytes(4)
+			if rgba != nil && i < len(f.Palette) {
+				f.Palette[i] = color.NRGBA{rgba[0], rgba[1], rgba[2], rgba[3]}
+			}
+			if flags&1 != 0 {
+				r.string()
+			}
+		}
+
+	case chunkOldPalette:
+		if len(f.Palette) > 0 {
+			return nil // A new palette chunk takes priority
+		}
+		packets := int(r.word())
+		index := 0
+		for i := 0; i < packets && r.err == nil; i++ {
+			index += int(r.byte())
+			n := int(r.byte())
+			if n == 0 {
+				n = 256
+			}
+			f.growPalette(index + n)
+			for j := 0; j < n; j++ {
+				rgb := r.bytes(3)
+				if rgb != nil {
+					f.Palette[index] = color.NRGBA{rgb[0], rgb[1], rgb[2], 255}
+				}
+				index++
+			}
+		}
+
+	case chunkSlice:
+		keys := int(r.dword())
+		flags := r.dword()
+		r.skip(4)
+		s := sprite.Slice{Name: r.string()}
+		for i := 0; i < keys && r.err == nil; i++ {
+			k := sprite.SliceKey{Frame: int(r.dword())}
+			x, y := int(r.long()), int(r.long())
+			k.Bounds = image.Rect(x, y, x+int(r.dword()), y+int(r.dword()))
+			if flags&1 != 0 {
+				cx, cy := int(r.long()), int(r.long())
+				k.Center = image.Rect(cx, cy, cx+int(r.dword()), cy+int(r.dword()))
+			}
+			if flags&2 != 0 {
+				k.Pivot = &image.Point{X: int(r.long()), Y: int(r.long())}
+			}
+			s.Keys = append(s.Keys, k)
+		}
+		f.Slices = append(f.Slices, s)
+		index := len(f.Slices) - 1
+		p.userData = func(text string) { f.Slices[index].Data = text }
+
+	case chunkUserData:
+		flags := r.dword()
+		if flags&1 != 0 {
+			text := r.string()
+			if p.userData != nil {
+				p.userData(text)
+			}
+		}
+		p.tagIndex++
+	}
+	return r.err
+}
+
+func (f *File) growPalette(size int) {
+	for len(f.Palette) < size {
+		f.Palette = append(f.Palette, color.NRGBA{})
+	}
+}
+
+// Converts cel pixels of any color depth to NRGBA
+func (p *parser) decodePixels(pixels []byte, w, h, layer int) (*image.NRGBA, error) {
+	f := p.file
+	bpp := f.Depth / 8
+	if len(pixels) < w*h*bpp {
+		return nil, io.ErrUnexpectedEOF
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if f.Depth == 32 {
+		copy(img.Pix, pixels[:w*h*4])
+		return img, nil
+	}
+
+	background := layer < len(f.Layers) && f.Layers[layer].IsBackground()
+	for i := 0; i < w*h; i++ {
+		var c color.NRGBA
+		switch f.Depth {
+		case 16:
+			v := pixels[i*2]
+			c = color.NRGBA{v, v, v, pixels[i*2+1]}
+		case 8:
+			index := int(pixels[i])
+			if (index != f.TransparentIndex || background) && index < len(f.Palette) {
+				c = f.Palette[index]
+			}
+		}
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = c.R, c.G, c.B, c.A
+	}
+	return img, nil
+}
+
+// Linked cels share the image of the same layer's cel in another frame
+func (p *parser) resolveLinks() error {
+	f := p.file
+	for i := range f.Frames {
+		for j := range f.Frames[i].Cels {
+			c := &f.Frames[i].Cels[j]
+			if c.Link < 0 {
+				continue
+			}
+			if c.Link >= len(f.Frames) {
+				return fmt.Errorf("cel of layer %d links to missing frame %d", c.Layer, c.Link)
+			}
+			linked := f.Frames[c.Link].GetCel(c.Layer)
+			if linked == nil || linked.Image == nil {
+				return fmt.Errorf("cel of layer %d links to an empty cel in frame %d", c.Layer, c.Link)
+			}
+			c.X, c.Y, c.Image = linked.X, linked.Y, linked.Image
+		}
+	}
+	return nil
+}
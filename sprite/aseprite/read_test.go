@@ -0,0 +1,143 @@
+package aseprite
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"testing"
+)
+
+/*
+testdata/indexed.aseprite is a 4x4 indexed (8 bit) sprite with 2 frames:
+layers "background" (background), "group" and "body" (in group, 50% opacity),
+frame 1 links the background cel of frame 0, palette entry 3 is half transparent blue,
+tags "idle" and "walk" and a nine-slice "panel" with a pivot all carry user data
+*/
+func loadFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/indexed.aseprite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParse(t *testing.T) {
+	f, err := Parse(loadFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Width != 4 || f.Height != 4 || f.Depth != 8 || len(f.Frames) != 2 {
+		t.Fatalf("%dx%d depth %d with %d frames, want 4x4 depth 8 with 2", f.Width, f.Height, f.Depth, len(f.Frames))
+	}
+	if f.Frames[0].Duration != 100 || f.Frames[1].Duration != 200 {
+		t.Fatalf("durations %d %d, want 100 200", f.Frames[0].Duration, f.Frames[1].Duration)
+	}
+
+	// The new palette chunk wins over the old one
+	wantPalette := []color.NRGBA{{0, 0, 0, 255}, {255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 128}}
+	if len(f.Palette) != len(wantPalette) {
+		t.Fatalf("palette %v, want %v", f.Palette, wantPalette)
+	}
+	for i, c := range wantPalette {
+		if f.Palette[i] != c {
+			t.Fatalf("palette[%d] = %v, want %v", i, f.Palette[i], c)
+		}
+	}
+
+	body := f.GetLayer("body")
+	if body != 2 || f.Layers[body].Parent != f.GetLayer("group") || f.Layers[0].Parent != -1 {
+		t.Fatalf("layers %+v, want body in group", f.Layers)
+	}
+	if f.Layers[body].Opacity != 128 || f.Layers[body].Data != "body data" {
+		t.Fatalf("body opacity %d data %q", f.Layers[body].Opacity, f.Layers[body].Data)
+	}
+	if !f.Layers[0].IsBackground() || f.Layers[body].IsBackground() {
+		t.Fatal("only the first layer is a background")
+	}
+}
+
+func TestParseCels(t *testing.T) {
+	f, err := Parse(loadFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The transparent index is opaque on the background layer only
+	bg := f.Frames[0].GetCel(0)
+	if bg == nil || bg.Image.NRGBAAt(3, 3) != (color.NRGBA{0, 0, 0, 255}) {
+		t.Fatal("background cel isn't opaque black")
+	}
+	c := f.Frames[0].GetCel(2)
+	if c == nil || c.X != 1 || c.Y != 1 || c.Data != "cel data" {
+		t.Fatalf("body cel %+v, want at 1,1 with user data", c)
+	}
+	want := []color.NRGBA{f.Palette[1], f.Palette[2], {}, f.Palette[3]}
+	for i, w := range want {
+		if got := c.Image.NRGBAAt(i%2, i/2); got != w {
+			t.Fatalf("compressed cel pixel %d = %v, want %v", i, got, w)
+		}
+	}
+
+	linked := f.Frames[1].GetCel(0)
+	if linked == nil || linked.Link != 0 || linked.Image != bg.Image {
+		t.Fatalf("frame 1 background %+v, want linked to frame 0", linked)
+	}
+	if raw := f.Frames[1].GetCel(2); raw == nil || raw.Link != -1 || raw.Image.NRGBAAt(1, 1) != f.Palette[3] {
+		t.Fatal("frame 1 body isn't its own raw cel")
+	}
+
+	// 50% red over black, the transparent pixel shows the background
+	canvas := f.Flatten(0)
+	if r := canvas.RGBAAt(1, 1); r.R < 126 || r.R > 129 || r.A != 255 {
+		t.Fatalf("flattened pixel %v, want half red over black", r)
+	}
+	if p := canvas.RGBAAt(1, 2); p != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatalf("flattened pixel %v, want background black", p)
+	}
+	if p := f.Flatten(0, "body").RGBAAt(0, 0); p.A != 0 {
+		t.Fatalf("body only flatten has background pixel %v", p)
+	}
+}
+
+func TestParseTagsAndSlices(t *testing.T) {
+	f, err := Parse(loadFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Tags) != 2 {
+		t.Fatalf("tags %+v, want 2", f.Tags)
+	}
+	idle, walk := f.Tags[0], f.Tags[1]
+	if idle.Name != "idle" || idle.From != 0 || idle.To != 0 || idle.Direction != "forward" || idle.Data != "idle data" {
+		t.Fatalf("idle tag %+v", idle)
+	}
+	if walk.Name != "walk" || walk.To != 1 || walk.Direction != "pingpong" || walk.Repeat != 3 || walk.Data != "walk data" {
+		t.Fatalf("walk tag %+v", walk)
+	}
+
+	if len(f.Slices) != 1 || f.Slices[0].Name != "panel" || f.Slices[0].Data != "panel data" {
+		t.Fatalf("slices %+v, want panel with user data", f.Slices)
+	}
+	s := f.Slices[0]
+	k := s.GetKey(0)
+	if k == nil || k.Bounds != image.Rect(0, 0, 4, 4) || k.Center != image.Rect(1, 1, 3, 3) || !k.IsNineSlice() {
+		t.Fatalf("key 0 %+v, want 0,0-4,4 with center 1,1-3,3", k)
+	}
+	if k.Pivot == nil || *k.Pivot != (image.Point{2, 3}) {
+		t.Fatalf("key 0 pivot %v, want 2,3", k.Pivot)
+	}
+	if k := s.GetKey(1); k == nil || k.Bounds != image.Rect(-1, 0, 4, 4) || k.Center != image.Rect(1, 1, 4, 3) {
+		t.Fatalf("key 1 %+v", k)
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	data := loadFixture(t)
+	for n := 0; n < len(data); n++ {
+		if _, err := Parse(data[:n]); err == nil {
+			t.Fatalf("Parse() of the first %d of %d bytes didn't fail", n, len(data))
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"golang.org/x/image/math/f64"
+)
+
+// A named sub-image of an Atlas
+type Frame struct {
+	Name       string
+	Image      *ebiten.Image   // Sub-image of the Atlas texture (as stored, so rotated frames are sideways)
+	Rect       image.Rectangle // Region in the Atlas texture
+	Rotated    bool            // Stored rotated 90 degrees clockwise
+	Trimmed    bool
+	SourceSize f64.Vec2 // Size before trimming
+	Offset     f64.Vec2 // Position of the trimmed image inside SourceSize
+	Pivot      f64.Vec2 // Origin for drawing, normalized (0, 0 is TopLeft, 0.5, 0.5 is center) of SourceSize
+	Duration   float64  // Milliseconds, used by animations (Aseprite exports it)
+}
+
+// Size of the frame as drawn (before trimming, unrotated)
+func (f *Frame) GetSize() f64.Vec2 {
+	return f.SourceSize
+}
+
+// Pivot in pixels, relative to TopLeft of SourceSize
+func (f *Frame) GetOrigin() f64.Vec2 {
+	return f64.Vec2{f.Pivot[0] * f.SourceSize[0], f.Pivot[1] * f.SourceSize[1]}
+}
+
+/*
+Local matrix of the frame's Image: un-rotates, places the trimmed image and moves the Pivot to 0, 0
+Concat further transforms (scale, rotate around the pivot, then position) after it
+*/
+func (f *Frame) GetMatrix() ebiten.GeoM {
+	m := ebiten.GeoM{}
+	if f.Rotated {
+		m.Rotate(-math.Pi / 2)
+		m.Translate(0, float64(f.Rect.Dx()))
+	}
+	origin := f.GetOrigin()
+	m.Translate(f.Offset[0]-origin[0], f.Offset[1]-origin[1])
+	return m
+}
+
+// Animation range of frames exported from Aseprite (meta.frameTags)
+type Tag struct {
+	Name      string
+	From, To  int    // Indexes into Atlas.Order (inclusive)
+	Direction string // "forward", "reverse", "pingpong" or "pingpong_reverse"
+	Repeat    int    // 0 is forever
+	Data      string
+}
+
+// Named region (eg: hitbox or nine-slice) exported from Aseprite (meta.slices)
+type Slice struct {
+	Name string
+	Data string
+	Keys []SliceKey
+}
+
+// Slice bounds from frame Frame onwards
+type SliceKey struct {
+	Frame  int
+	Bounds image.Rectangle
+	Center image.Rectangle // Nine-slice center, empty if not set
+	Pivot  *image.Point    // nil if not set
+}
+
+// Keys with a Center can be drawn as nine-slices, others are plain regions (eg: hitboxes)
+func (k *SliceKey) IsNineSlice() bool {
+	return !k.Center.Empty()
+}
+
+// Key active at a frame index, nil if the Slice doesn't exist there yet
+func (s *Slice) GetKey(frame int) *SliceKey {
+	var found *SliceKey
+	for i := range s.Keys {
+		if s.Keys[i].Frame <= frame {
+			found = &s.Keys[i]
+		}
+	}
+	return found
+}
+
+/*
+Atlas is a packed texture with named Frames (TexturePacker JSON/XML, Aseprite JSON)
+Frames keep the order of the descriptor in Order, Aseprite Tags index into it
+*/
+type Atlas struct {
+	Image  *ebiten.Image
+	Frames map[string]*Frame
+	Order  []string
+	Tags   []Tag
+	Slices []Slice
+}
+
+func NewAtlas(img *ebiten.Image) *Atlas {
+	return &Atlas{Image: img, Frames: map[string]*Frame{}}
+}
+
+// Adds a frame for a region of the Atlas texture (eg: for hand made sheets), replaces frames with the same name
+func (a *Atlas) AddFrame(name string, rect image.Rectangle) *Frame {
+	f := &Frame{
+		Name:       name,
+		Rect:       rect,
+		SourceSize: f64.Vec2{float64(rect.Dx()), float64(rect.Dy())},
+	}
+	a.addFrame(f)
+	return f
+}
+
+func (a *Atlas) addFrame(f *Frame) {
+	if a.Image != nil {
+		f.Image = a.Image.SubImage(f.Rect).(*ebiten.Image)
+	}
+	if _, exists := a.Frames[f.Name]; !exists {
+		a.Order = append(a.Order, f.Name)
+	}
+	a.Frames[f.Name] = f
+}
+
+// Adds frames cut from a uniform grid, named prefix0, prefix1... (row by row)
+func (a *Atlas) AddGrid(prefix string, x, y, frameWidth, frameHeight, columns, count int) []*Frame {
+	frames := make([]*Frame, 0, count)
+	for i := 0; i < count; i++ {
+		fx, fy := x+(i%columns)*frameWidth, y+(i/columns)*frameHeight
+		frames = append(frames, a.AddFrame(fmt.Sprintf("%s%d", prefix, i), image.Rect(fx, fy, fx+frameWidth, fy+frameHeight)))
+	}
+	return frames
+}
+
+// nil if there is no such frame
+func (a *Atlas) Get(name string) *Frame {
+	return a.Frames[name]
+}
+
+// Frame by its index in Order, nil if out of range
+func (a *Atlas) GetByIndex(i int) *Frame {
+	if i < 0 || i >= len(a.Order) {
+		return nil
+	}
+	return a.Frames[a.Order[i]]
+}
+
+func (a *Atlas) GetImage(name string) *ebiten.Image {
+	if f := a.Frames[name]; f != nil {
+		return f.Image
+	}
+	return nil
+}
+
+// Frames of an Aseprite Tag, in order
+func (a *Atlas) GetTagFrames(tag string) []*Frame {
+	for _, t := range a.Tags {
+		if t.Name != tag {
+			continue
+		}
+		var frames []*Frame
+		for i := t.From; i <= t.To; i++ {
+			if f := a.GetByIndex(i); f != nil {
+				frames = append(frames, f)
+			}
+		}
+		return frames
+	}
+	return nil
+}
+
+func (a *Atlas) GetSlice(name string) *Slice {
+	for i := range a.Slices {
+		if a.Slices[i].Name == name {
+			return &a.Slices[i]
+		}
+	}
+	return nil
+}
+
+/*
+Draws a frame by name with its Pivot at World x, y through Screen.DrawImage
+op.GeoM (if any) is applied around the Pivot (eg: flip, scale, rotate) before moving to x, y
+*/
+func (a *Atlas) Draw(screen scr.Screen, name string, x, y float64, op *ebiten.DrawImageOptions) {
+	f := a.Frames[name]
+	if f == nil {
+		return
+	}
+	DrawFrame(screen, f, x, y, op)
+}
+
+// Same as Atlas.Draw for a Frame
+func DrawFrame(screen scr.Screen, f *Frame, x, y float64, op *ebiten.DrawImageOptions) {
+	if f.Image == nil {
+		return
+	}
+	if op == nil {
+		op = &ebiten.DrawImageOptions{}
+	}
+	m := f.GetMatrix()
+	m.Concat(op.GeoM)
+	m.Translate(x, y)
+	op.GeoM = m
+	screen.DrawImage(f.Image, op)
+}
+
+/***************** LOADING *********************/
+
+/*
+Loads an atlas descriptor from disk, the texture is resolved relative to it
+.json is TexturePacker (hash or array) or Aseprite, .xml is TexturePacker/Starling XML
+*/
+func Load(filePath string) (*Atlas, error) {
+	dir, name := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	return LoadFS(os.DirFS(dir), name)
+}
+
+// Same as Load but from any fs.FS (eg: embed.FS), name uses forward slashes
+func LoadFS(fsys fs.FS, name string) (*Atlas, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var atlas *Atlas
+	var imagePath string
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		atlas, imagePath, err = ParseJSON(data)
+	case ".xml":
+		atlas, imagePath, err = ParseXML(data)
+	default:
+		return nil, fmt.Errorf("sprite: unknown atlas format %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sprite: %s: %w", name, err)
+	}
+
+	img, err := loadImage(fsys, path.Join(path.Dir(name), imagePath))
+	if err != nil {
+		return nil, fmt.Errorf("sprite: %s: %w", name, err)
+	}
+	atlas.SetImage(img)
+	return atlas, nil
+}
+
+// Sets (or replaces) the texture, all frame sub-images are recreated
+func (a *Atlas) SetImage(img *ebiten.Image) {
+	a.Image = img
+	for _, f := range a.Frames {
+		f.Image = img.SubImage(f.Rect).(*ebiten.Image)
+	}
+}
+
+func loadImage(fsys fs.FS, name string) (*ebiten.Image, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decoded, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(decoded), nil
+}
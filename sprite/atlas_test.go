@@ -0,0 +1,43 @@
+package sprite
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+func TestFrameGetMatrix(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame Frame
+		// Corners of the stored image (TopLeft, TopRight, BottomLeft, BottomRight) once drawn
+		want [4]f64.Vec2
+	}{
+		{"plain", Frame{Rect: image.Rect(10, 10, 14, 12), SourceSize: f64.Vec2{4, 2}},
+			[4]f64.Vec2{{0, 0}, {4, 0}, {0, 2}, {4, 2}}},
+		{"trimmed", Frame{Rect: image.Rect(0, 0, 4, 2), Trimmed: true, SourceSize: f64.Vec2{10, 8}, Offset: f64.Vec2{1, 3}},
+			[4]f64.Vec2{{1, 3}, {5, 3}, {1, 5}, {5, 5}}},
+		{"pivot", Frame{Rect: image.Rect(0, 0, 4, 2), SourceSize: f64.Vec2{4, 2}, Pivot: f64.Vec2{0.5, 1}},
+			[4]f64.Vec2{{-2, -2}, {2, -2}, {-2, 0}, {2, 0}}},
+		// A 4x2 frame stored clockwise is 2x4, its TopLeft was the frame's BottomLeft
+		{"rotated", Frame{Rect: image.Rect(20, 0, 22, 4), Rotated: true, SourceSize: f64.Vec2{4, 2}},
+			[4]f64.Vec2{{0, 2}, {0, 0}, {4, 2}, {4, 0}}},
+		{"rotated trimmed with pivot", Frame{Rect: image.Rect(0, 0, 2, 4), Rotated: true, Trimmed: true,
+			SourceSize: f64.Vec2{10, 8}, Offset: f64.Vec2{1, 3}, Pivot: f64.Vec2{0.5, 0.5}},
+			[4]f64.Vec2{{-4, 1}, {-4, -1}, {0, 1}, {0, -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.frame.GetMatrix()
+			w, h := float64(tt.frame.Rect.Dx()), float64(tt.frame.Rect.Dy())
+			for i, corner := range [4]f64.Vec2{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+				x, y := m.Apply(corner[0], corner[1])
+				if math.Abs(x-tt.want[i][0]) > 1e-9 || math.Abs(y-tt.want[i][1]) > 1e-9 {
+					t.Fatalf("corner %v drawn at %v, %v, want %v", corner, x, y, tt.want[i])
+				}
+			}
+		})
+	}
+}
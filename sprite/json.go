@@ -0,0 +1,166 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+
+	"golang.org/x/image/math/f64"
+)
+
+// TexturePacker (JSON Hash/Array) and Aseprite (Hash/Array) share the same frame layout
+type jsonRect struct {
+	X, Y, W, H int
+}
+
+func (r jsonRect) rect() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+type jsonFrame struct {
+	Filename         string
+	Frame            jsonRect
+	Rotated          bool
+	Trimmed          bool
+	SpriteSourceSize jsonRect
+	SourceSize       struct{ W, H int }
+	Pivot            *struct{ X, Y float64 }
+	Duration         float64
+}
+
+type jsonMeta struct {
+	App       string
+	Image     string
+	FrameTags []struct {
+		Name      string
+		From, To  int
+		Direction string
+		Repeat    json.Number
+		Data      string
+	}
+	Slices []struct {
+		Name string
+		Data string
+		Keys []struct {
+			Frame  int
+			Bounds jsonRect
+			Center *jsonRect
+			Pivot  *struct{ X, Y int }
+		}
+	}
+}
+
+type jsonAtlas struct {
+	Frames json.RawMessage
+	Meta   jsonMeta
+}
+
+/*
+Parses a TexturePacker or Aseprite JSON descriptor, returns the Atlas (without texture) and meta.image
+Frames can be a hash (keyed by name, order is kept) or an array (named by filename)
+*/
+func ParseJSON(data []byte) (*Atlas, string, error) {
+	var doc jsonAtlas
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, "", err
+	}
+	frames, err := parseJSONFrames(doc.Frames)
+	if err != nil {
+		return nil, "", err
+	}
+	if doc.Meta.Image == "" {
+		return nil, "", fmt.Errorf("missing meta.image")
+	}
+
+	atlas := NewAtlas(nil)
+	for _, jf := range frames {
+		atlas.addFrame(jf.toFrame())
+	}
+	for _, t := range doc.Meta.FrameTags {
+		tag := Tag{Name: t.Name, From: t.From, To: t.To, Direction: t.Direction, Data: t.Data}
+		if tag.Direction == "" {
+			tag.Direction = "forward"
+		}
+		if repeat, err := t.Repeat.Int64(); err == nil {
+			tag.Repeat = int(repeat)
+		}
+		atlas.Tags = append(atlas.Tags, tag)
+	}
+	for _, s := range doc.Meta.Slices {
+		slice := Slice{Name: s.Name, Data: s.Data}
+		for _, k := range s.Keys {
+			key := SliceKey{Frame: k.Frame, Bounds: k.Bounds.rect()}
+			if k.Center != nil {
+				key.Center = k.Center.rect()
+			}
+			if k.Pivot != nil {
+				key.Pivot = &image.Point{X: k.Pivot.X, Y: k.Pivot.Y}
+			}
+			slice.Keys = append(slice.Keys, key)
+		}
+		atlas.Slices = append(atlas.Slices, slice)
+	}
+	return atlas, doc.Meta.Image, nil
+}
+
+// Reads frames as an array or as a hash in document order
+func parseJSONFrames(raw json.RawMessage) ([]jsonFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("missing frames")
+	}
+	if raw[0] == '[' {
+		var frames []jsonFrame
+		err := json.Unmarshal(raw, &frames)
+		return frames, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // {
+		return nil, err
+	}
+	var frames []jsonFrame
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, _ := token.(string)
+		var jf jsonFrame
+		if err := dec.Decode(&jf); err != nil {
+			return nil, fmt.Errorf("frame %q: %w", name, err)
+		}
+		jf.Filename = name
+		frames = append(frames, jf)
+	}
+	return frames, nil
+}
+
+func (jf jsonFrame) toFrame() *Frame {
+	f := &Frame{
+		Name:     jf.Filename,
+		Rotated:  jf.Rotated,
+		Trimmed:  jf.Trimmed,
+		Duration: jf.Duration,
+	}
+
+	// Frame size is unrotated, rotated frames take h x w in the texture
+	w, h := jf.Frame.W, jf.Frame.H
+	if jf.Rotated {
+		w, h = h, w
+	}
+	f.Rect = image.Rect(jf.Frame.X, jf.Frame.Y, jf.Frame.X+w, jf.Frame.Y+h)
+
+	f.SourceSize = f64.Vec2{float64(jf.SourceSize.W), float64(jf.SourceSize.H)}
+	if f.SourceSize[0] == 0 || f.SourceSize[1] == 0 {
+		f.SourceSize = f64.Vec2{float64(jf.Frame.W), float64(jf.Frame.H)}
+	}
+	if jf.Trimmed {
+		f.Offset = f64.Vec2{float64(jf.SpriteSourceSize.X), float64(jf.SpriteSourceSize.Y)}
+	}
+	if jf.Pivot != nil {
+		f.Pivot = f64.Vec2{jf.Pivot.X, jf.Pivot.Y}
+	}
+	return f
+}
@@ -0,0 +1,102 @@
+package sprite
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Same frames in both formats: a plain one, a trimmed one and a rotated trimmed one with a pivot
+const framesHash = `{
+	"walk_2": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "sourceSize": {"w": 16, "h": 16}, "duration": 100},
+	"walk_1": {"frame": {"x": 16, "y": 0, "w": 10, "h": 12}, "trimmed": true,
+		"spriteSourceSize": {"x": 3, "y": 4, "w": 10, "h": 12}, "sourceSize": {"w": 16, "h": 16}, "duration": 150},
+	"jump": {"frame": {"x": 26, "y": 0, "w": 12, "h": 6}, "rotated": true, "trimmed": true,
+		"spriteSourceSize": {"x": 2, "y": 5, "w": 12, "h": 6}, "sourceSize": {"w": 16, "h": 16}, "pivot": {"x": 0.5, "y": 1}}
+}`
+
+const framesArray = `[
+	{"filename": "walk_2", "frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "sourceSize": {"w": 16, "h": 16}, "duration": 100},
+	{"filename": "walk_1", "frame": {"x": 16, "y": 0, "w": 10, "h": 12}, "trimmed": true,
+		"spriteSourceSize": {"x": 3, "y": 4, "w": 10, "h": 12}, "sourceSize": {"w": 16, "h": 16}, "duration": 150},
+	{"filename": "jump", "frame": {"x": 26, "y": 0, "w": 12, "h": 6}, "rotated": true, "trimmed": true,
+		"spriteSourceSize": {"x": 2, "y": 5, "w": 12, "h": 6}, "sourceSize": {"w": 16, "h": 16}, "pivot": {"x": 0.5, "y": 1}}
+]`
+
+const meta = `"meta": {"image": "sheet.png",
+	"frameTags": [{"name": "walk", "from": 0, "to": 1}, {"name": "jump", "from": 2, "to": 2, "direction": "pingpong", "repeat": "3"}],
+	"slices": [{"name": "hitbox", "keys": [{"frame": 0, "bounds": {"x": 1, "y": 2, "w": 8, "h": 9}, "pivot": {"x": 4, "y": 9}}]}]}`
+
+func TestParseJSON(t *testing.T) {
+	want := map[string]*Frame{
+		"walk_2": {Name: "walk_2", Rect: image.Rect(0, 0, 16, 16), SourceSize: f64.Vec2{16, 16}, Duration: 100},
+		"walk_1": {Name: "walk_1", Rect: image.Rect(16, 0, 26, 12), Trimmed: true,
+			SourceSize: f64.Vec2{16, 16}, Offset: f64.Vec2{3, 4}, Duration: 150},
+		// Stored sideways, 6x12 in the texture
+		"jump": {Name: "jump", Rect: image.Rect(26, 0, 32, 12), Rotated: true, Trimmed: true,
+			SourceSize: f64.Vec2{16, 16}, Offset: f64.Vec2{2, 5}, Pivot: f64.Vec2{0.5, 1}},
+	}
+	for name, frames := range map[string]string{"hash": framesHash, "array": framesArray} {
+		t.Run(name, func(t *testing.T) {
+			atlas, img, err := ParseJSON([]byte(`{"frames": ` + frames + `, ` + meta + `}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img != "sheet.png" {
+				t.Fatalf("image %q, want sheet.png", img)
+			}
+			if order := []string{"walk_2", "walk_1", "jump"}; !reflect.DeepEqual(atlas.Order, order) {
+				t.Fatalf("Order %v, want the document order %v", atlas.Order, order)
+			}
+			for name, w := range want {
+				if f := atlas.Get(name); !reflect.DeepEqual(f, w) {
+					t.Fatalf("frame %s %+v, want %+v", name, f, w)
+				}
+			}
+
+			tags := []Tag{{Name: "walk", From: 0, To: 1, Direction: "forward"}, {Name: "jump", From: 2, To: 2, Direction: "pingpong", Repeat: 3}}
+			if !reflect.DeepEqual(atlas.Tags, tags) {
+				t.Fatalf("Tags %+v, want %+v", atlas.Tags, tags)
+			}
+			if frames := atlas.GetTagFrames("walk"); len(frames) != 2 || frames[0].Name != "walk_2" {
+				t.Fatalf("walk tag frames %v", frames)
+			}
+			key := atlas.GetSlice("hitbox").GetKey(0)
+			if key.Bounds != image.Rect(1, 2, 9, 11) || key.Pivot == nil || *key.Pivot != image.Pt(4, 9) || key.IsNineSlice() {
+				t.Fatalf("hitbox key %+v", key)
+			}
+		})
+	}
+}
+
+func TestParseJSONSourceSize(t *testing.T) {
+	// Without sourceSize the frame is its own source
+	atlas, _, err := ParseJSON([]byte(`{"frames": [{"filename": "a", "frame": {"x": 1, "y": 2, "w": 3, "h": 4}}], "meta": {"image": "a.png"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := atlas.Get("a"); f.SourceSize != (f64.Vec2{3, 4}) || f.Offset != (f64.Vec2{}) {
+		t.Fatalf("SourceSize %v Offset %v, want 3, 4 and 0, 0", f.SourceSize, f.Offset)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name, data, err string
+	}{
+		{"no image", `{"frames": {}, "meta": {}}`, "missing meta.image"},
+		{"no frames", `{"meta": {"image": "a.png"}}`, "missing frames"},
+		{"bad frame", `{"frames": {"a": {"frame": 3}}, "meta": {"image": "a.png"}}`, `frame "a"`},
+		{"not json", `frames`, "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseJSON([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/math/f64"
+)
+
+// Top edge of the used area of a page, from X to X+Width
+type skylineSegment struct {
+	x, y, width int
+}
+
+// A texture images are packed into
+type Page struct {
+	Image   *ebiten.Image
+	skyline []skylineSegment
+}
+
+/*
+Packer packs images into shared pages (skyline bottom-left), so drawing them doesn't switch textures
+Every image gets Padding transparent pixels around it plus Extrude pixels of its own edges repeated,
+which keeps neighbours from bleeding in with linear filtering or at fractional zoom
+Packed images are sub-images of a page and can be drawn anywhere an *ebiten.Image is accepted
+*/
+type Packer struct {
+	PageWidth, PageHeight int
+	Padding               int
+	Extrude               int
+	Pages                 []*Page
+}
+
+func NewPacker(pageWidth, pageHeight, padding, extrude int) *Packer {
+	return &Packer{
+		PageWidth:  pageWidth,
+		PageHeight: pageHeight,
+		Padding:    padding,
+		Extrude:    extrude,
+	}
+}
+
+// Packs an image (*ebiten.Image or any image.Image), returns its sub-image in a page
+func (p *Packer) Add(img image.Image) (*ebiten.Image, error) {
+	_, packed, err := p.add(img)
+	return packed, err
+}
+
+func (p *Packer) add(img image.Image) (*Page, *ebiten.Image, error) {
+	b := img.Bounds()
+	border := p.Padding + p.Extrude
+	w, h := b.Dx()+2*border, b.Dy()+2*border
+	if w > p.PageWidth || h > p.PageHeight {
+		return nil, nil, fmt.Errorf("sprite: %dx%d image doesn't fit in a %dx%d page", b.Dx(), b.Dy(), p.PageWidth, p.PageHeight)
+	}
+
+	var page *Page
+	x, y, ok := 0, 0, false
+	for _, pg := range p.Pages {
+		if x, y, ok = pg.find(w, h, p.PageWidth, p.PageHeight); ok {
+			page = pg
+			break
+		}
+	}
+	if page == nil {
+		page = &Page{
+			Image:   ebiten.NewImage(p.PageWidth, p.PageHeight),
+			skyline: []skylineSegment{{0, 0, p.PageWidth}},
+		}
+		p.Pages = append(p.Pages, page)
+		x, y, _ = page.find(w, h, p.PageWidth, p.PageHeight)
+	}
+	page.place(x, y, w, h)
+
+	src, ok := img.(*ebiten.Image)
+	if !ok {
+		src = ebiten.NewImageFromImage(img)
+		defer src.Dispose()
+	}
+	x, y = x+border, y+border
+	page.draw(src, x, y, p.Extrude)
+	return page, page.Image.SubImage(image.Rect(x, y, x+b.Dx(), y+b.Dy())).(*ebiten.Image), nil
+}
+
+// Packs several images tallest first (packs tighter than adding them one by one), results keep the order of images
+func (p *Packer) AddAll(images ...image.Image) ([]*ebiten.Image, error) {
+	_, packed, err := p.addAll(images)
+	return packed, err
+}
+
+func (p *Packer) addAll(images []image.Image) ([]*Page, []*ebiten.Image, error) {
+	order := make([]int, len(images))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return images[order[i]].Bounds().Dy() > images[order[j]].Bounds().Dy()
+	})
+
+	pages := make([]*Page, len(images))
+	packed := make([]*ebiten.Image, len(images))
+	for _, i := range order {
+		page, img, err := p.add(images[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		pages[i], packed[i] = page, img
+	}
+	return pages, packed, nil
+}
+
+// Packs named images into an Atlas (one per page), frames are looked up by name across all returned atlases
+func (p *Packer) PackAtlas(images map[string]image.Image) ([]*Atlas, error) {
+	names := make([]string, 0, len(images))
+	list := make([]image.Image, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, images[name])
+	}
+	pages, packed, err := p.addAll(list)
+	if err != nil {
+		return nil, err
+	}
+
+	atlases := map[*Page]*Atlas{}
+	var result []*Atlas
+	for i, img := range packed {
+		page := pages[i]
+		a, ok := atlases[page]
+		if !ok {
+			a = NewAtlas(page.Image)
+			atlases[page] = a
+			result = append(result, a)
+		}
+		b := img.Bounds()
+		a.addFrame(&Frame{
+			Name:       names[i],
+			Rect:       b,
+			SourceSize: f64.Vec2{float64(b.Dx()), float64(b.Dy())},
+		})
+	}
+	return result, nil
+}
+
+// Disposes every page, images returned by Add become unusable
+func (p *Packer) Dispose() {
+	for _, pg := range p.Pages {
+		pg.Image.Dispose()
+	}
+	p.Pages = nil
+}
+
+// Lowest position (then leftmost) a w x h rect fits at, resting on the skyline
+func (pg *Page) find(w, h, pageWidth, pageHeight int) (x, y int, ok bool) {
+	bestY := pageHeight
+	for i, seg := range pg.skyline {
+		if seg.x+w > pageWidth {
+			break
+		}
+		top := 0
+		remaining := w
+		for j := i; remaining > 0 && j < len(pg.skyline); j++ {
+			if pg.skyline[j].y > top {
+				top = pg.skyline[j].y
+			}
+			remaining -= pg.skyline[j].width
+		}
+		if top+h <= pageHeight && top < bestY {
+			x, y, bestY, ok = seg.x, top, top, true
+		}
+	}
+	return x, y, ok
+}
+
+// Raises the skyline over a placed rect (x is always the start of a segment)
+func (pg *Page) place(x, y, w, h int) {
+	placed := skylineSegment{x, y + h, w}
+	skyline := make([]skylineSegment, 0, len(pg.skyline)+2)
+	inserted := false
+	for _, seg := range pg.skyline {
+		end := seg.x + seg.width
+		if end <= x || seg.x >= x+w {
+			if seg.x >= x+w && !inserted {
+				skyline = append(skyline, placed)
+				inserted = true
+			}
+			skyline = append(skyline, seg)
+			continue
+		}
+		if !inserted {
+			skyline = append(skyline, placed)
+			inserted = true
+		}
+		// Keep the part sticking out on the right
+		if end > x+w {
+			skyline = append(skyline, skylineSegment{x + w, seg.y, end - x - w})
+		}
+	}
+	if !inserted {
+		skyline = append(skyline, placed)
+	}
+
+	// Merge neighbours of the same height
+	merged := skyline[:0]
+	for _, seg := range skyline {
+		if n := len(merged); n > 0 && merged[n-1].y == seg.y {
+			merged[n-1].width += seg.width
+			continue
+		}
+		merged = append(merged, seg)
+	}
+	pg.skyline = merged
+}
+
+// Draws src at x, y and repeats its edge pixels extrude times around it
+func (pg *Page) draw(src *ebiten.Image, x, y, extrude int) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	op := &ebiten.DrawImageOptions{}
+	op.Blend = ebiten.BlendCopy
+	op.GeoM.Translate(float64(x), float64(y))
+	pg.Image.DrawImage(src, op)
+	if extrude <= 0 || w == 0 || h == 0 {
+		return
+	}
+
+	e := float64(extrude)
+	strip := func(r image.Rectangle, sx, sy, tx, ty float64) {
+		op.GeoM.Reset()
+		op.GeoM.Scale(sx, sy)
+		op.GeoM.Translate(tx, ty)
+		pg.Image.DrawImage(src.SubImage(r.Add(b.Min)).(*ebiten.Image), op)
+	}
+	fx, fy, fw, fh := float64(x), float64(y), float64(w), float64(h)
+	strip(image.Rect(0, 0, w, 1), 1, e, fx, fy-e)    // Top
+	strip(image.Rect(0, h-1, w, h), 1, e, fx, fy+fh) // Bottom
+	strip(image.Rect(0, 0, 1, h), e, 1, fx-e, fy)    // Left
+	strip(image.Rect(w-1, 0, w, h), e, 1, fx+fw, fy) // Right
+	// Corners
+	strip(image.Rect(0, 0, 1, 1), e, e, fx-e, fy-e)
+	strip(image.Rect(w-1, 0, w, 1), e, e, fx+fw, fy-e)
+	strip(image.Rect(0, h-1, 1, h), e, e, fx-e, fy+fh)
+	strip(image.Rect(w-1, h-1, w, h), e, e, fx+fw, fy+fh)
+}
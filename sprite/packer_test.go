@@ -0,0 +1,134 @@
+package sprite
+
+import (
+	"image"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestPage(width int) *Page {
+	return &Page{skyline: []skylineSegment{{0, 0, width}}}
+}
+
+func TestPagePlacementDoesNotOverlap(t *testing.T) {
+	const pageWidth, pageHeight = 128, 128
+	pg := newTestPage(pageWidth)
+	rng := rand.New(rand.NewSource(1))
+	var placed []image.Rectangle
+	for i := 0; i < 200; i++ {
+		w, h := 4+rng.Intn(20), 4+rng.Intn(20)
+		x, y, ok := pg.find(w, h, pageWidth, pageHeight)
+		if !ok {
+			continue
+		}
+		r := image.Rect(x, y, x+w, y+h)
+		if !r.In(image.Rect(0, 0, pageWidth, pageHeight)) {
+			t.Fatalf("%v placed outside the page", r)
+		}
+		for _, other := range placed {
+			if r.Overlaps(other) {
+				t.Fatalf("%v overlaps %v", r, other)
+			}
+		}
+		placed = append(placed, r)
+		pg.place(x, y, w, h)
+
+		// Segments stay sorted, cover the page and never repeat a height
+		end := 0
+		for j, seg := range pg.skyline {
+			if seg.x != end || seg.width <= 0 || (j > 0 && pg.skyline[j-1].y == seg.y) {
+				t.Fatalf("skyline %v after placing %v", pg.skyline, r)
+			}
+			end += seg.width
+		}
+		if end != pageWidth {
+			t.Fatalf("skyline %v covers %d, want %d", pg.skyline, end, pageWidth)
+		}
+	}
+	if len(placed) < 30 {
+		t.Fatalf("only %d rects placed", len(placed))
+	}
+}
+
+func TestPageSkyline(t *testing.T) {
+	pg := newTestPage(64)
+	steps := []struct {
+		w, h    int
+		x, y    int
+		skyline []skylineSegment
+	}{
+		{16, 10, 0, 0, []skylineSegment{{0, 10, 16}, {16, 0, 48}}},
+		{16, 20, 16, 0, []skylineSegment{{0, 10, 16}, {16, 20, 16}, {32, 0, 32}}},
+		// Same height as the first, they merge
+		{32, 10, 32, 0, []skylineSegment{{0, 10, 16}, {16, 20, 16}, {32, 10, 32}}},
+		// Lowest spot first, then leftmost
+		{16, 10, 0, 10, []skylineSegment{{0, 20, 32}, {32, 10, 32}}},
+		{40, 5, 0, 20, []skylineSegment{{0, 25, 40}, {40, 10, 24}}},
+		{24, 15, 40, 10, []skylineSegment{{0, 25, 64}}},
+	}
+	for i, s := range steps {
+		x, y, ok := pg.find(s.w, s.h, 64, 64)
+		if !ok || x != s.x || y != s.y {
+			t.Fatalf("step %d: find(%d, %d) = %d, %d, %v, want %d, %d", i, s.w, s.h, x, y, ok, s.x, s.y)
+		}
+		pg.place(x, y, s.w, s.h)
+		if !reflect.DeepEqual(pg.skyline, s.skyline) {
+			t.Fatalf("step %d: skyline %v, want %v", i, pg.skyline, s.skyline)
+		}
+	}
+	if _, _, ok := pg.find(8, 40, 64, 64); ok {
+		t.Fatal("found room above the top of the page")
+	}
+	if _, _, ok := pg.find(65, 1, 64, 64); ok {
+		t.Fatal("found room wider than the page")
+	}
+}
+
+func TestPackerPages(t *testing.T) {
+	p := NewPacker(32, 32, 1, 1)
+	defer p.Dispose()
+
+	// 12x12 with 2 pixels of border on each side, 4 fill a page
+	var packed []image.Rectangle
+	for i := 0; i < 5; i++ {
+		img, err := p.Add(image.NewRGBA(image.Rect(0, 0, 12, 12)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		packed = append(packed, img.Bounds())
+	}
+	want := []image.Rectangle{
+		image.Rect(2, 2, 14, 14), image.Rect(18, 2, 30, 14),
+		image.Rect(2, 18, 14, 30), image.Rect(18, 18, 30, 30),
+		image.Rect(2, 2, 14, 14),
+	}
+	if !reflect.DeepEqual(packed, want) {
+		t.Fatalf("packed at %v, want %v", packed, want)
+	}
+	if len(p.Pages) != 2 {
+		t.Fatalf("%d pages, want the 5th image on a new page", len(p.Pages))
+	}
+
+	// Smaller images still go to the first page with room
+	p.Pages[0].skyline = []skylineSegment{{0, 26, 32}}
+	img, err := p.Add(image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(2, 28, 4, 30) || len(p.Pages) != 2 {
+		t.Fatalf("small image at %v, want the bottom of page 1", img.Bounds())
+	}
+
+	// Doesn't fit even in an empty page (border included)
+	for _, size := range []image.Point{{29, 4}, {4, 29}, {100, 100}} {
+		_, err := p.Add(image.NewRGBA(image.Rectangle{Max: size}))
+		if err == nil || !strings.Contains(err.Error(), "doesn't fit") {
+			t.Fatalf("%v image: error %v, want too large", size, err)
+		}
+	}
+	if len(p.Pages) != 2 {
+		t.Fatalf("%d pages after failed adds, want 2", len(p.Pages))
+	}
+}
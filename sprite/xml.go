@@ -0,0 +1,112 @@
+package sprite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+
+	"golang.org/x/image/math/f64"
+)
+
+type xmlAtlas struct {
+	ImagePath   string          `xml:"imagePath,attr"`
+	SubTextures []xmlSubTexture `xml:"SubTexture"`
+	Sprites     []xmlSprite     `xml:"sprite"`
+}
+
+// Starling/Sparrow format, frameX/frameY are negative offsets and pivots are in pixels
+type xmlSubTexture struct {
+	Name        string   `xml:"name,attr"`
+	X           int      `xml:"x,attr"`
+	Y           int      `xml:"y,attr"`
+	Width       int      `xml:"width,attr"`
+	Height      int      `xml:"height,attr"`
+	FrameX      int      `xml:"frameX,attr"`
+	FrameY      int      `xml:"frameY,attr"`
+	FrameWidth  int      `xml:"frameWidth,attr"`
+	FrameHeight int      `xml:"frameHeight,attr"`
+	Rotated     bool     `xml:"rotated,attr"`
+	PivotX      *float64 `xml:"pivotX,attr"`
+	PivotY      *float64 `xml:"pivotY,attr"`
+}
+
+// TexturePacker generic XML, sizes are unrotated and pivots are normalized
+type xmlSprite struct {
+	Name           string   `xml:"n,attr"`
+	X              int      `xml:"x,attr"`
+	Y              int      `xml:"y,attr"`
+	Width          int      `xml:"w,attr"`
+	Height         int      `xml:"h,attr"`
+	OffsetX        int      `xml:"oX,attr"`
+	OffsetY        int      `xml:"oY,attr"`
+	OriginalWidth  int      `xml:"oW,attr"`
+	OriginalHeight int      `xml:"oH,attr"`
+	Rotated        string   `xml:"r,attr"`
+	PivotX         *float64 `xml:"pX,attr"`
+	PivotY         *float64 `xml:"pY,attr"`
+}
+
+// Parses a TexturePacker XML (Starling/Sparrow or generic) descriptor, returns the Atlas (without texture) and imagePath
+func ParseXML(data []byte) (*Atlas, string, error) {
+	var doc xmlAtlas
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc.ImagePath == "" {
+		return nil, "", fmt.Errorf("missing imagePath")
+	}
+
+	atlas := NewAtlas(nil)
+	for _, st := range doc.SubTextures {
+		atlas.addFrame(st.toFrame())
+	}
+	for _, sp := range doc.Sprites {
+		atlas.addFrame(sp.toFrame())
+	}
+	return atlas, doc.ImagePath, nil
+}
+
+func (st xmlSubTexture) toFrame() *Frame {
+	f := &Frame{
+		Name:    st.Name,
+		Rect:    image.Rect(st.X, st.Y, st.X+st.Width, st.Y+st.Height),
+		Rotated: st.Rotated,
+	}
+	// Region is as stored in the texture
+	w, h := st.Width, st.Height
+	if st.Rotated {
+		w, h = h, w
+	}
+	f.SourceSize = f64.Vec2{float64(w), float64(h)}
+	if st.FrameWidth > 0 && st.FrameHeight > 0 {
+		f.Trimmed = true
+		f.SourceSize = f64.Vec2{float64(st.FrameWidth), float64(st.FrameHeight)}
+		f.Offset = f64.Vec2{float64(-st.FrameX), float64(-st.FrameY)}
+	}
+	if st.PivotX != nil && st.PivotY != nil {
+		f.Pivot = f64.Vec2{*st.PivotX / f.SourceSize[0], *st.PivotY / f.SourceSize[1]}
+	}
+	return f
+}
+
+func (sp xmlSprite) toFrame() *Frame {
+	f := &Frame{
+		Name:    sp.Name,
+		Rotated: sp.Rotated == "y",
+	}
+	w, h := sp.Width, sp.Height
+	if f.Rotated {
+		w, h = h, w
+	}
+	f.Rect = image.Rect(sp.X, sp.Y, sp.X+w, sp.Y+h)
+	f.SourceSize = f64.Vec2{float64(sp.Width), float64(sp.Height)}
+	if sp.OriginalWidth > 0 && sp.OriginalHeight > 0 {
+		f.Trimmed = sp.OriginalWidth != sp.Width || sp.OriginalHeight != sp.Height
+		f.SourceSize = f64.Vec2{float64(sp.OriginalWidth), float64(sp.OriginalHeight)}
+		f.Offset = f64.Vec2{float64(sp.OffsetX), float64(sp.OffsetY)}
+	}
+	if sp.PivotX != nil && sp.PivotY != nil {
+		f.Pivot = f64.Vec2{*sp.PivotX, *sp.PivotY}
+	}
+	return f
+}
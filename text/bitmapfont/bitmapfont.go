@@ -0,0 +1,156 @@
+package bitmapfont
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Single glyph of a Bitmap Font (in unscaled pixels)
+type Glyph struct {
+	Rect    image.Rectangle // Glyph's area on the Page
+	Page    int
+	Offset  image.Point // Offset of the glyph's TopLeft from the TopLeft of the line
+	Advance int
+}
+
+/*
+Face is a Bitmap Font (BMFont/AngelCode or fixed-grid Sprite Font) implementing font.Face,
+so it can be passed anywhere a TTF face can be (utils.DrawText, Screen.DrawText, Overlay.DrawText, richtext).
+NOTE: Only the alpha of glyphs is used, as a mask tinted with the text color (their own color is ignored).
+*/
+type Face struct {
+	Pages      []image.Image
+	Glyphs     map[rune]*Glyph
+	Kerning    map[[2]rune]int
+	LineHeight int  // Distance between two lines
+	Base       int  // Distance from the top of the line to the baseline
+	Scale      int  // Integer scale for Pixel-Art fonts, 0 means 1
+	Fallback   rune // Drawn for runes that are missing, 0 means nothing is drawn
+
+	scaled map[rune]*image.Alpha // Cache of scaled glyph masks
+}
+
+func newFace() *Face {
+	return &Face{
+		Glyphs:  map[rune]*Glyph{},
+		Kerning: map[[2]rune]int{},
+		Scale:   1,
+		scaled:  map[rune]*image.Alpha{},
+	}
+}
+
+// Returns a copy of the Face drawn at a different integer scale
+func (f *Face) WithScale(scale int) *Face {
+	c := *f
+	c.Scale = scale
+	c.scaled = map[rune]*image.Alpha{}
+	return &c
+}
+
+func (f *Face) scale() int {
+	if f.Scale < 1 {
+		return 1
+	}
+	return f.Scale
+}
+
+func (f *Face) glyph(r rune) (*Glyph, rune, bool) {
+	if g, ok := f.Glyphs[r]; ok {
+		return g, r, true
+	}
+	if g, ok := f.Glyphs[f.Fallback]; ok && f.Fallback != 0 {
+		return g, f.Fallback, true
+	}
+	return nil, r, false
+}
+
+func (f *Face) Close() error {
+	return nil
+}
+
+func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	g, r, ok := f.glyph(r)
+	if !ok || g.Page < 0 || g.Page >= len(f.Pages) || f.Pages[g.Page] == nil {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	s := f.scale()
+
+	x := dot.X.Floor() + g.Offset.X*s
+	y := dot.Y.Floor() + (g.Offset.Y-f.Base)*s
+	dr = image.Rect(x, y, x+g.Rect.Dx()*s, y+g.Rect.Dy()*s)
+	advance = fixed.I(g.Advance * s)
+
+	if s == 1 {
+		return dr, f.Pages[g.Page], g.Rect.Min, advance, true
+	}
+	return dr, f.scaledMask(r, g, s), image.Point{}, advance, true
+}
+
+// Scales (nearest neighbour) glyph's alpha into a separate mask
+func (f *Face) scaledMask(r rune, g *Glyph, s int) *image.Alpha {
+	if f.scaled == nil {
+		f.scaled = map[rune]*image.Alpha{}
+	}
+	if m, ok := f.scaled[r]; ok {
+		return m
+	}
+	page := f.Pages[g.Page]
+	m := image.NewAlpha(image.Rect(0, 0, g.Rect.Dx()*s, g.Rect.Dy()*s))
+	for y := 0; y < g.Rect.Dy(); y++ {
+		for x := 0; x < g.Rect.Dx(); x++ {
+			_, _, _, a := page.At(g.Rect.Min.X+x, g.Rect.Min.Y+y).RGBA()
+			c := color.Alpha{uint8(a >> 8)}
+			draw.Draw(m, image.Rect(x*s, y*s, (x+1)*s, (y+1)*s), image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+	f.scaled[r] = m
+	return m
+}
+
+func (f *Face) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	g, _, ok := f.glyph(r)
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	s := f.scale()
+	x, y := g.Offset.X*s, (g.Offset.Y-f.Base)*s
+	bounds = fixed.Rectangle26_6{
+		Min: fixed.P(x, y),
+		Max: fixed.P(x+g.Rect.Dx()*s, y+g.Rect.Dy()*s),
+	}
+	return bounds, fixed.I(g.Advance * s), true
+}
+
+func (f *Face) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	g, _, ok := f.glyph(r)
+	if !ok {
+		return 0, false
+	}
+	return fixed.I(g.Advance * f.scale()), true
+}
+
+func (f *Face) Kern(r0, r1 rune) fixed.Int26_6 {
+	return fixed.I(f.Kerning[[2]rune{r0, r1}] * f.scale())
+}
+
+func (f *Face) Metrics() font.Metrics {
+	s := f.scale()
+	m := font.Metrics{
+		Height:    fixed.I(f.LineHeight * s),
+		Ascent:    fixed.I(f.Base * s),
+		Descent:   fixed.I((f.LineHeight - f.Base) * s),
+		CapHeight: fixed.I(f.Base * s),
+		XHeight:   fixed.I(f.Base * s / 2),
+	}
+	if g, ok := f.Glyphs['x']; ok {
+		m.XHeight = fixed.I((f.Base - g.Offset.Y) * s)
+	}
+	if g, ok := f.Glyphs['H']; ok {
+		m.CapHeight = fixed.I((f.Base - g.Offset.Y) * s)
+	}
+	return m
+}
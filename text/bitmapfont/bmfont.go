@@ -0,0 +1,219 @@
+package bitmapfont
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Called for every page image referenced by a BMFont descriptor
+type PageLoader func(file string) (image.Image, error)
+
+type bmChar struct {
+	ID       int `xml:"id,attr"`
+	X        int `xml:"x,attr"`
+	Y        int `xml:"y,attr"`
+	Width    int `xml:"width,attr"`
+	Height   int `xml:"height,attr"`
+	XOffset  int `xml:"xoffset,attr"`
+	YOffset  int `xml:"yoffset,attr"`
+	XAdvance int `xml:"xadvance,attr"`
+	Page     int `xml:"page,attr"`
+}
+
+type bmKerning struct {
+	First  int `xml:"first,attr"`
+	Second int `xml:"second,attr"`
+	Amount int `xml:"amount,attr"`
+}
+
+type bmPage struct {
+	ID   int    `xml:"id,attr"`
+	File string `xml:"file,attr"`
+}
+
+// Layout of a BMFont descriptor, used for both text and XML formats
+type bmFont struct {
+	Common struct {
+		LineHeight int `xml:"lineHeight,attr"`
+		Base       int `xml:"base,attr"`
+	} `xml:"common"`
+	Pages    []bmPage    `xml:"pages>page"`
+	Chars    []bmChar    `xml:"chars>char"`
+	Kernings []bmKerning `xml:"kernings>kerning"`
+}
+
+/*
+Parses a BMFont (AngelCode) descriptor in text or XML format
+loadPage is called with the file name of every page (as written in the descriptor)
+*/
+func ParseBMFont(descriptor []byte, loadPage PageLoader) (*Face, error) {
+	var desc *bmFont
+	var err error
+	if trimmed := bytes.TrimSpace(descriptor); bytes.HasPrefix(trimmed, []byte("<")) {
+		desc = &bmFont{}
+		err = xml.Unmarshal(descriptor, desc)
+	} else {
+		desc, err = parseBMFontText(descriptor)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bitmapfont: invalid descriptor: %w", err)
+	}
+
+	face := newFace()
+	face.LineHeight = desc.Common.LineHeight
+	face.Base = desc.Common.Base
+
+	for _, p := range desc.Pages {
+		if p.ID < 0 {
+			return nil, fmt.Errorf("bitmapfont: invalid page id %d", p.ID)
+		}
+		img, err := loadPage(p.File)
+		if err != nil {
+			return nil, fmt.Errorf("bitmapfont: loading page %q: %w", p.File, err)
+		}
+		for len(face.Pages) <= p.ID {
+			face.Pages = append(face.Pages, nil)
+		}
+		face.Pages[p.ID] = img
+	}
+
+	for _, c := range desc.Chars {
+		// Page ids can be sparse, every page used by a char must have been loaded
+		if c.Page < 0 || c.Page >= len(face.Pages) || face.Pages[c.Page] == nil {
+			return nil, fmt.Errorf("bitmapfont: char %d uses missing page %d", c.ID, c.Page)
+		}
+		face.Glyphs[rune(c.ID)] = &Glyph{
+			Rect:    image.Rect(c.X, c.Y, c.X+c.Width, c.Y+c.Height),
+			Page:    c.Page,
+			Offset:  image.Pt(c.XOffset, c.YOffset),
+			Advance: c.XAdvance,
+		}
+	}
+	for _, k := range desc.Kernings {
+		face.Kerning[[2]rune{rune(k.First), rune(k.Second)}] = k.Amount
+	}
+	if _, ok := face.Glyphs['?']; ok {
+		face.Fallback = '?'
+	}
+	return face, nil
+}
+
+// Lines of the text format look like: char id=65 x=10 y=0 width=8 ... page=0
+func parseBMFontText(descriptor []byte) (*bmFont, error) {
+	desc := &bmFont{}
+	scanner := bufio.NewScanner(bytes.NewReader(descriptor))
+	for scanner.Scan() {
+		tag, attrs := parseBMFontLine(scanner.Text())
+		num := func(key string) int {
+			n, _ := strconv.Atoi(attrs[key])
+			return n
+		}
+		switch tag {
+		case "common":
+			desc.Common.LineHeight = num("lineHeight")
+			desc.Common.Base = num("base")
+		case "page":
+			desc.Pages = append(desc.Pages, bmPage{ID: num("id"), File: attrs["file"]})
+		case "char":
+			desc.Chars = append(desc.Chars, bmChar{
+				ID: num("id"), X: num("x"), Y: num("y"),
+				Width: num("width"), Height: num("height"),
+				XOffset: num("xoffset"), YOffset: num("yoffset"),
+				XAdvance: num("xadvance"), Page: num("page"),
+			})
+		case "kerning":
+			desc.Kernings = append(desc.Kernings, bmKerning{
+				First: num("first"), Second: num("second"), Amount: num("amount"),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if desc.Common.LineHeight == 0 {
+		return nil, fmt.Errorf("missing common lineHeight")
+	}
+	return desc, nil
+}
+
+// Splits a line into its tag and key=value attributes (values can be quoted)
+func parseBMFontLine(line string) (tag string, attrs map[string]string) {
+	attrs = map[string]string{}
+	line = strings.TrimSpace(line)
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		tag, line = line[:i], line[i+1:]
+	} else {
+		return line, attrs
+	}
+
+	for {
+		line = strings.TrimLeft(line, " \t")
+		eq := strings.IndexRune(line, '=')
+		if eq < 0 {
+			return tag, attrs
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexRune(line[1:], '"')
+			if end < 0 {
+				value, line = line[1:], ""
+			} else {
+				value, line = line[1:end+1], line[end+2:]
+			}
+		} else if sp := strings.IndexAny(line, " \t"); sp >= 0 {
+			value, line = line[:sp], line[sp:]
+		} else {
+			value, line = line, ""
+		}
+		attrs[key] = value
+	}
+}
+
+// Loads a BMFont descriptor from disk, pages are loaded relative to the descriptor
+func LoadBMFont(descriptorPath string) (*Face, error) {
+	descriptor, err := os.ReadFile(descriptorPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(descriptorPath)
+	return ParseBMFont(descriptor, func(file string) (image.Image, error) {
+		f, err := os.Open(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		return img, err
+	})
+}
+
+// Same as LoadBMFont but from a file system (eg: embed.FS)
+func LoadBMFontFS(fsys fs.FS, descriptorPath string) (*Face, error) {
+	descriptor, err := fs.ReadFile(fsys, descriptorPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := path.Dir(descriptorPath)
+	return ParseBMFont(descriptor, func(file string) (image.Image, error) {
+		f, err := fsys.Open(path.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		return img, err
+	})
+}
@@ -0,0 +1,75 @@
+package bitmapfont
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// Every page is a 16x16 alpha image
+func testPages(file string) (image.Image, error) {
+	img := image.NewAlpha(image.Rect(0, 0, 16, 16))
+	img.SetAlpha(1, 1, color.Alpha{255})
+	return img, nil
+}
+
+func TestParseBMFontPages(t *testing.T) {
+	tests := []struct {
+		name, descriptor, err string
+	}{
+		{"text", `info face="test"
+common lineHeight=10 base=8 pages=2
+page id=0 file="a.png"
+page id=1 file="b.png"
+char id=65 x=0 y=0 width=4 height=6 xoffset=0 yoffset=2 xadvance=5 page=0
+char id=66 x=4 y=0 width=4 height=6 xoffset=0 yoffset=2 xadvance=5 page=1`, ""},
+		{"xml", `<?xml version="1.0"?>
+<font><common lineHeight="10" base="8"/>
+<pages><page id="0" file="a.png"/><page id="1" file="b.png"/></pages>
+<chars><char id="65" width="4" height="6" xadvance="5" page="0"/><char id="66" width="4" height="6" xadvance="5" page="1"/></chars>
+</font>`, ""},
+		// Page 1 is skipped, so Pages has a nil entry
+		{"sparse", `common lineHeight=10 base=8
+page id=0 file="a.png"
+page id=2 file="c.png"
+char id=65 width=4 height=6 xadvance=5 page=0
+char id=66 width=4 height=6 xadvance=5 page=1`, "char 66 uses missing page 1"},
+		{"past the last page", `common lineHeight=10 base=8
+page id=0 file="a.png"
+char id=65 width=4 height=6 xadvance=5 page=3`, "char 65 uses missing page 3"},
+		{"negative page id", `common lineHeight=10 base=8
+page id=-1 file="a.png"`, "invalid page id -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			face, err := ParseBMFont([]byte(tt.descriptor), testPages)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range "AB" {
+				_, mask, _, advance, ok := face.Glyph(fixed.P(0, 8), r)
+				if !ok || mask == nil || advance != fixed.I(5) {
+					t.Fatalf("Glyph(%q) = mask %v advance %v ok %v", r, mask, advance, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestGlyphWithoutPage(t *testing.T) {
+	face := newFace()
+	face.Pages = []image.Image{nil}
+	face.Glyphs['A'] = &Glyph{Rect: image.Rect(0, 0, 4, 4), Advance: 5}
+	if _, mask, _, _, ok := face.Glyph(fixed.P(0, 0), 'A'); ok || mask != nil {
+		t.Fatal("Glyph() on a nil page reported ok")
+	}
+}
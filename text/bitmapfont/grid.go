@@ -0,0 +1,86 @@
+package bitmapfont
+
+import (
+	"image"
+	"os"
+)
+
+/*
+Creates a Sprite Font from an image where glyphs are laid out on a fixed grid
+chars lists the glyphs in row-major order (left to right, top to bottom)
+If proportional is true, advance of every glyph is trimmed to its non-transparent columns
+*/
+func NewGridFont(img image.Image, cellWidth, cellHeight int, chars string, proportional bool) *Face {
+	face := newFace()
+	face.Pages = []image.Image{img}
+	face.LineHeight = cellHeight
+	face.Base = cellHeight
+
+	b := img.Bounds()
+	columns := b.Dx() / cellWidth
+	if columns < 1 {
+		return face
+	}
+
+	i := 0
+	for _, r := range chars {
+		x := b.Min.X + (i%columns)*cellWidth
+		y := b.Min.Y + (i/columns)*cellHeight
+		i++
+		if y+cellHeight > b.Max.Y {
+			break
+		}
+		rect := image.Rect(x, y, x+cellWidth, y+cellHeight)
+		glyph := &Glyph{Rect: rect, Advance: cellWidth}
+
+		if proportional {
+			left, right := opaqueColumns(img, rect)
+			if right < left {
+				// Empty cell (eg: space), keep half a cell of advance
+				glyph.Advance = cellWidth / 2
+				glyph.Rect = image.Rect(x, y, x, y+cellHeight)
+			} else {
+				glyph.Rect = image.Rect(left, y, right+1, y+cellHeight)
+				glyph.Advance = right - left + 2 // 1px spacing
+			}
+		}
+		face.Glyphs[r] = glyph
+	}
+	if _, ok := face.Glyphs['?']; ok {
+		face.Fallback = '?'
+	}
+	return face
+}
+
+// First and last columns (inclusive) with any non-transparent pixel, right < left if empty
+func opaqueColumns(img image.Image, rect image.Rectangle) (left, right int) {
+	left, right = rect.Max.X, rect.Min.X-1
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a > 0 {
+				if x < left {
+					left = x
+				}
+				if x > right {
+					right = x
+				}
+				break
+			}
+		}
+	}
+	return left, right
+}
+
+// Loads a grid Sprite Font image from disk
+func LoadGridFont(imagePath string, cellWidth, cellHeight int, chars string, proportional bool) (*Face, error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewGridFont(img, cellWidth, cellHeight, chars, proportional), nil
+}
@@ -0,0 +1,190 @@
+package fonts
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"sync"
+
+	ovr "github.com/shubhamdwivedii/gopher-engine/scene/overlay"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+const DEFAULT_DPI = 72
+
+type FaceOptions struct {
+	Size    float64 // In points (same as pixels at DEFAULT_DPI)
+	DPI     float64 // 0 means DEFAULT_DPI
+	Hinting font.Hinting
+}
+
+type faceKey struct {
+	name    string
+	size    float64
+	dpi     float64
+	hinting font.Hinting
+}
+
+/*
+Registry loads TTF/OTF fonts once (by name) and caches faces per (font, size, DPI, hinting)
+Sizes are multiplied by Scale, keep it in sync with the Overlay (see SyncScale) so text drawn
+at Render Resolution stays crisp when the window is resized.
+*/
+type Registry struct {
+	Scale float64
+
+	mutex sync.Mutex
+	fonts map[string]*opentype.Font
+	faces map[faceKey]font.Face
+}
+
+func New() *Registry {
+	return &Registry{
+		Scale: 1,
+		fonts: map[string]*opentype.Font{},
+		faces: map[faceKey]font.Face{},
+	}
+}
+
+/*
+Parses TTF/OTF data and registers it under name (replacing any font with same name)
+Faces already handed out keep drawing with the old font, fetch them again (Face) to get the new one
+*/
+func (r *Registry) LoadBytes(name string, data []byte) error {
+	fnt, err := opentype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("fonts: parsing %q: %w", name, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.dropFaces(name)
+	r.fonts[name] = fnt
+	return nil
+}
+
+func (r *Registry) Load(name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("fonts: loading %q: %w", name, err)
+	}
+	return r.LoadBytes(name, data)
+}
+
+// Same as Load but from a file system (eg: embed.FS)
+func (r *Registry) LoadFS(name string, fsys fs.FS, path string) error {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return fmt.Errorf("fonts: loading %q: %w", name, err)
+	}
+	return r.LoadBytes(name, data)
+}
+
+func (r *Registry) Has(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.fonts[name]
+	return ok
+}
+
+// Face of size (in points) at DEFAULT_DPI with full hinting, multiplied by Scale
+func (r *Registry) Face(name string, size float64) (font.Face, error) {
+	return r.FaceWith(name, FaceOptions{Size: size, Hinting: font.HintingFull})
+}
+
+// Face with given options, multiplied by Scale
+func (r *Registry) FaceWith(name string, opts FaceOptions) (font.Face, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if opts.DPI == 0 {
+		opts.DPI = DEFAULT_DPI
+	}
+	scale := r.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	// Rounded to a quarter point, so tiny scale changes don't create new faces
+	size := math.Round(opts.Size*scale*4) / 4
+
+	key := faceKey{name: name, size: size, dpi: opts.DPI, hinting: opts.Hinting}
+	if face, ok := r.faces[key]; ok {
+		return face, nil
+	}
+
+	fnt, ok := r.fonts[name]
+	if !ok {
+		return nil, fmt.Errorf("fonts: font %q is not loaded", name)
+	}
+	face, err := opentype.NewFace(fnt, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     opts.DPI,
+		Hinting: opts.Hinting,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fonts: creating face of %q: %w", name, err)
+	}
+	r.faces[key] = face
+	return face, nil
+}
+
+// Same as Face but panics on error, handy for package level vars
+func (r *Registry) MustFace(name string, size float64) font.Face {
+	face, err := r.Face(name, size)
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+func (r *Registry) SetScale(scale float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Scale = scale
+}
+
+/*
+Sets Scale to the (smaller axis) scale of the Overlay, returns true if it changed
+Faces fetched after this are sized for the Overlay's Render Resolution
+*/
+func (r *Registry) SyncScale(overlay ovr.Overlay) bool {
+	s := overlay.GetScale()
+	scale := math.Min(s[0], s[1])
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if scale == r.Scale {
+		return false
+	}
+	r.Scale = scale
+	return true
+}
+
+// Closes and forgets all cached faces (fonts stay loaded)
+func (r *Registry) ClearFaces() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key, face := range r.faces {
+		face.Close()
+		delete(r.faces, key)
+	}
+}
+
+// Unloads a font and forgets its faces (faces already handed out keep working)
+func (r *Registry) Unload(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.dropFaces(name)
+	delete(r.fonts, name)
+}
+
+// Faces aren't closed, callers may still be drawing with them
+func (r *Registry) dropFaces(name string) {
+	for key := range r.faces {
+		if key.name == name {
+			delete(r.faces, key)
+		}
+	}
+}
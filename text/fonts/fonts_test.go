@@ -0,0 +1,47 @@
+package fonts
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestReplacedFontKeepsOldFaces(t *testing.T) {
+	r := New()
+	if err := r.LoadBytes("ui", goregular.TTF); err != nil {
+		t.Fatal(err)
+	}
+	old, err := r.Face("ui", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := r.MustFace("ui", 16); again != old {
+		t.Fatal("same size didn't reuse the cached face")
+	}
+	regular, _ := old.GlyphAdvance('W')
+
+	// Replacing the font hands out new faces, the old one still works
+	if err := r.LoadBytes("ui", gomono.TTF); err != nil {
+		t.Fatal(err)
+	}
+	mono := r.MustFace("ui", 16)
+	if mono == old {
+		t.Fatal("Face() returned the face of the replaced font")
+	}
+	if a, ok := old.GlyphAdvance('W'); !ok || a != regular {
+		t.Fatalf("old face advance %v %v, want %v", a, ok, regular)
+	}
+	if a, _ := mono.GlyphAdvance('W'); a == regular {
+		t.Fatal("new face has the advance of the old font")
+	}
+
+	r.Unload("ui")
+	if _, err := r.Face("ui", 16); err == nil {
+		t.Fatal("Face() of an unloaded font didn't fail")
+	}
+	if a, ok := mono.GlyphAdvance('W'); !ok || a == fixed.Int26_6(0) {
+		t.Fatal("face of an unloaded font stopped working")
+	}
+}
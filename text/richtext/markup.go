@@ -0,0 +1,199 @@
+package richtext
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode"
+)
+
+/*
+Supported Markup :-
+	[color=#ff8800]...[/color]  or  [color=red]...[/color]
+	[b]...[/b]                  switches to Options.BoldFace
+	[icon=name]                 draws Options.Icons[name] inline
+	[[                          literal '['
+Tags can be nested, unknown tags are drawn as plain text.
+*/
+
+type style struct {
+	color color.Color
+	bold  bool
+}
+
+const (
+	tokenWord = iota
+	tokenSpace
+	tokenNewline
+	tokenIcon
+)
+
+type piece struct {
+	text  string
+	style style
+}
+
+type token struct {
+	kind   int
+	pieces []piece // A word can have multiple styles (eg: "he[b]llo[/b]")
+	icon   string
+	style  style
+}
+
+var namedColors = map[string]color.Color{
+	"white":  color.White,
+	"black":  color.Black,
+	"red":    color.RGBA{255, 0, 0, 255},
+	"green":  color.RGBA{0, 255, 0, 255},
+	"blue":   color.RGBA{0, 0, 255, 255},
+	"yellow": color.RGBA{255, 255, 0, 255},
+	"orange": color.RGBA{255, 165, 0, 255},
+	"gray":   color.RGBA{128, 128, 128, 255},
+}
+
+// Accepts #rgb, #rrggbb, #rrggbbaa or one of the named colors
+func ParseColor(s string) (color.Color, error) {
+	if c, ok := namedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	if !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	var r, g, b, a uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &r, &g, &b, &a); err != nil || len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{r, g, b, a}, nil
+}
+
+type tokenizer struct {
+	tokens []token
+	stack  []style
+	word   []piece
+	buf    strings.Builder
+}
+
+func (t *tokenizer) current() style {
+	return t.stack[len(t.stack)-1]
+}
+
+func (t *tokenizer) flushPiece() {
+	if t.buf.Len() > 0 {
+		t.word = append(t.word, piece{text: t.buf.String(), style: t.current()})
+		t.buf.Reset()
+	}
+}
+
+func (t *tokenizer) flushWord() {
+	t.flushPiece()
+	if len(t.word) > 0 {
+		t.tokens = append(t.tokens, token{kind: tokenWord, pieces: t.word})
+		t.word = nil
+	}
+}
+
+// Returns false if tag is not a known tag (it is then drawn as text)
+func (t *tokenizer) applyTag(tag string) bool {
+	name, value := tag, ""
+	if eq := strings.IndexRune(tag, '='); eq >= 0 {
+		name, value = tag[:eq], tag[eq+1:]
+	}
+
+	switch name {
+	case "b":
+		t.flushPiece()
+		s := t.current()
+		s.bold = true
+		t.stack = append(t.stack, s)
+	case "color":
+		c, err := ParseColor(value)
+		if err != nil {
+			return false
+		}
+		t.flushPiece()
+		s := t.current()
+		s.color = c
+		t.stack = append(t.stack, s)
+	case "/b", "/color":
+		t.flushPiece()
+		if len(t.stack) > 1 {
+			t.stack = t.stack[:len(t.stack)-1]
+		}
+	case "icon":
+		if value == "" {
+			return false
+		}
+		t.flushWord()
+		t.tokens = append(t.tokens, token{kind: tokenIcon, icon: value, style: t.current()})
+	default:
+		return false
+	}
+	return true
+}
+
+// Splits markup into words, spaces, newlines and icons
+func tokenize(txt string, base style) []token {
+	t := &tokenizer{stack: []style{base}}
+
+	runes := []rune(txt)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '[' {
+			if i+1 < len(runes) && runes[i+1] == '[' {
+				t.buf.WriteRune('[')
+				i++
+				continue
+			}
+			end := -1
+			for j := i + 1; j < len(runes); j++ {
+				if runes[j] == ']' {
+					end = j
+					break
+				}
+			}
+			if end > i && t.applyTag(string(runes[i+1:end])) {
+				i = end
+				continue
+			}
+		}
+
+		switch {
+		case r == '\n':
+			t.flushWord()
+			t.tokens = append(t.tokens, token{kind: tokenNewline})
+		case unicode.IsSpace(r):
+			t.flushWord()
+			t.tokens = append(t.tokens, token{kind: tokenSpace, style: t.current()})
+		default:
+			t.buf.WriteRune(r)
+		}
+	}
+	t.flushWord()
+	return t.tokens
+}
+
+// Removes all markup, returns plain text
+func StripMarkup(txt string) string {
+	var b strings.Builder
+	for _, t := range tokenize(txt, style{}) {
+		switch t.kind {
+		case tokenWord:
+			for _, p := range t.pieces {
+				b.WriteString(p.text)
+			}
+		case tokenSpace:
+			b.WriteRune(' ')
+		case tokenNewline:
+			b.WriteRune('\n')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,348 @@
+package richtext
+
+import (
+	"image/color"
+	"math"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/font"
+)
+
+type Options struct {
+	Width       float64 // Width to wrap at, 0 means no wrapping (only explicit newlines)
+	Align       string  // One of the TEXT_ALIGN_* constants, defaults to TEXT_ALIGN_LEFT
+	LineSpacing float64 // Multiplier of the line height, 0 means 1
+	Color       color.Color
+	BoldFace    font.Face                // Used for [b], if nil text is drawn twice (1px apart) instead
+	Icons       map[string]*ebiten.Image // Used for [icon=name], scaled to line height
+}
+
+// Single drawable piece of a laid out line (text run or icon)
+type Item struct {
+	Text  string
+	Face  font.Face
+	Color color.Color
+	Bold  bool // Fake bold (no BoldFace)
+	Icon  *ebiten.Image
+	X     float64 // Relative to the left of the Text block
+	Width float64
+	space bool
+}
+
+type Line struct {
+	Items    []Item
+	Width    float64
+	Baseline float64 // Relative to the top of the Text block
+}
+
+// Laid out text, ready to be drawn (or measured)
+type Text struct {
+	Lines  []Line
+	Width  float64
+	Height float64
+}
+
+func advance(face font.Face, s string) float64 {
+	return float64(font.MeasureString(face, s)) / 64
+}
+
+// Lays out txt (with markup) using face and opts, opts can be nil
+func Layout(txt string, face font.Face, opts *Options) *Text {
+	if opts == nil {
+		opts = &Options{}
+	}
+	clr := opts.Color
+	if clr == nil {
+		clr = color.White
+	}
+	spacing := opts.LineSpacing
+	if spacing == 0 {
+		spacing = 1
+	}
+
+	metrics := face.Metrics()
+	ascent := float64(metrics.Ascent.Ceil())
+	lineHeight := float64(metrics.Height.Ceil()) * spacing
+
+	faceFor := func(s style) font.Face {
+		if s.bold && opts.BoldFace != nil {
+			return opts.BoldFace
+		}
+		return face
+	}
+	colorFor := func(s style) color.Color {
+		if s.color != nil {
+			return s.color
+		}
+		return clr
+	}
+
+	result := &Text{}
+	var line []Item
+	x := 0.0
+
+	// paragraphEnd is true for the last line of a paragraph (not justified)
+	finishLine := func(paragraphEnd bool) {
+		// Trailing spaces don't count
+		for len(line) > 0 && line[len(line)-1].space {
+			line = line[:len(line)-1]
+		}
+		width := 0.0
+		if len(line) > 0 {
+			last := line[len(line)-1]
+			width = last.X + last.Width
+		}
+		alignLine(line, width, opts, paragraphEnd)
+		if opts.Align == TEXT_ALIGN_JUSTIFY && !paragraphEnd && opts.Width > 0 {
+			width = opts.Width
+		}
+		result.Lines = append(result.Lines, Line{
+			Items:    line,
+			Width:    width,
+			Baseline: ascent + float64(len(result.Lines))*lineHeight,
+		})
+		result.Width = math.Max(result.Width, width)
+		line = nil
+		x = 0
+	}
+
+	for _, tok := range tokenize(txt, style{}) {
+		switch tok.kind {
+		case tokenNewline:
+			finishLine(true)
+
+		case tokenSpace:
+			if len(line) == 0 {
+				continue // No leading spaces on wrapped lines
+			}
+			f := faceFor(tok.style)
+			w := advance(f, " ")
+			line = append(line, Item{Text: " ", Face: f, X: x, Width: w, space: true})
+			x += w
+
+		case tokenIcon:
+			icon := opts.Icons[tok.icon]
+			if icon == nil {
+				continue
+			}
+			iw, ih := float64(icon.Bounds().Dx()), float64(icon.Bounds().Dy())
+			w := iw * ascent / ih
+			if opts.Width > 0 && x+w > opts.Width && len(line) > 0 {
+				finishLine(false)
+			}
+			line = append(line, Item{Icon: icon, X: x, Width: w})
+			x += w
+
+		case tokenWord:
+			items := make([]Item, 0, len(tok.pieces))
+			w := 0.0
+			for _, p := range tok.pieces {
+				f := faceFor(p.style)
+				pw := advance(f, p.text)
+				items = append(items, Item{
+					Text:  p.text,
+					Face:  f,
+					Color: colorFor(p.style),
+					Bold:  p.style.bold && opts.BoldFace == nil,
+					Width: pw,
+				})
+				w += pw
+			}
+
+			if opts.Width > 0 && x+w > opts.Width && len(line) > 0 {
+				finishLine(false)
+			}
+
+			// Words longer than the wrap width are broken at characters
+			for _, item := range items {
+				if opts.Width > 0 && x+item.Width > opts.Width {
+					for _, r := range item.Text {
+						rw := advance(item.Face, string(r))
+						if x+rw > opts.Width && len(line) > 0 {
+							finishLine(false)
+						}
+						part := item
+						part.Text, part.X, part.Width = string(r), x, rw
+						line = appendItem(line, part)
+						x += rw
+					}
+					continue
+				}
+				item.X = x
+				line = appendItem(line, item)
+				x += item.Width
+			}
+		}
+	}
+	finishLine(true)
+
+	result.Height = float64(len(result.Lines)) * lineHeight
+	return result
+}
+
+// Merges consecutive characters of same style back into a single run
+func appendItem(line []Item, item Item) []Item {
+	if n := len(line); n > 0 {
+		last := &line[n-1]
+		if !last.space && last.Icon == nil && last.Face == item.Face && last.Color == item.Color &&
+			last.Bold == item.Bold && last.X+last.Width == item.X {
+			last.Text += item.Text
+			last.Width += item.Width
+			return line
+		}
+	}
+	return append(line, item)
+}
+
+func alignLine(line []Item, width float64, opts *Options, paragraphEnd bool) {
+	if opts.Width <= 0 {
+		return
+	}
+	extra := opts.Width - width
+	if extra <= 0 {
+		return
+	}
+
+	switch opts.Align {
+	case TEXT_ALIGN_CENTER:
+		shift(line, extra/2)
+	case TEXT_ALIGN_RIGHT:
+		shift(line, extra)
+	case TEXT_ALIGN_JUSTIFY:
+		if paragraphEnd {
+			return
+		}
+		spaces := 0
+		for _, item := range line {
+			if item.space {
+				spaces++
+			}
+		}
+		if spaces == 0 {
+			return
+		}
+		gap, offset := extra/float64(spaces), 0.0
+		for i := range line {
+			line[i].X += offset
+			if line[i].space {
+				offset += gap
+				line[i].Width += gap
+			}
+		}
+	}
+}
+
+func shift(line []Item, dx float64) {
+	for i := range line {
+		line[i].X += dx
+	}
+}
+
+// Width and Height of the laid out text
+func (t *Text) Size() (w, h float64) {
+	return t.Width, t.Height
+}
+
+// Draws laid out text with its TopLeft at x, y
+func (t *Text) Draw(target *ebiten.Image, x, y float64) {
+	t.DrawPartial(target, x, y, -1)
+}
+
+// Number of drawable characters (icons count as one), used with DrawPartial
+func (t *Text) Length() int {
+	n := 0
+	for _, line := range t.Lines {
+		for _, item := range line.Items {
+			if item.Icon != nil {
+				n++
+			} else if !item.space {
+				n += utf8.RuneCountInString(item.Text)
+			}
+		}
+	}
+	return n
+}
+
+// Draws only the first n characters (like a typewriter), n < 0 draws everything
+func (t *Text) DrawPartial(target *ebiten.Image, x, y float64, n int) {
+	for _, line := range t.Lines {
+		baseline := y + line.Baseline
+		for _, item := range line.Items {
+			if item.space {
+				continue
+			}
+			if n == 0 {
+				return
+			}
+			if item.Icon != nil {
+				ih := float64(item.Icon.Bounds().Dy())
+				scale := item.Width / float64(item.Icon.Bounds().Dx())
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Scale(scale, scale)
+				op.GeoM.Translate(x+item.X, baseline-ih*scale)
+				op.Filter = ebiten.FilterLinear
+				target.DrawImage(item.Icon, op)
+				n--
+				continue
+			}
+
+			txt := item.Text
+			if count := utf8.RuneCountInString(txt); n >= 0 && count > n {
+				txt = string([]rune(txt)[:n])
+				n = 0
+			} else if n > 0 {
+				n -= count
+			}
+
+			ix, iy := int(math.Round(x+item.X)), int(math.Round(baseline))
+			text.Draw(target, txt, item.Face, ix, iy, item.Color)
+			if item.Bold {
+				text.Draw(target, txt, item.Face, ix+1, iy, item.Color)
+			}
+		}
+	}
+}
+
+// Splits laid out text into pages of at most maxHeight (at least one line per page)
+func (t *Text) Paginate(maxHeight float64) []*Text {
+	if len(t.Lines) == 0 {
+		return []*Text{t}
+	}
+	lineHeight := t.Height / float64(len(t.Lines))
+	perPage := int(maxHeight / lineHeight)
+	if perPage < 1 {
+		perPage = 1
+	}
+
+	var pages []*Text
+	for start := 0; start < len(t.Lines); start += perPage {
+		end := start + perPage
+		if end > len(t.Lines) {
+			end = len(t.Lines)
+		}
+		page := &Text{Height: float64(end-start) * lineHeight}
+		top := float64(start) * lineHeight
+		for _, line := range t.Lines[start:end] {
+			line.Baseline -= top
+			page.Lines = append(page.Lines, line)
+			page.Width = math.Max(page.Width, line.Width)
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// Layout and Draw in one go, x, y is TopLeft (not baseline)
+func Draw(target *ebiten.Image, txt string, face font.Face, x, y float64, opts *Options) *Text {
+	t := Layout(txt, face, opts)
+	t.Draw(target, x, y)
+	return t
+}
+
+// Size of txt when laid out with face and opts
+func Measure(txt string, face font.Face, opts *Options) (w, h float64) {
+	return Layout(txt, face, opts).Size()
+}
@@ -0,0 +1,257 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+)
+
+// Loads a .tmx (XML) or .tmj/.json (JSON) map from disk, tilesets and images are resolved relative to it
+func Load(filePath string) (*Map, error) {
+	dir, name := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	return LoadFS(os.DirFS(dir), name)
+}
+
+// Same as Load but from any fs.FS (eg: embed.FS), name uses forward slashes
+func LoadFS(fsys fs.FS, name string) (*Map, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &loader{fsys: fsys, dir: path.Dir(name), images: map[string]*ebiten.Image{}}
+	var m *Map
+	switch strings.ToLower(path.Ext(name)) {
+	case ".tmx", ".xml":
+		m, err = l.parseTMX(data)
+	case ".tmj", ".json":
+		m, err = l.parseTMJ(data)
+	default:
+		return nil, fmt.Errorf("tiled: unknown map format %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tiled: %s: %w", name, err)
+	}
+	if m.Orientation != TILED_ORTHOGONAL && m.Orientation != TILED_ISOMETRIC {
+		return nil, fmt.Errorf("tiled: %s: %s maps are not supported yet", name, m.Orientation)
+	}
+	return m, nil
+}
+
+// Shared state while loading a map, images used by several tilesets are decoded once
+type loader struct {
+	fsys   fs.FS
+	dir    string
+	images map[string]*ebiten.Image
+}
+
+func (l *loader) resolve(dir, source string) string {
+	return path.Clean(path.Join(dir, source))
+}
+
+func (l *loader) loadImage(dir, source string) (*ebiten.Image, error) {
+	if source == "" {
+		return nil, nil
+	}
+	p := l.resolve(dir, source)
+	if img, ok := l.images[p]; ok {
+		return img, nil
+	}
+	f, err := l.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	decoded, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	img := ebiten.NewImageFromImage(decoded)
+	l.images[p] = img
+	return img, nil
+}
+
+// Loads an external tileset (.tsx or .tsj/.json)
+func (l *loader) loadTileset(source string, firstGID uint32) (*Tileset, error) {
+	p := l.resolve(l.dir, source)
+	data, err := fs.ReadFile(l.fsys, p)
+	if err != nil {
+		return nil, err
+	}
+	var ts *Tileset
+	if strings.ToLower(path.Ext(p)) == ".tsx" {
+		ts, err = l.parseTSX(data, path.Dir(p))
+	} else {
+		ts, err = l.parseTSJ(data, path.Dir(p))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	ts.FirstGID = firstGID
+	ts.Source = source
+	return ts, nil
+}
+
+// Fills Columns/TileCount if missing and sums animation durations
+func finishTileset(ts *Tileset) {
+	if ts.Image != nil && ts.TileWidth > 0 {
+		w := ts.Image.Bounds().Dx() - 2*ts.Margin + ts.Spacing
+		if ts.Columns <= 0 {
+			ts.Columns = w / (ts.TileWidth + ts.Spacing)
+		}
+		if ts.TileCount <= 0 && ts.TileHeight > 0 {
+			h := ts.Image.Bounds().Dy() - 2*ts.Margin + ts.Spacing
+			ts.TileCount = ts.Columns * (h / (ts.TileHeight + ts.Spacing))
+		}
+	}
+	for _, info := range ts.Tiles {
+		info.duration = 0
+		for _, f := range info.Animation {
+			info.duration += f.Duration
+		}
+	}
+}
+
+// Splits flags off a raw GID and finds its Tileset
+func (m *Map) cell(raw uint32) Cell {
+	gid := raw & GID_MASK
+	if gid == 0 {
+		return Cell{}
+	}
+	return Cell{
+		GID:     gid,
+		Tileset: m.GetTileset(gid),
+		FlipH:   raw&FLIPPED_HORIZONTALLY != 0,
+		FlipV:   raw&FLIPPED_VERTICALLY != 0,
+		FlipD:   raw&FLIPPED_DIAGONALLY != 0,
+	}
+}
+
+// Tiled's tile layer chunk (infinite maps have several)
+type chunk struct {
+	x, y, w, h int
+	gids       []uint32
+}
+
+// Merges chunks into the Layer's grid and resolves Cells
+func (m *Map) fillLayer(layer *Layer, chunks []chunk) {
+	if len(chunks) == 0 {
+		return
+	}
+	minX, minY := chunks[0].x, chunks[0].y
+	maxX, maxY := chunks[0].x+chunks[0].w, chunks[0].y+chunks[0].h
+	for _, c := range chunks[1:] {
+		minX, minY = minInt(minX, c.x), minInt(minY, c.y)
+		maxX, maxY = maxInt(maxX, c.x+c.w), maxInt(maxY, c.y+c.h)
+	}
+	layer.X, layer.Y = minX, minY
+	layer.Width, layer.Height = maxX-minX, maxY-minY
+	layer.Cells = make([]Cell, layer.Width*layer.Height)
+	for _, c := range chunks {
+		for i, raw := range c.gids {
+			if i >= c.w*c.h {
+				break
+			}
+			x, y := c.x+i%c.w-minX, c.y+i/c.w-minY
+			layer.Cells[y*layer.Width+x] = m.cell(raw)
+		}
+	}
+}
+
+// Decodes base64 layer data (optionally zlib/gzip compressed) into raw GIDs
+func decodeBase64(data, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "zlib":
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, err
+		}
+	case "gzip":
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+	if raw, err = ioutil.ReadAll(r); err != nil {
+		return nil, err
+	}
+
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return gids, nil
+}
+
+func decodeCSV(data string) ([]uint32, error) {
+	fields := strings.FieldsFunc(data, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t'
+	})
+	gids := make([]uint32, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		gids[i] = uint32(v)
+	}
+	return gids, nil
+}
+
+// Tiled colors are "#RRGGBB" or "#AARRGGBB", nil if empty or invalid
+func parseColor(s string) color.Color {
+	s = strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil
+	}
+	switch len(s) {
+	case 6:
+		return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+	case 8:
+		return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), uint8(v >> 24)}
+	}
+	return nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,190 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/math/f64"
+)
+
+// 3x2 layer: plain, flipped and empty cells from two tilesets
+var rawGIDs = []uint32{
+	1, 2 | FLIPPED_HORIZONTALLY, 11 | FLIPPED_VERTICALLY,
+	0, 3 | FLIPPED_DIAGONALLY | FLIPPED_HORIZONTALLY, 12,
+}
+
+func encodeGIDs(t *testing.T, gids []uint32, compression string) string {
+	t.Helper()
+	raw := make([]byte, len(gids)*4)
+	for i, gid := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+	var buf bytes.Buffer
+	switch compression {
+	case "":
+		buf.Write(raw)
+	case "zlib":
+		w := zlib.NewWriter(&buf)
+		w.Write(raw)
+		w.Close()
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		w.Write(raw)
+		w.Close()
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func csvGIDs(gids []uint32) string {
+	fields := make([]string, len(gids))
+	for i, gid := range gids {
+		fields[i] = fmt.Sprint(gid)
+	}
+	return strings.Join(fields[:3], ",") + ",\n" + strings.Join(fields[3:], ",")
+}
+
+func tmx(encoding, compression, data string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<map orientation="orthogonal" renderorder="right-down" width="3" height="2" tilewidth="16" tileheight="16" infinite="0" parallaxoriginx="10" parallaxoriginy="20">
+ <tileset firstgid="1" name="a" tilewidth="16" tileheight="16" tilecount="10" columns="5"/>
+ <tileset firstgid="11" name="b" tilewidth="16" tileheight="16" tilecount="10" columns="5"/>
+ <layer id="1" name="ground" width="3" height="2" parallaxx="0.5" tintcolor="#80ff0000">
+  <data encoding="` + encoding + `" compression="` + compression + `">` + data + `</data>
+ </layer>
+</map>`
+}
+
+func tmj(layerData string) string {
+	return `{
+ "orientation": "orthogonal", "renderorder": "right-down", "width": 3, "height": 2,
+ "tilewidth": 16, "tileheight": 16, "infinite": false,
+ "tilesets": [
+  {"firstgid": 1, "name": "a", "tilewidth": 16, "tileheight": 16, "tilecount": 10, "columns": 5},
+  {"firstgid": 11, "name": "b", "tilewidth": 16, "tileheight": 16, "tilecount": 10, "columns": 5}
+ ],
+ "layers": [
+  {"id": 1, "name": "ground", "type": "tilelayer", "width": 3, "height": 2, "x": 0, "y": 0, "visible": true, "opacity": 1, ` + layerData + `}
+ ]
+}`
+}
+
+func checkCells(t *testing.T, m *Map) {
+	t.Helper()
+	l := m.GetLayer("ground")
+	if l == nil {
+		t.Fatal("layer not found")
+	}
+	if l.Width != 3 || l.Height != 2 || len(l.Cells) != 6 {
+		t.Fatalf("layer is %dx%d with %d cells, want 3x2", l.Width, l.Height, len(l.Cells))
+	}
+	a, b := m.Tilesets[0], m.Tilesets[1]
+	want := []Cell{
+		{GID: 1, Tileset: a},
+		{GID: 2, Tileset: a, FlipH: true},
+		{GID: 11, Tileset: b, FlipV: true},
+		{},
+		{GID: 3, Tileset: a, FlipH: true, FlipD: true},
+		{GID: 12, Tileset: b},
+	}
+	for i, w := range want {
+		if got := l.GetCell(i%3, i/3); got != w {
+			t.Errorf("cell %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if id := l.GetCell(0, 1).GetTileID(); id != 0 {
+		t.Errorf("GetTileID() of an empty cell = %d", id)
+	}
+	if id := l.GetCell(2, 1).GetTileID(); id != 1 {
+		t.Errorf("GetTileID() = %d, want 1 (local to the second tileset)", id)
+	}
+}
+
+func TestLoadTileData(t *testing.T) {
+	tests := []struct {
+		name, file, data string
+	}{
+		{"tmx csv", "map.tmx", tmx("csv", "", csvGIDs(rawGIDs))},
+		{"tmx base64", "map.tmx", tmx("base64", "", encodeGIDs(t, rawGIDs, ""))},
+		{"tmx base64 zlib", "map.tmx", tmx("base64", "zlib", encodeGIDs(t, rawGIDs, "zlib"))},
+		{"tmx base64 gzip", "map.tmx", tmx("base64", "gzip", encodeGIDs(t, rawGIDs, "gzip"))},
+		{"tmj array", "map.tmj", tmj(`"data": [1, 2147483650, 1073741835, 0, 2684354563, 12]`)},
+		{"tmj base64 zlib", "map.tmj", tmj(`"encoding": "base64", "compression": "zlib", "data": "` + encodeGIDs(t, rawGIDs, "zlib") + `"`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := LoadFS(fstest.MapFS{tt.file: {Data: []byte(tt.data)}}, tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCells(t, m)
+		})
+	}
+}
+
+func TestLoadBadData(t *testing.T) {
+	tests := []struct {
+		name, data string
+	}{
+		{"bad csv", tmx("csv", "", "1,x,3")},
+		{"bad base64", tmx("base64", "", "not base64!")},
+		{"bad zlib", tmx("base64", "zlib", encodeGIDs(t, rawGIDs, ""))},
+		{"unknown compression", tmx("base64", "zstd", encodeGIDs(t, rawGIDs, ""))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadFS(fstest.MapFS{"map.tmx": {Data: []byte(tt.data)}}, "map.tmx"); err == nil {
+				t.Fatal("no error")
+			}
+		})
+	}
+}
+
+func TestParallaxAndTint(t *testing.T) {
+	m, err := LoadFS(fstest.MapFS{"map.tmx": {Data: []byte(tmx("csv", "", csvGIDs(rawGIDs)))}}, "map.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := m.GetLayer("ground")
+	if l.Parallax != (f64.Vec2{0.5, 1}) || m.ParallaxOrigin != (f64.Vec2{10, 20}) {
+		t.Fatalf("parallax %v origin %v, want (0.5, 1) (10, 20)", l.Parallax, m.ParallaxOrigin)
+	}
+	if r, g, b, a := l.Tint.RGBA(); r>>8 != 0x80 || g != 0 || b != 0 || a>>8 != 0x80 {
+		t.Fatalf("tint %v, want #80ff0000", l.Tint)
+	}
+
+	// Half speed on x, none on y, nothing at the origin
+	if o := m.GetParallaxOffset(l, 10, 20); o != (f64.Vec2{}) {
+		t.Fatalf("offset at origin %v, want 0", o)
+	}
+	if o := m.GetParallaxOffset(l, 110, 70); o != (f64.Vec2{50, 0}) {
+		t.Fatalf("offset %v, want (50, 0)", o)
+	}
+
+	// Groups multiply parallax and tint
+	group := &Layer{Type: TILED_LAYER_GROUP, Visible: true, Opacity: 0.5, Parallax: f64.Vec2{0.5, 0.5}, Tint: color.RGBA{0, 0, 255, 255}}
+	l.Parent = group
+	if p := l.GetParallax(); p != (f64.Vec2{0.25, 0.5}) {
+		t.Fatalf("GetParallax() = %v, want (0.25, 0.5)", p)
+	}
+	cs := l.GetColorScale()
+	if cs.R() != 0 || cs.G() != 0 || cs.B() != 0 {
+		t.Fatalf("red tint in a blue group = %v, want black", cs)
+	}
+	if a := cs.A(); a < 0.24 || a > 0.26 {
+		t.Fatalf("alpha %v, want 0.5 (tint) * 0.5 (group opacity)", a)
+	}
+
+	l.Parent, l.Tint = nil, nil
+	if cs := l.GetColorScale(); cs.R() != 1 || cs.A() != 1 {
+		t.Fatalf("GetColorScale() without tint = %v, want identity", cs)
+	}
+}
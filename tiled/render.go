@@ -0,0 +1,233 @@
+package tiled
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"golang.org/x/image/math/f64"
+)
+
+// Advances the clock of animated tiles by one tick (1/ebiten.TPS())
+func (m *Map) Update() error {
+	m.clock += 1000 / float64(ebiten.TPS())
+	return nil
+}
+
+// Draws every visible Layer through Screen, only tiles inside the Viewport are drawn
+func (m *Map) Draw(screen scr.Screen) {
+	m.EachLayer(func(l *Layer) {
+		if l.Type != TILED_LAYER_GROUP {
+			m.DrawLayer(screen, l)
+		}
+	})
+}
+
+// Draws a single Layer (tiles, tile objects or image), skipped if hidden
+func (m *Map) DrawLayer(screen scr.Screen, l *Layer) {
+	if !l.IsVisible() {
+		return
+	}
+	switch l.Type {
+	case TILED_LAYER_TILES:
+		m.drawTiles(screen, l)
+	case TILED_LAYER_OBJECTS:
+		m.drawTileObjects(screen, l)
+	case TILED_LAYER_IMAGE:
+		if l.Image != nil {
+			op := &ebiten.DrawImageOptions{}
+			offset := m.getDrawOffset(screen, l)
+			op.GeoM.Translate(offset[0], offset[1])
+			op.ColorScale = l.GetColorScale()
+			screen.DrawImage(l.Image, op)
+		}
+	}
+}
+
+/*
+Shift of a Layer for parallax scrolling when the view is centered at cx, cy (World)
+Layers with a Parallax below 1 scroll slower than the World, 0 keeps them fixed on screen
+*/
+func (m *Map) GetParallaxOffset(l *Layer, cx, cy float64) f64.Vec2 {
+	f := l.GetParallax()
+	return f64.Vec2{(cx - m.ParallaxOrigin[0]) * (1 - f[0]), (cy - m.ParallaxOrigin[1]) * (1 - f[1])}
+}
+
+// Offset of a Layer plus its parallax shift for the Screen's Viewport
+func (m *Map) getDrawOffset(screen scr.Screen, l *Layer) f64.Vec2 {
+	offset := l.GetOffset()
+	if v := screen.GetViewport(); v != nil {
+		cx, cy := v.GetCenter()
+		shift := m.GetParallaxOffset(l, cx, cy)
+		offset[0] += shift[0]
+		offset[1] += shift[1]
+	}
+	return offset
+}
+
+// Visible area in tiles, the whole layer if there is no Viewport
+func (m *Map) visibleTiles(screen scr.Screen, l *Layer) (x1, y1, x2, y2 int) {
+	x1, y1, x2, y2 = l.X, l.Y, l.X+l.Width-1, l.Y+l.Height-1
+	v := screen.GetViewport()
+	if v == nil || m.Orientation != TILED_ORTHOGONAL {
+		return
+	}
+
+	vx, vy, vw, vh := v.GetVisibleRect()
+	offset := m.getDrawOffset(screen, l)
+	vx, vy = vx-offset[0], vy-offset[1]
+
+	// Tiles bigger than the grid (and offsets) can poke into view from neighbouring cells
+	maxW, maxH := m.TileWidth, m.TileHeight
+	for _, ts := range m.Tilesets {
+		maxW = maxInt(maxW, ts.TileWidth+int(math.Abs(ts.TileOffset[0])))
+		maxH = maxInt(maxH, ts.TileHeight+int(math.Abs(ts.TileOffset[1])))
+	}
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+	x1 = maxInt(x1, floor((vx-float64(maxW-m.TileWidth))/tw))
+	y1 = maxInt(y1, floor(vy/th))
+	x2 = minInt(x2, floor((vx+vw)/tw))
+	y2 = minInt(y2, floor((vy+vh+float64(maxH-m.TileHeight))/th))
+	return
+}
+
+func (m *Map) drawTiles(screen scr.Screen, l *Layer) {
+	offset := m.getDrawOffset(screen, l)
+	colors := l.GetColorScale()
+	op := &ebiten.DrawImageOptions{}
+
+	x1, y1, x2, y2 := m.visibleTiles(screen, l)
+	var vx, vy, vw, vh float64
+	cullIso := false
+	if v := screen.GetViewport(); v != nil && m.Orientation == TILED_ISOMETRIC {
+		vx, vy, vw, vh = v.GetVisibleRect()
+		cullIso = true
+	}
+
+	for ty := y1; ty <= y2; ty++ {
+		for tx := x1; tx <= x2; tx++ {
+			c := l.GetCell(tx, ty)
+			if c.IsEmpty() || c.Tileset == nil {
+				continue
+			}
+			x, y := m.TileToWorld(tx, ty)
+			x, y = x+offset[0], y+offset[1]
+			ts := c.Tileset
+
+			// Tiles are aligned to the bottom of their cell
+			y += float64(m.TileHeight - ts.TileHeight)
+			if cullIso && (x+float64(ts.TileWidth) < vx || y+float64(ts.TileHeight) < vy || x > vx+vw || y > vy+vh) {
+				continue
+			}
+
+			op.GeoM.Reset()
+			op.ColorScale = colors
+			m.drawCell(screen, c, x, y, op)
+		}
+	}
+}
+
+// Tile objects are positioned by their BottomLeft corner and can be scaled and rotated
+func (m *Map) drawTileObjects(screen scr.Screen, l *Layer) {
+	offset := m.getDrawOffset(screen, l)
+	colors := l.GetColorScale()
+	for _, o := range l.Objects {
+		if !o.Visible || !o.IsTile() || o.Cell.Tileset == nil {
+			continue
+		}
+		img := o.Cell.Tileset.GetTileImage(o.Cell.Tileset.animatedID(o.Cell.GetTileID(), m.clock))
+		if img == nil {
+			continue
+		}
+		w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+		op := &ebiten.DrawImageOptions{}
+		applyFlips(&op.GeoM, o.Cell, float64(w), float64(h))
+		if o.Width > 0 && o.Height > 0 {
+			op.GeoM.Scale(o.Width/float64(w), o.Height/float64(h))
+		}
+		op.GeoM.Translate(0, -o.Height)
+		op.GeoM.Rotate(o.Rotation * math.Pi / 180)
+		op.GeoM.Translate(o.X+offset[0], o.Y+offset[1])
+		op.ColorScale = colors
+		screen.DrawImage(img, op)
+	}
+}
+
+// Draws a Cell with its TopLeft at x, y (World), op may already hold colors
+func (m *Map) drawCell(screen scr.Screen, c Cell, x, y float64, op *ebiten.DrawImageOptions) {
+	ts := c.Tileset
+	img := ts.GetTileImage(ts.animatedID(c.GetTileID(), m.clock))
+	if img == nil {
+		return
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	applyFlips(&op.GeoM, c, float64(w), float64(h))
+	op.GeoM.Translate(x+ts.TileOffset[0], y+ts.TileOffset[1])
+	screen.DrawImage(img, op)
+}
+
+// Tiled applies the diagonal flip first, then horizontal, then vertical
+func applyFlips(g *ebiten.GeoM, c Cell, w, h float64) {
+	if c.FlipD {
+		swap := ebiten.GeoM{}
+		swap.SetElement(0, 0, 0)
+		swap.SetElement(0, 1, 1)
+		swap.SetElement(1, 0, 1)
+		swap.SetElement(1, 1, 0)
+		g.Concat(swap)
+		w, h = h, w
+	}
+	if c.FlipH {
+		g.Scale(-1, 1)
+		g.Translate(w, 0)
+	}
+	if c.FlipV {
+		g.Scale(1, -1)
+		g.Translate(0, h)
+	}
+}
+
+/***************** CHUNKED RENDERING *********************/
+
+// LayerSource adapts a tile Layer for tilemap.Renderer (orthogonal maps only)
+type LayerSource struct {
+	Map   *Map
+	Layer *Layer
+}
+
+// Source for a cached, chunked tilemap.Renderer, set Renderer.Offset to Layer.GetOffset() (plus GetParallaxOffset every frame for parallax layers)
+func (m *Map) GetLayerSource(l *Layer) *LayerSource {
+	return &LayerSource{Map: m, Layer: l}
+}
+
+func (s *LayerSource) GetBounds() (x, y, width, height int) {
+	return s.Layer.X, s.Layer.Y, s.Layer.Width, s.Layer.Height
+}
+
+func (s *LayerSource) GetTileSize() (width, height int) {
+	return s.Map.TileWidth, s.Map.TileHeight
+}
+
+func (s *LayerSource) GetTile(tx, ty int, op *ebiten.DrawImageOptions) *ebiten.Image {
+	c := s.Layer.GetCell(tx, ty)
+	if c.IsEmpty() || c.Tileset == nil {
+		return nil
+	}
+	ts := c.Tileset
+	img := ts.GetTileImage(ts.animatedID(c.GetTileID(), s.Map.clock))
+	if img == nil {
+		return nil
+	}
+	applyFlips(&op.GeoM, c, float64(img.Bounds().Dx()), float64(img.Bounds().Dy()))
+	op.GeoM.Translate(ts.TileOffset[0], ts.TileOffset[1]+float64(s.Map.TileHeight-ts.TileHeight))
+	colors := s.Layer.GetColorScale()
+	op.ColorScale.ScaleWithColorScale(colors)
+	return img
+}
+
+func (s *LayerSource) IsAnimated(tx, ty int) bool {
+	info := s.Layer.GetCell(tx, ty).GetTileInfo()
+	return info != nil && len(info.Animation) > 0
+}
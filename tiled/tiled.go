@@ -0,0 +1,394 @@
+package tiled
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	vpt "github.com/shubhamdwivedii/gopher-engine/scene/viewport"
+	"golang.org/x/image/math/f64"
+)
+
+// Flags stored in the top bits of a Global Tile ID
+const (
+	FLIPPED_HORIZONTALLY = 0x80000000
+	FLIPPED_VERTICALLY   = 0x40000000
+	FLIPPED_DIAGONALLY   = 0x20000000
+	ROTATED_HEXAGONAL    = 0x10000000
+	GID_MASK             = 0x0fffffff
+)
+
+// Custom properties, values are kept as strings (bools are "true"/"false", colors are "#AARRGGBB")
+type Properties map[string]string
+
+func (p Properties) GetString(name, fallback string) string {
+	if v, ok := p[name]; ok {
+		return v
+	}
+	return fallback
+}
+
+func (p Properties) GetInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(p[name]); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func (p Properties) GetFloat(name string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(p[name], 64); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func (p Properties) GetBool(name string, fallback bool) bool {
+	if v, err := strconv.ParseBool(p[name]); err == nil {
+		return v
+	}
+	return fallback
+}
+
+/***************** MAP *********************/
+
+type Map struct {
+	Orientation     string // TILED_ORTHOGONAL, TILED_ISOMETRIC (TILED_STAGGERED and TILED_HEXAGONAL aren't supported yet)
+	RenderOrder     string
+	Width, Height   int // In tiles
+	TileWidth       int
+	TileHeight      int
+	Infinite        bool
+	BackgroundColor color.Color // nil if not set
+	ParallaxOrigin  f64.Vec2    // Parallax layers are at their Offset when the view is centered here
+	Tilesets        []*Tileset
+	Layers          []*Layer // In draw order, Groups contain their own Layers
+	Properties      Properties
+
+	clock float64 // Milliseconds, drives animated tiles
+}
+
+// Size of the map in World pixels
+func (m *Map) GetPixelSize() (width, height float64) {
+	if m.Orientation == TILED_ISOMETRIC {
+		return float64(m.Width+m.Height) * float64(m.TileWidth) / 2, float64(m.Width+m.Height) * float64(m.TileHeight) / 2
+	}
+	return float64(m.Width * m.TileWidth), float64(m.Height * m.TileHeight)
+}
+
+// Sets Viewport's World size (and Camera bounds) to the map's size
+func (m *Map) ApplyToViewport(v *vpt.Viewport) {
+	v.SetWorldSize(m.GetPixelSize())
+}
+
+// TopLeft of the tile's cell in World pixels
+func (m *Map) TileToWorld(tx, ty int) (x, y float64) {
+	if m.Orientation == TILED_ISOMETRIC {
+		originX := float64(m.Height*m.TileWidth) / 2
+		return originX + float64(tx-ty-1)*float64(m.TileWidth)/2, float64(tx+ty) * float64(m.TileHeight) / 2
+	}
+	return float64(tx * m.TileWidth), float64(ty * m.TileHeight)
+}
+
+// Tile coordinates of a World point (may be outside the map)
+func (m *Map) WorldToTile(x, y float64) (tx, ty int) {
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+	if m.Orientation == TILED_ISOMETRIC {
+		x -= float64(m.Height) * tw / 2
+		fx, fy := x/tw+y/th, y/th-x/tw
+		return floor(fx), floor(fy)
+	}
+	return floor(x / tw), floor(y / th)
+}
+
+// Tileset that a (masked) Global Tile ID belongs to
+func (m *Map) GetTileset(gid uint32) *Tileset {
+	var found *Tileset
+	for _, ts := range m.Tilesets {
+		if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	return found
+}
+
+// First Layer (searching inside Groups too) with the given name, nil if none
+func (m *Map) GetLayer(name string) *Layer {
+	return findLayer(m.Layers, name)
+}
+
+func findLayer(layers []*Layer, name string) *Layer {
+	for _, l := range layers {
+		if l.Name == name {
+			return l
+		}
+		if found := findLayer(l.Layers, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// All Objects (from every object Layer) with the given name
+func (m *Map) FindObjects(name string) []*Object {
+	var result []*Object
+	m.EachLayer(func(l *Layer) {
+		for _, o := range l.Objects {
+			if o.Name == name {
+				result = append(result, o)
+			}
+		}
+	})
+	return result
+}
+
+// Calls fn for every Layer, depth first (Groups before their children)
+func (m *Map) EachLayer(fn func(l *Layer)) {
+	var walk func(layers []*Layer)
+	walk = func(layers []*Layer) {
+		for _, l := range layers {
+			fn(l)
+			walk(l.Layers)
+		}
+	}
+	walk(m.Layers)
+}
+
+/***************** TILESET *********************/
+
+type Tileset struct {
+	FirstGID    uint32
+	Name        string
+	Source      string // External tileset file (.tsx/.tsj), empty if embedded
+	TileWidth   int
+	TileHeight  int
+	Spacing     int
+	Margin      int
+	TileCount   int
+	Columns     int
+	TileOffset  f64.Vec2 // Drawing offset in pixels
+	Image       *ebiten.Image
+	ImageSource string
+	Tiles       map[uint32]*TileInfo // Tiles with extra data, by local ID
+	Properties  Properties
+
+	subImages map[uint32]*ebiten.Image
+}
+
+// Extra data of a single tile in a Tileset
+type TileInfo struct {
+	ID          uint32 // Local ID
+	Type        string // "class" in newer Tiled versions
+	Properties  Properties
+	Animation   []Frame
+	Image       *ebiten.Image // Image collection tilesets have an image per tile
+	ImageSource string
+	Objects     []*Object // Collision shapes, relative to the tile
+
+	duration float64 // Sum of Animation durations
+}
+
+type Frame struct {
+	TileID   uint32  // Local ID
+	Duration float64 // Milliseconds
+}
+
+// Image of a tile by local ID, sub-images are cached
+func (ts *Tileset) GetTileImage(id uint32) *ebiten.Image {
+	if info, ok := ts.Tiles[id]; ok && info.Image != nil {
+		return info.Image
+	}
+	if ts.Image == nil || ts.Columns <= 0 {
+		return nil
+	}
+	if img, ok := ts.subImages[id]; ok {
+		return img
+	}
+	col, row := int(id)%ts.Columns, int(id)/ts.Columns
+	x := ts.Margin + col*(ts.TileWidth+ts.Spacing)
+	y := ts.Margin + row*(ts.TileHeight+ts.Spacing)
+	img := ts.Image.SubImage(image.Rect(x, y, x+ts.TileWidth, y+ts.TileHeight)).(*ebiten.Image)
+	if ts.subImages == nil {
+		ts.subImages = map[uint32]*ebiten.Image{}
+	}
+	ts.subImages[id] = img
+	return img
+}
+
+// Local ID of the frame that should be visible at clock (milliseconds), id itself if not animated
+func (ts *Tileset) animatedID(id uint32, clock float64) uint32 {
+	info, ok := ts.Tiles[id]
+	if !ok || len(info.Animation) == 0 || info.duration <= 0 {
+		return id
+	}
+	t := clock - float64(int(clock/info.duration))*info.duration
+	for _, f := range info.Animation {
+		if t < f.Duration {
+			return f.TileID
+		}
+		t -= f.Duration
+	}
+	return info.Animation[len(info.Animation)-1].TileID
+}
+
+/***************** LAYERS *********************/
+
+// A cell of a tile Layer, GID 0 means empty
+type Cell struct {
+	GID     uint32 // Global Tile ID without flags
+	Tileset *Tileset
+	FlipH   bool
+	FlipV   bool
+	FlipD   bool // Diagonal flip (x/y swapped), used for 90 degree rotations
+}
+
+func (c Cell) IsEmpty() bool {
+	return c.GID == 0
+}
+
+// Local ID in its Tileset
+func (c Cell) GetTileID() uint32 {
+	if c.Tileset == nil {
+		return 0
+	}
+	return c.GID - c.Tileset.FirstGID
+}
+
+func (c Cell) GetTileInfo() *TileInfo {
+	if c.Tileset == nil {
+		return nil
+	}
+	return c.Tileset.Tiles[c.GetTileID()]
+}
+
+/*
+Layer is any Tiled layer, Type is one of TILED_LAYER_*
+Tile Layers use Cells, Object Layers use Objects, Image Layers use Image, Groups use Layers
+*/
+type Layer struct {
+	ID         int
+	Name       string
+	Type       string
+	Class      string
+	Visible    bool
+	Opacity    float64
+	Offset     f64.Vec2    // In pixels
+	Parallax   f64.Vec2    // 1, 1 means scrolling with the World
+	Tint       color.Color // nil means no tint
+	Properties Properties
+
+	// Tile Layers, infinite maps are merged into one grid starting at X, Y (in tiles)
+	X, Y          int
+	Width, Height int
+	Cells         []Cell
+
+	Objects []*Object // Object Layers
+
+	Image       *ebiten.Image // Image Layers
+	ImageSource string
+
+	Layers []*Layer // Groups
+	Parent *Layer
+}
+
+// Cell at tile coordinates (map space), empty Cell if out of range
+func (l *Layer) GetCell(tx, ty int) Cell {
+	x, y := tx-l.X, ty-l.Y
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return Cell{}
+	}
+	return l.Cells[y*l.Width+x]
+}
+
+// Replaces a Cell (eg: breakable blocks), out of range is ignored
+func (l *Layer) SetCell(tx, ty int, c Cell) {
+	x, y := tx-l.X, ty-l.Y
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return
+	}
+	l.Cells[y*l.Width+x] = c
+}
+
+// Visible and all its parent Groups are visible
+func (l *Layer) IsVisible() bool {
+	for p := l; p != nil; p = p.Parent {
+		if !p.Visible {
+			return false
+		}
+	}
+	return true
+}
+
+// Opacity multiplied by parent Groups' opacity
+func (l *Layer) GetOpacity() float64 {
+	o := 1.0
+	for p := l; p != nil; p = p.Parent {
+		o *= p.Opacity
+	}
+	return o
+}
+
+// Offset added to parent Groups' offsets
+func (l *Layer) GetOffset() f64.Vec2 {
+	var o f64.Vec2
+	for p := l; p != nil; p = p.Parent {
+		o[0] += p.Offset[0]
+		o[1] += p.Offset[1]
+	}
+	return o
+}
+
+// Parallax multiplied by parent Groups' parallax
+func (l *Layer) GetParallax() f64.Vec2 {
+	f := f64.Vec2{1, 1}
+	for p := l; p != nil; p = p.Parent {
+		f[0] *= p.Parallax[0]
+		f[1] *= p.Parallax[1]
+	}
+	return f
+}
+
+// Tint and Opacity of the Layer and its parent Groups, to scale the colors of its tiles with
+func (l *Layer) GetColorScale() ebiten.ColorScale {
+	var cs ebiten.ColorScale
+	for p := l; p != nil; p = p.Parent {
+		if p.Tint != nil {
+			cs.ScaleWithColor(p.Tint)
+		}
+	}
+	cs.ScaleAlpha(float32(l.GetOpacity()))
+	return cs
+}
+
+/***************** OBJECTS *********************/
+
+type Object struct {
+	ID         int
+	Name       string
+	Type       string // "class" in newer Tiled versions
+	X, Y       float64
+	Width      float64
+	Height     float64
+	Rotation   float64 // Degrees clockwise
+	Visible    bool
+	Cell       Cell // Tile objects have a GID (their X, Y is BottomLeft)
+	Ellipse    bool
+	Point      bool
+	Polygon    []f64.Vec2 // Relative to X, Y
+	Polyline   []f64.Vec2
+	Text       string
+	Properties Properties
+}
+
+func (o *Object) IsTile() bool {
+	return !o.Cell.IsEmpty()
+}
+
+func floor(v float64) int {
+	i := int(v)
+	if v < 0 && float64(i) != v {
+		i--
+	}
+	return i
+}
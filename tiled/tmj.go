@@ -0,0 +1,355 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/math/f64"
+)
+
+/***************** TMJ (JSON) FORMAT *********************/
+
+type jsonProperty struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+type jsonProperties []jsonProperty
+
+func (p jsonProperties) convert() Properties {
+	props := Properties{}
+	for _, prop := range p {
+		switch v := prop.Value.(type) {
+		case string:
+			props[prop.Name] = v
+		case nil:
+			props[prop.Name] = ""
+		default:
+			props[prop.Name] = fmt.Sprint(v)
+		}
+	}
+	return props
+}
+
+type jsonTile struct {
+	ID         uint32         `json:"id"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	Properties jsonProperties `json:"properties"`
+	Image      string         `json:"image"`
+	Animation  []struct {
+		TileID   uint32  `json:"tileid"`
+		Duration float64 `json:"duration"`
+	} `json:"animation"`
+	ObjectGroup *jsonLayer `json:"objectgroup"`
+}
+
+type jsonTileset struct {
+	FirstGID   uint32 `json:"firstgid"`
+	Source     string `json:"source"`
+	Name       string `json:"name"`
+	TileWidth  int    `json:"tilewidth"`
+	TileHeight int    `json:"tileheight"`
+	Spacing    int    `json:"spacing"`
+	Margin     int    `json:"margin"`
+	TileCount  int    `json:"tilecount"`
+	Columns    int    `json:"columns"`
+	Image      string `json:"image"`
+	TileOffset *struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	} `json:"tileoffset"`
+	Tiles      []jsonTile     `json:"tiles"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"` // Array of GIDs, or a base64 string
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Rotation   float64        `json:"rotation"`
+	GID        uint32         `json:"gid"`
+	Visible    *bool          `json:"visible"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Polygon    []jsonPoint    `json:"polygon"`
+	Polyline   []jsonPoint    `json:"polyline"`
+	Text       *jsonText      `json:"text"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonText struct {
+	Text string `json:"text"`
+}
+
+type jsonPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type jsonLayer struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Class       string         `json:"class"`
+	Visible     *bool          `json:"visible"`
+	Opacity     *float64       `json:"opacity"`
+	OffsetX     float64        `json:"offsetx"`
+	OffsetY     float64        `json:"offsety"`
+	ParallaxX   *float64       `json:"parallaxx"`
+	ParallaxY   *float64       `json:"parallaxy"`
+	TintColor   string         `json:"tintcolor"`
+	Encoding    string         `json:"encoding"`
+	Compression string         `json:"compression"`
+	Properties  jsonProperties `json:"properties"`
+	jsonChunk
+	Chunks  []jsonChunk  `json:"chunks"`
+	Objects []jsonObject `json:"objects"`
+	Image   string       `json:"image"`
+	Layers  []jsonLayer  `json:"layers"`
+}
+
+type jsonMap struct {
+	Orientation     string         `json:"orientation"`
+	RenderOrder     string         `json:"renderorder"`
+	Width           int            `json:"width"`
+	Height          int            `json:"height"`
+	TileWidth       int            `json:"tilewidth"`
+	TileHeight      int            `json:"tileheight"`
+	Infinite        bool           `json:"infinite"`
+	BackgroundColor string         `json:"backgroundcolor"`
+	ParallaxOriginX float64        `json:"parallaxoriginx"`
+	ParallaxOriginY float64        `json:"parallaxoriginy"`
+	Properties      jsonProperties `json:"properties"`
+	Tilesets        []jsonTileset  `json:"tilesets"`
+	Layers          []jsonLayer    `json:"layers"`
+}
+
+func (l *loader) parseTMJ(data []byte) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+
+	m := &Map{
+		Orientation:     jm.Orientation,
+		RenderOrder:     jm.RenderOrder,
+		Width:           jm.Width,
+		Height:          jm.Height,
+		TileWidth:       jm.TileWidth,
+		TileHeight:      jm.TileHeight,
+		Infinite:        jm.Infinite,
+		BackgroundColor: parseColor(jm.BackgroundColor),
+		ParallaxOrigin:  f64.Vec2{jm.ParallaxOriginX, jm.ParallaxOriginY},
+		Properties:      jm.Properties.convert(),
+	}
+
+	for i := range jm.Tilesets {
+		jt := &jm.Tilesets[i]
+		var ts *Tileset
+		var err error
+		if jt.Source != "" {
+			ts, err = l.loadTileset(jt.Source, jt.FirstGID)
+		} else {
+			ts, err = l.convertJSONTileset(jt, l.dir)
+			if ts != nil {
+				ts.FirstGID = jt.FirstGID
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	layers, err := l.convertJSONLayers(m, jm.Layers, nil)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	return m, nil
+}
+
+// External .tsj/.json tileset, dir is the directory of the file (images are relative to it)
+func (l *loader) parseTSJ(data []byte, dir string) (*Tileset, error) {
+	var jt jsonTileset
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return nil, err
+	}
+	return l.convertJSONTileset(&jt, dir)
+}
+
+func (l *loader) convertJSONTileset(jt *jsonTileset, dir string) (*Tileset, error) {
+	ts := &Tileset{
+		Name:        jt.Name,
+		TileWidth:   jt.TileWidth,
+		TileHeight:  jt.TileHeight,
+		Spacing:     jt.Spacing,
+		Margin:      jt.Margin,
+		TileCount:   jt.TileCount,
+		Columns:     jt.Columns,
+		ImageSource: jt.Image,
+		Tiles:       map[uint32]*TileInfo{},
+		Properties:  jt.Properties.convert(),
+	}
+	if jt.TileOffset != nil {
+		ts.TileOffset = f64.Vec2{jt.TileOffset.X, jt.TileOffset.Y}
+	}
+
+	var err error
+	if ts.Image, err = l.loadImage(dir, jt.Image); err != nil {
+		return nil, err
+	}
+
+	for i := range jt.Tiles {
+		jtile := &jt.Tiles[i]
+		info := &TileInfo{
+			ID:          jtile.ID,
+			Type:        firstNonEmpty(jtile.Class, jtile.Type),
+			Properties:  jtile.Properties.convert(),
+			ImageSource: jtile.Image,
+		}
+		for _, f := range jtile.Animation {
+			info.Animation = append(info.Animation, Frame{TileID: f.TileID, Duration: f.Duration})
+		}
+		if info.Image, err = l.loadImage(dir, jtile.Image); err != nil {
+			return nil, err
+		}
+		if jtile.ObjectGroup != nil {
+			for j := range jtile.ObjectGroup.Objects {
+				info.Objects = append(info.Objects, convertJSONObject(nil, &jtile.ObjectGroup.Objects[j]))
+			}
+		}
+		ts.Tiles[info.ID] = info
+	}
+
+	finishTileset(ts)
+	return ts, nil
+}
+
+func (l *loader) convertJSONLayers(m *Map, jls []jsonLayer, parent *Layer) ([]*Layer, error) {
+	var layers []*Layer
+	for i := range jls {
+		jl := &jls[i]
+		layer := &Layer{
+			ID:         jl.ID,
+			Name:       jl.Name,
+			Type:       jl.Type,
+			Class:      jl.Class,
+			Visible:    jl.Visible == nil || *jl.Visible,
+			Opacity:    optional(jl.Opacity, 1),
+			Offset:     f64.Vec2{jl.OffsetX, jl.OffsetY},
+			Parallax:   f64.Vec2{optional(jl.ParallaxX, 1), optional(jl.ParallaxY, 1)},
+			Tint:       parseColor(jl.TintColor),
+			Properties: jl.Properties.convert(),
+			Parent:     parent,
+		}
+
+		switch jl.Type {
+		case TILED_LAYER_TILES:
+			chunks := jl.Chunks
+			if len(chunks) == 0 {
+				chunks = []jsonChunk{{0, 0, jl.Width, jl.Height, jl.Data}}
+			}
+			var decoded []chunk
+			for _, c := range chunks {
+				gids, err := decodeJSONData(c.Data, jl.Encoding, jl.Compression)
+				if err != nil {
+					return nil, fmt.Errorf("layer %q: %w", jl.Name, err)
+				}
+				decoded = append(decoded, chunk{c.X, c.Y, c.Width, c.Height, gids})
+			}
+			m.fillLayer(layer, decoded)
+		case TILED_LAYER_OBJECTS:
+			for j := range jl.Objects {
+				layer.Objects = append(layer.Objects, convertJSONObject(m, &jl.Objects[j]))
+			}
+		case TILED_LAYER_IMAGE:
+			layer.ImageSource = jl.Image
+			img, err := l.loadImage(l.dir, jl.Image)
+			if err != nil {
+				return nil, err
+			}
+			layer.Image = img
+		case TILED_LAYER_GROUP:
+			children, err := l.convertJSONLayers(m, jl.Layers, layer)
+			if err != nil {
+				return nil, err
+			}
+			layer.Layers = children
+		default:
+			continue
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+func decodeJSONData(data json.RawMessage, encoding, compression string) ([]uint32, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if encoding == "base64" || strings.HasPrefix(strings.TrimSpace(string(data)), "\"") {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return decodeBase64(s, compression)
+	}
+	var gids []uint32
+	err := json.Unmarshal(data, &gids)
+	return gids, err
+}
+
+// m can be nil when tile objects aren't possible
+func convertJSONObject(m *Map, jo *jsonObject) *Object {
+	o := &Object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Type:       firstNonEmpty(jo.Class, jo.Type),
+		X:          jo.X,
+		Y:          jo.Y,
+		Width:      jo.Width,
+		Height:     jo.Height,
+		Rotation:   jo.Rotation,
+		Visible:    jo.Visible == nil || *jo.Visible,
+		Ellipse:    jo.Ellipse,
+		Point:      jo.Point,
+		Properties: jo.Properties.convert(),
+	}
+	if m != nil && jo.GID != 0 {
+		o.Cell = m.cell(jo.GID)
+	}
+	for _, p := range jo.Polygon {
+		o.Polygon = append(o.Polygon, f64.Vec2{p.X, p.Y})
+	}
+	for _, p := range jo.Polyline {
+		o.Polyline = append(o.Polyline, f64.Vec2{p.X, p.Y})
+	}
+	if jo.Text != nil {
+		o.Text = jo.Text.Text
+	}
+	return o
+}
+
+func optional(v *float64, fallback float64) float64 {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
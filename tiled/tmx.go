@@ -0,0 +1,418 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	. "github.com/shubhamdwivedii/gopher-engine/constants"
+	"golang.org/x/image/math/f64"
+)
+
+/***************** TMX (XML) FORMAT *********************/
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"` // Multi-line strings are stored as text
+}
+
+type xmlProperties struct {
+	Properties []xmlProperty `xml:"property"`
+}
+
+func (p *xmlProperties) convert() Properties {
+	props := Properties{}
+	if p == nil {
+		return props
+	}
+	for _, prop := range p.Properties {
+		if prop.Value == "" {
+			props[prop.Name] = prop.Text
+		} else {
+			props[prop.Name] = prop.Value
+		}
+	}
+	return props
+}
+
+type xmlImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type xmlFrame struct {
+	TileID   uint32  `xml:"tileid,attr"`
+	Duration float64 `xml:"duration,attr"`
+}
+
+type xmlTile struct {
+	ID          uint32         `xml:"id,attr"`
+	Type        string         `xml:"type,attr"`
+	Class       string         `xml:"class,attr"`
+	Properties  *xmlProperties `xml:"properties"`
+	Image       *xmlImage      `xml:"image"`
+	Animation   []xmlFrame     `xml:"animation>frame"`
+	ObjectGroup *xmlLayer      `xml:"objectgroup"`
+}
+
+type xmlTileset struct {
+	FirstGID   uint32 `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Columns    int    `xml:"columns,attr"`
+	TileOffset *struct {
+		X float64 `xml:"x,attr"`
+		Y float64 `xml:"y,attr"`
+	} `xml:"tileoffset"`
+	Image      *xmlImage      `xml:"image"`
+	Tiles      []xmlTile      `xml:"tile"`
+	Properties *xmlProperties `xml:"properties"`
+}
+
+type xmlChunk struct {
+	X      int    `xml:"x,attr"`
+	Y      int    `xml:"y,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Text   string `xml:",chardata"`
+	Tiles  []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type xmlData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	xmlChunk
+	Chunks []xmlChunk `xml:"chunk"`
+}
+
+type xmlPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+type xmlObject struct {
+	ID         int            `xml:"id,attr"`
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr"`
+	Class      string         `xml:"class,attr"`
+	X          float64        `xml:"x,attr"`
+	Y          float64        `xml:"y,attr"`
+	Width      float64        `xml:"width,attr"`
+	Height     float64        `xml:"height,attr"`
+	Rotation   float64        `xml:"rotation,attr"`
+	GID        uint32         `xml:"gid,attr"`
+	Visible    string         `xml:"visible,attr"`
+	Ellipse    *struct{}      `xml:"ellipse"`
+	Point      *struct{}      `xml:"point"`
+	Polygon    *xmlPoints     `xml:"polygon"`
+	Polyline   *xmlPoints     `xml:"polyline"`
+	Text       *xmlChunk      `xml:"text"`
+	Properties *xmlProperties `xml:"properties"`
+}
+
+// Any layer element (layer, objectgroup, imagelayer, group), told apart by XMLName
+type xmlLayer struct {
+	XMLName    xml.Name
+	ID         int            `xml:"id,attr"`
+	Name       string         `xml:"name,attr"`
+	Class      string         `xml:"class,attr"`
+	Visible    string         `xml:"visible,attr"`
+	Opacity    string         `xml:"opacity,attr"`
+	OffsetX    float64        `xml:"offsetx,attr"`
+	OffsetY    float64        `xml:"offsety,attr"`
+	ParallaxX  string         `xml:"parallaxx,attr"`
+	ParallaxY  string         `xml:"parallaxy,attr"`
+	TintColor  string         `xml:"tintcolor,attr"`
+	Width      int            `xml:"width,attr"`
+	Height     int            `xml:"height,attr"`
+	Properties *xmlProperties `xml:"properties"`
+	Data       *xmlData       `xml:"data"`
+	Objects    []xmlObject    `xml:"object"`
+	Image      *xmlImage      `xml:"image"`
+	Layers     []xmlLayer     `xml:",any"`
+}
+
+type xmlMap struct {
+	Orientation     string         `xml:"orientation,attr"`
+	RenderOrder     string         `xml:"renderorder,attr"`
+	Width           int            `xml:"width,attr"`
+	Height          int            `xml:"height,attr"`
+	TileWidth       int            `xml:"tilewidth,attr"`
+	TileHeight      int            `xml:"tileheight,attr"`
+	Infinite        int            `xml:"infinite,attr"`
+	BackgroundColor string         `xml:"backgroundcolor,attr"`
+	ParallaxOriginX float64        `xml:"parallaxoriginx,attr"`
+	ParallaxOriginY float64        `xml:"parallaxoriginy,attr"`
+	Properties      *xmlProperties `xml:"properties"`
+	Tilesets        []xmlTileset   `xml:"tileset"`
+	Layers          []xmlLayer     `xml:",any"`
+}
+
+func (l *loader) parseTMX(data []byte) (*Map, error) {
+	var xm xmlMap
+	if err := xml.Unmarshal(data, &xm); err != nil {
+		return nil, err
+	}
+
+	m := &Map{
+		Orientation:     xm.Orientation,
+		RenderOrder:     xm.RenderOrder,
+		Width:           xm.Width,
+		Height:          xm.Height,
+		TileWidth:       xm.TileWidth,
+		TileHeight:      xm.TileHeight,
+		Infinite:        xm.Infinite == 1,
+		BackgroundColor: parseColor(xm.BackgroundColor),
+		ParallaxOrigin:  f64.Vec2{xm.ParallaxOriginX, xm.ParallaxOriginY},
+		Properties:      xm.Properties.convert(),
+	}
+
+	// Tilesets first, Cells need them
+	for _, xt := range xm.Tilesets {
+		var ts *Tileset
+		var err error
+		if xt.Source != "" {
+			ts, err = l.loadTileset(xt.Source, xt.FirstGID)
+		} else {
+			ts, err = l.convertTileset(&xt, l.dir)
+			if ts != nil {
+				ts.FirstGID = xt.FirstGID
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	layers, err := l.convertLayers(m, xm.Layers, nil)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+	return m, nil
+}
+
+// External .tsx tileset, dir is the directory of the .tsx (images are relative to it)
+func (l *loader) parseTSX(data []byte, dir string) (*Tileset, error) {
+	var xt xmlTileset
+	if err := xml.Unmarshal(data, &xt); err != nil {
+		return nil, err
+	}
+	return l.convertTileset(&xt, dir)
+}
+
+func (l *loader) convertTileset(xt *xmlTileset, dir string) (*Tileset, error) {
+	ts := &Tileset{
+		Name:       xt.Name,
+		TileWidth:  xt.TileWidth,
+		TileHeight: xt.TileHeight,
+		Spacing:    xt.Spacing,
+		Margin:     xt.Margin,
+		TileCount:  xt.TileCount,
+		Columns:    xt.Columns,
+		Tiles:      map[uint32]*TileInfo{},
+		Properties: xt.Properties.convert(),
+	}
+	if xt.TileOffset != nil {
+		ts.TileOffset = f64.Vec2{xt.TileOffset.X, xt.TileOffset.Y}
+	}
+
+	var err error
+	if xt.Image != nil {
+		ts.ImageSource = xt.Image.Source
+		if ts.Image, err = l.loadImage(dir, xt.Image.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, xtile := range xt.Tiles {
+		info := &TileInfo{
+			ID:         xtile.ID,
+			Type:       firstNonEmpty(xtile.Class, xtile.Type),
+			Properties: xtile.Properties.convert(),
+		}
+		for _, f := range xtile.Animation {
+			info.Animation = append(info.Animation, Frame{TileID: f.TileID, Duration: f.Duration})
+		}
+		if xtile.Image != nil {
+			info.ImageSource = xtile.Image.Source
+			if info.Image, err = l.loadImage(dir, xtile.Image.Source); err != nil {
+				return nil, err
+			}
+		}
+		if xtile.ObjectGroup != nil {
+			// Collision shapes can't be tile objects, so no Map is needed
+			for i := range xtile.ObjectGroup.Objects {
+				info.Objects = append(info.Objects, convertObject(nil, &xtile.ObjectGroup.Objects[i]))
+			}
+		}
+		ts.Tiles[info.ID] = info
+	}
+
+	finishTileset(ts)
+	return ts, nil
+}
+
+func (l *loader) convertLayers(m *Map, xls []xmlLayer, parent *Layer) ([]*Layer, error) {
+	var layers []*Layer
+	for i := range xls {
+		xl := &xls[i]
+		layer := &Layer{
+			ID:         xl.ID,
+			Name:       xl.Name,
+			Class:      xl.Class,
+			Visible:    xl.Visible != "0",
+			Opacity:    parseFloat(xl.Opacity, 1),
+			Offset:     f64.Vec2{xl.OffsetX, xl.OffsetY},
+			Parallax:   f64.Vec2{parseFloat(xl.ParallaxX, 1), parseFloat(xl.ParallaxY, 1)},
+			Tint:       parseColor(xl.TintColor),
+			Properties: xl.Properties.convert(),
+			Parent:     parent,
+		}
+
+		switch xl.XMLName.Local {
+		case "layer":
+			layer.Type = TILED_LAYER_TILES
+			chunks, err := xl.Data.chunks(xl.Width, xl.Height)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", xl.Name, err)
+			}
+			m.fillLayer(layer, chunks)
+		case "objectgroup":
+			layer.Type = TILED_LAYER_OBJECTS
+			for j := range xl.Objects {
+				layer.Objects = append(layer.Objects, convertObject(m, &xl.Objects[j]))
+			}
+		case "imagelayer":
+			layer.Type = TILED_LAYER_IMAGE
+			if xl.Image != nil {
+				layer.ImageSource = xl.Image.Source
+				img, err := l.loadImage(l.dir, xl.Image.Source)
+				if err != nil {
+					return nil, err
+				}
+				layer.Image = img
+			}
+		case "group":
+			layer.Type = TILED_LAYER_GROUP
+			children, err := l.convertLayers(m, xl.Layers, layer)
+			if err != nil {
+				return nil, err
+			}
+			layer.Layers = children
+		default:
+			continue // Not a layer (eg: editorsettings)
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// Raw GIDs of a tile layer, as a single chunk for finite maps
+func (d *xmlData) chunks(width, height int) ([]chunk, error) {
+	if d == nil {
+		return nil, nil
+	}
+	if len(d.Chunks) == 0 {
+		gids, err := d.decode(&d.xmlChunk)
+		if err != nil {
+			return nil, err
+		}
+		return []chunk{{0, 0, width, height, gids}}, nil
+	}
+
+	result := make([]chunk, 0, len(d.Chunks))
+	for i := range d.Chunks {
+		c := &d.Chunks[i]
+		gids, err := d.decode(c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, chunk{c.X, c.Y, c.Width, c.Height, gids})
+	}
+	return result, nil
+}
+
+func (d *xmlData) decode(c *xmlChunk) ([]uint32, error) {
+	switch d.Encoding {
+	case "csv":
+		return decodeCSV(c.Text)
+	case "base64":
+		return decodeBase64(c.Text, d.Compression)
+	case "":
+		gids := make([]uint32, len(c.Tiles))
+		for i, t := range c.Tiles {
+			gids[i] = t.GID
+		}
+		return gids, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", d.Encoding)
+}
+
+// m can be nil when tile objects aren't possible
+func convertObject(m *Map, xo *xmlObject) *Object {
+	o := &Object{
+		ID:         xo.ID,
+		Name:       xo.Name,
+		Type:       firstNonEmpty(xo.Class, xo.Type),
+		X:          xo.X,
+		Y:          xo.Y,
+		Width:      xo.Width,
+		Height:     xo.Height,
+		Rotation:   xo.Rotation,
+		Visible:    xo.Visible != "0",
+		Ellipse:    xo.Ellipse != nil,
+		Point:      xo.Point != nil,
+		Properties: xo.Properties.convert(),
+	}
+	if m != nil && xo.GID != 0 {
+		o.Cell = m.cell(xo.GID)
+	}
+	if xo.Polygon != nil {
+		o.Polygon = parsePoints(xo.Polygon.Points)
+	}
+	if xo.Polyline != nil {
+		o.Polyline = parsePoints(xo.Polyline.Points)
+	}
+	if xo.Text != nil {
+		o.Text = xo.Text.Text
+	}
+	return o
+}
+
+// "x1,y1 x2,y2 ..."
+func parsePoints(s string) []f64.Vec2 {
+	var points []f64.Vec2
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			continue
+		}
+		points = append(points, f64.Vec2{parseFloat(xy[0], 0), parseFloat(xy[1], 0)})
+	}
+	return points
+}
+
+func parseFloat(s string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,271 @@
+package tilemap
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	"golang.org/x/image/math/f64"
+)
+
+/*
+Source provides tiles to a Renderer (eg: tiled.LayerSource)
+GetTile returns the image of tile tx, ty (nil if empty), op.GeoM should only hold the tile's local transform (flips)
+Animated tiles aren't baked into chunks, they are drawn every frame on top
+*/
+type Source interface {
+	GetBounds() (x, y, width, height int) // In tiles
+	GetTileSize() (width, height int)
+	GetTile(tx, ty int, op *ebiten.DrawImageOptions) *ebiten.Image
+	IsAnimated(tx, ty int) bool
+}
+
+type chunkKey struct {
+	x, y int
+}
+
+type chunk struct {
+	image    *ebiten.Image
+	dirty    bool
+	animated []image.Point // Tiles drawn every frame
+	lastUsed uint64
+}
+
+/*
+Renderer pre-renders a Source into fixed size chunks (ChunkSize x ChunkSize tiles) and caches them
+Only chunks intersecting the Viewport's visible rect are drawn (and rendered if needed)
+Edited tiles should be Invalidated, only their chunk is re-rendered
+Only orthogonal grids are supported, tiles bigger than the grid are clipped at chunk edges
+*/
+type Renderer struct {
+	Source    Source
+	ChunkSize int
+	Offset    f64.Vec2 // World position of tile 0, 0 (eg: Layer offset)
+	MaxChunks int      // Chunks kept in memory, least recently drawn are evicted first (0 is unlimited)
+
+	chunks map[chunkKey]*chunk
+	free   []*ebiten.Image // Images of evicted chunks, reused
+	frame  uint64
+	op     ebiten.DrawImageOptions
+}
+
+func New(source Source, chunkSize int) *Renderer {
+	if chunkSize <= 0 {
+		chunkSize = 16
+	}
+	return &Renderer{
+		Source:    source,
+		ChunkSize: chunkSize,
+		chunks:    map[chunkKey]*chunk{},
+	}
+}
+
+func (r *Renderer) chunkOf(tx, ty int) chunkKey {
+	return chunkKey{floorDiv(tx, r.ChunkSize), floorDiv(ty, r.ChunkSize)}
+}
+
+// Marks the chunk containing tile tx, ty for re-rendering
+func (r *Renderer) Invalidate(tx, ty int) {
+	if c, ok := r.chunks[r.chunkOf(tx, ty)]; ok {
+		c.dirty = true
+	}
+}
+
+// Marks every chunk overlapping the tile area for re-rendering
+func (r *Renderer) InvalidateRect(tx, ty, width, height int) {
+	min, max := r.chunkOf(tx, ty), r.chunkOf(tx+width-1, ty+height-1)
+	for cy := min.y; cy <= max.y; cy++ {
+		for cx := min.x; cx <= max.x; cx++ {
+			if c, ok := r.chunks[chunkKey{cx, cy}]; ok {
+				c.dirty = true
+			}
+		}
+	}
+}
+
+// Re-renders everything (eg: after a tileset image changed)
+func (r *Renderer) InvalidateAll() {
+	for _, c := range r.chunks {
+		c.dirty = true
+	}
+}
+
+// Frees all cached chunk images
+func (r *Renderer) Clear() {
+	for _, c := range r.chunks {
+		c.image.Dispose()
+	}
+	for _, img := range r.free {
+		img.Dispose()
+	}
+	r.chunks = map[chunkKey]*chunk{}
+	r.free = nil
+}
+
+// Number of chunks currently cached
+func (r *Renderer) Len() int {
+	return len(r.chunks)
+}
+
+/*
+Draws chunks visible in the Screen's Viewport (all chunks if there is none)
+op (optional) is used for colors and blending of every chunk, its GeoM is ignored
+*/
+func (r *Renderer) Draw(screen scr.Screen, op *ebiten.DrawImageOptions) {
+	if r.Source == nil {
+		return
+	}
+	r.frame++
+	tw, th := r.Source.GetTileSize()
+	if tw <= 0 || th <= 0 {
+		return
+	}
+	bx, by, bw, bh := r.Source.GetBounds()
+	if bw <= 0 || bh <= 0 {
+		return
+	}
+
+	// Visible tiles, clamped to the Source
+	x1, y1, x2, y2 := bx, by, bx+bw-1, by+bh-1
+	if v := screen.GetViewport(); v != nil {
+		vx, vy, vw, vh := v.GetVisibleRect()
+		vx, vy = vx-r.Offset[0], vy-r.Offset[1]
+		x1 = maxInt(x1, int(math.Floor(vx/float64(tw))))
+		y1 = maxInt(y1, int(math.Floor(vy/float64(th))))
+		x2 = minInt(x2, int(math.Floor((vx+vw)/float64(tw))))
+		y2 = minInt(y2, int(math.Floor((vy+vh)/float64(th))))
+	}
+	if x1 > x2 || y1 > y2 {
+		return
+	}
+
+	min, max := r.chunkOf(x1, y1), r.chunkOf(x2, y2)
+	for cy := min.y; cy <= max.y; cy++ {
+		for cx := min.x; cx <= max.x; cx++ {
+			key := chunkKey{cx, cy}
+			c := r.getChunk(key, tw, th)
+			c.lastUsed = r.frame
+
+			r.resetOP(op)
+			r.op.GeoM.Translate(r.Offset[0]+float64(cx*r.ChunkSize*tw), r.Offset[1]+float64(cy*r.ChunkSize*th))
+			screen.DrawImage(c.image, &r.op)
+
+			r.drawAnimated(screen, c, tw, th, op)
+		}
+	}
+	r.evict()
+}
+
+// Returns a cached chunk, rendering it if it's new or dirty
+func (r *Renderer) getChunk(key chunkKey, tw, th int) *chunk {
+	c, ok := r.chunks[key]
+	if !ok {
+		c = &chunk{image: r.newChunkImage(tw, th), dirty: true}
+		r.chunks[key] = c
+	}
+	if c.dirty {
+		r.renderChunk(key, c, tw, th)
+	}
+	return c
+}
+
+func (r *Renderer) newChunkImage(tw, th int) *ebiten.Image {
+	w, h := r.ChunkSize*tw, r.ChunkSize*th
+	if n := len(r.free); n > 0 {
+		img := r.free[n-1]
+		r.free = r.free[:n-1]
+		if img.Bounds().Dx() == w && img.Bounds().Dy() == h {
+			return img
+		}
+		img.Dispose()
+	}
+	return ebiten.NewImage(w, h)
+}
+
+func (r *Renderer) renderChunk(key chunkKey, c *chunk, tw, th int) {
+	c.image.Clear()
+	c.animated = c.animated[:0]
+	c.dirty = false
+
+	op := &ebiten.DrawImageOptions{}
+	x0, y0 := key.x*r.ChunkSize, key.y*r.ChunkSize
+	for ty := y0; ty < y0+r.ChunkSize; ty++ {
+		for tx := x0; tx < x0+r.ChunkSize; tx++ {
+			if r.Source.IsAnimated(tx, ty) {
+				c.animated = append(c.animated, image.Pt(tx, ty))
+				continue
+			}
+			op.GeoM.Reset()
+			op.ColorScale.Reset()
+			img := r.Source.GetTile(tx, ty, op)
+			if img == nil {
+				continue
+			}
+			op.GeoM.Translate(float64((tx-x0)*tw), float64((ty-y0)*th))
+			c.image.DrawImage(img, op)
+		}
+	}
+}
+
+func (r *Renderer) drawAnimated(screen scr.Screen, c *chunk, tw, th int, op *ebiten.DrawImageOptions) {
+	for _, p := range c.animated {
+		r.resetOP(op)
+		img := r.Source.GetTile(p.X, p.Y, &r.op)
+		if img == nil {
+			continue
+		}
+		r.op.GeoM.Translate(r.Offset[0]+float64(p.X*tw), r.Offset[1]+float64(p.Y*th))
+		screen.DrawImage(img, &r.op)
+	}
+}
+
+// Colors, blending and filter of op (if any) with an identity GeoM
+func (r *Renderer) resetOP(op *ebiten.DrawImageOptions) {
+	r.op = ebiten.DrawImageOptions{}
+	if op != nil {
+		r.op.ColorScale = op.ColorScale
+		r.op.Blend = op.Blend
+		r.op.Filter = op.Filter
+	}
+}
+
+// Drops least recently drawn chunks (never the ones drawn this frame) above MaxChunks
+func (r *Renderer) evict() {
+	for r.MaxChunks > 0 && len(r.chunks) > r.MaxChunks {
+		var oldest chunkKey
+		var found *chunk
+		for key, c := range r.chunks {
+			if c.lastUsed != r.frame && (found == nil || c.lastUsed < found.lastUsed) {
+				oldest, found = key, c
+			}
+		}
+		if found == nil {
+			return
+		}
+		delete(r.chunks, oldest)
+		r.free = append(r.free, found.image)
+	}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
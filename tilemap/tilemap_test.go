@@ -0,0 +1,196 @@
+package tilemap
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	scr "github.com/shubhamdwivedii/gopher-engine/scene/screen"
+	vpt "github.com/shubhamdwivedii/gopher-engine/scene/viewport"
+)
+
+// 8x8 tiles, GetTile only counts calls (every tile is empty, so nothing is drawn into chunks)
+type fakeSource struct {
+	x, y, width, height int
+	animated            map[[2]int]bool
+	reads               map[[2]int]int
+}
+
+func newFakeSource(x, y, width, height int) *fakeSource {
+	return &fakeSource{x, y, width, height, map[[2]int]bool{}, map[[2]int]int{}}
+}
+
+func (s *fakeSource) GetBounds() (x, y, width, height int) {
+	return s.x, s.y, s.width, s.height
+}
+
+func (s *fakeSource) GetTileSize() (width, height int) {
+	return 8, 8
+}
+
+func (s *fakeSource) GetTile(tx, ty int, op *ebiten.DrawImageOptions) *ebiten.Image {
+	s.reads[[2]int{tx, ty}]++
+	return nil
+}
+
+func (s *fakeSource) IsAnimated(tx, ty int) bool {
+	return s.animated[[2]int{tx, ty}]
+}
+
+// Records chunks drawn, by their World position
+type fakeScreen struct {
+	scr.Screen
+	viewport *vpt.Viewport
+	drawn    [][2]float64
+}
+
+func (s *fakeScreen) GetViewport() *vpt.Viewport {
+	return s.viewport
+}
+
+func (s *fakeScreen) DrawImage(image *ebiten.Image, op *ebiten.DrawImageOptions) {
+	s.drawn = append(s.drawn, [2]float64{op.GeoM.Element(0, 2), op.GeoM.Element(1, 2)})
+}
+
+func keys(r *Renderer, match func(c *chunk) bool) []chunkKey {
+	var found []chunkKey
+	for key, c := range r.chunks {
+		if match(c) {
+			found = append(found, key)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].y != found[j].y {
+			return found[i].y < found[j].y
+		}
+		return found[i].x < found[j].x
+	})
+	return found
+}
+
+func TestFloorDiv(t *testing.T) {
+	tests := []struct {
+		a, b, want int
+	}{
+		{0, 16, 0}, {15, 16, 0}, {16, 16, 1},
+		{-1, 16, -1}, {-16, 16, -1}, {-17, 16, -2}, {-32, 16, -2},
+		{5, -2, -3}, {-5, -2, 2},
+	}
+	for _, tt := range tests {
+		if got := floorDiv(tt.a, tt.b); got != tt.want {
+			t.Errorf("floorDiv(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	r := New(nil, 4)
+	if got := r.chunkOf(-1, -4); got != (chunkKey{-1, -1}) {
+		t.Fatalf("chunkOf(-1, -4) = %v, want -1, -1", got)
+	}
+	if got := r.chunkOf(-5, 3); got != (chunkKey{-2, 0}) {
+		t.Fatalf("chunkOf(-5, 3) = %v, want -2, 0", got)
+	}
+}
+
+func TestInvalidateRect(t *testing.T) {
+	tests := []struct {
+		name         string
+		tx, ty, w, h int
+		want         []chunkKey
+	}{
+		{"single tile", -1, -1, 1, 1, []chunkKey{{-1, -1}}},
+		{"inside one chunk", 4, 4, 4, 4, []chunkKey{{1, 1}}},
+		{"across zero", -5, 3, 6, 2, []chunkKey{{-2, 0}, {-1, 0}, {0, 0}, {-2, 1}, {-1, 1}, {0, 1}}},
+		{"ends on a chunk edge", -8, -4, 8, 1, []chunkKey{{-2, -1}, {-1, -1}}},
+		{"past cached chunks", 6, 6, 100, 1, []chunkKey{{1, 1}, {2, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(nil, 4)
+			for cy := -2; cy <= 2; cy++ {
+				for cx := -2; cx <= 2; cx++ {
+					r.chunks[chunkKey{cx, cy}] = &chunk{}
+				}
+			}
+			r.InvalidateRect(tt.tx, tt.ty, tt.w, tt.h)
+			got := keys(r, func(c *chunk) bool { return c.dirty })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("dirty chunks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvictKeepsChunksDrawnThisFrame(t *testing.T) {
+	r := New(nil, 4)
+	r.MaxChunks = 2
+	r.frame = 5
+	for i, lastUsed := range []uint64{5, 2, 4, 5, 3, 5} {
+		r.chunks[chunkKey{i, 0}] = &chunk{lastUsed: lastUsed}
+	}
+
+	// Everything older goes, even if chunks drawn this frame are more than MaxChunks
+	r.evict()
+	want := []chunkKey{{0, 0}, {3, 0}, {5, 0}}
+	if got := keys(r, func(c *chunk) bool { return true }); !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunks %v after evict, want %v", got, want)
+	}
+	if len(r.free) != 3 {
+		t.Fatalf("%d images kept for reuse, want 3", len(r.free))
+	}
+
+	// Least recently drawn first
+	r.chunks = map[chunkKey]*chunk{}
+	for i, lastUsed := range []uint64{2, 5, 4, 3} {
+		r.chunks[chunkKey{i, 0}] = &chunk{lastUsed: lastUsed}
+	}
+	r.evict()
+	want = []chunkKey{{1, 0}, {2, 0}}
+	if got := keys(r, func(c *chunk) bool { return true }); !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunks %v after evict, want %v", got, want)
+	}
+}
+
+func TestDrawVisibleChunks(t *testing.T) {
+	source := newFakeSource(-16, -16, 48, 48)
+	source.animated[[2]int{-3, 2}] = true
+	r := New(source, 4) // 32x32 pixel chunks
+	r.MaxChunks = 6
+
+	// View of 64x48 pixels at -40, -8 sees tiles -5..3, -1..5 (its right and bottom edges too)
+	v := vpt.New(64, 48, 1000, 1000, 0, 0)
+	v.Position[0], v.Position[1] = -40, -8
+	screen := &fakeScreen{viewport: v}
+	r.Draw(screen, nil)
+
+	want := []chunkKey{{-2, -1}, {-1, -1}, {0, -1}, {-2, 0}, {-1, 0}, {0, 0}, {-2, 1}, {-1, 1}, {0, 1}}
+	if got := keys(r, func(c *chunk) bool { return c.lastUsed == r.frame }); !reflect.DeepEqual(got, want) {
+		t.Fatalf("drawn chunks %v, want %v", got, want)
+	}
+	if len(r.chunks) != 9 {
+		t.Fatalf("%d chunks cached, drawn ones can't be evicted", len(r.chunks))
+	}
+	if reads := source.reads[[2]int{0, 0}]; reads != 1 {
+		t.Fatalf("tile 0, 0 read %d times, want once", reads)
+	}
+	if source.reads[[2]int{-3, 2}] != 1 || len(screen.drawn) != 9 {
+		t.Fatalf("animated tile read %d times, %d draws", source.reads[[2]int{-3, 2}], len(screen.drawn))
+	}
+	if screen.drawn[0] != [2]float64{-64, -32} {
+		t.Fatalf("first chunk drawn at %v, want -64, -32", screen.drawn[0])
+	}
+
+	// Cached chunks aren't rendered again, invalidated ones are
+	r.Invalidate(-1, -1)
+	r.Draw(screen, nil)
+	if source.reads[[2]int{0, 0}] != 1 || source.reads[[2]int{-1, -1}] != 2 {
+		t.Fatal("only the invalidated chunk should be rendered again")
+	}
+
+	// Moving away evicts chunks that aren't visible anymore, the 9 visible ones stay above MaxChunks
+	v.Position[0] = 40
+	r.Draw(screen, nil)
+	if drawn := keys(r, func(c *chunk) bool { return c.lastUsed == r.frame }); len(drawn) != 9 || len(r.chunks) != 9 {
+		t.Fatalf("%d chunks cached, %d drawn, want only the 9 drawn ones", len(r.chunks), len(drawn))
+	}
+}
@@ -1,22 +1,17 @@
 package utils
 
 import (
+	goimage "image"
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/shubhamdwivedii/gopher-engine/text/richtext"
 	"golang.org/x/image/font"
-	"golang.org/x/image/math/f64"
 )
 
-type entity interface {
-	GetPosition() f64.Vec2 // TopLeft Coordinates in World
-	GetSize() f64.Vec2     // Size of the entity
-	GetScale() f64.Vec2
-	GetRotation() float64
-}
-
 func Fill(image *ebiten.Image, col color.Color) {
 	image.Fill(col)
 }
@@ -50,6 +45,102 @@ func DebugPrintAt(image *ebiten.Image, text string, x, y int) {
 	ebitenutil.DebugPrintAt(image, text, x, y)
 }
 
+// fnt can be a TTF/OTF face or a Bitmap Font (see text/bitmapfont), x, y is the baseline
 func DrawText(image *ebiten.Image, txt string, fnt font.Face, x, y int, clr color.Color) {
 	text.Draw(image, txt, fnt, x, y, clr)
 }
+
+// Insets (in pixels of the source image) of the borders that are not stretched
+type NineSlice struct {
+	Left, Top, Right, Bottom int
+	TileEdges                bool // Repeat edges instead of stretching them
+	TileCenter               bool // Repeat center instead of stretching it
+}
+
+// Three-Slice is a Nine-Slice that only stretches along one axis
+type ThreeSlice struct {
+	Start, End int  // Left/Right insets (Top/Bottom if Vertical)
+	Vertical   bool // Stretches along Y instead of X
+	Tile       bool // Repeat center instead of stretching it
+}
+
+/*
+Draws image stretched to width x height without distorting its corners
+x, y is TopLeft on targetImage, op.GeoM (if any) is applied after placing the slices
+*/
+func DrawNineSlice(image *ebiten.Image, targetImage *ebiten.Image, x, y, width, height float64, slice NineSlice, op *ebiten.DrawImageOptions) {
+	b := image.Bounds()
+	srcX := [4]int{b.Min.X, b.Min.X + slice.Left, b.Max.X - slice.Right, b.Max.X}
+	srcY := [4]int{b.Min.Y, b.Min.Y + slice.Top, b.Max.Y - slice.Bottom, b.Max.Y}
+	dstX := [4]float64{x, x + float64(slice.Left), x + width - float64(slice.Right), x + width}
+	dstY := [4]float64{y, y + float64(slice.Top), y + height - float64(slice.Bottom), y + height}
+
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			src := image.SubImage(goimage.Rect(srcX[i], srcY[j], srcX[i+1], srcY[j+1])).(*ebiten.Image)
+			tile := slice.TileEdges
+			if i == 1 && j == 1 {
+				tile = slice.TileCenter
+			}
+			drawSlice(src, targetImage, dstX[i], dstY[j], dstX[i+1]-dstX[i], dstY[j+1]-dstY[j], tile, op)
+		}
+	}
+}
+
+// Draws image stretched to given length, thickness is the size of the image along the other axis
+func DrawThreeSlice(image *ebiten.Image, targetImage *ebiten.Image, x, y, length float64, slice ThreeSlice, op *ebiten.DrawImageOptions) {
+	w, h := float64(image.Bounds().Dx()), float64(image.Bounds().Dy())
+	if slice.Vertical {
+		nine := NineSlice{Top: slice.Start, Bottom: slice.End, TileEdges: slice.Tile, TileCenter: slice.Tile}
+		DrawNineSlice(image, targetImage, x, y, w, length, nine, op)
+		return
+	}
+	nine := NineSlice{Left: slice.Start, Right: slice.End, TileEdges: slice.Tile, TileCenter: slice.Tile}
+	DrawNineSlice(image, targetImage, x, y, length, h, nine, op)
+}
+
+// Draws src into the dst rect, either stretched or repeated (last repetition is cropped)
+func drawSlice(src *ebiten.Image, targetImage *ebiten.Image, x, y, width, height float64, tile bool, op *ebiten.DrawImageOptions) {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw <= 0 || sh <= 0 || width <= 0 || height <= 0 {
+		return
+	}
+
+	sliceOP := &ebiten.DrawImageOptions{}
+	if op != nil {
+		*sliceOP = *op
+	}
+
+	if !tile {
+		sliceOP.GeoM.Reset()
+		sliceOP.GeoM.Scale(width/float64(sw), height/float64(sh))
+		sliceOP.GeoM.Translate(x, y)
+		if op != nil {
+			sliceOP.GeoM.Concat(op.GeoM)
+		}
+		targetImage.DrawImage(src, sliceOP)
+		return
+	}
+
+	min := src.Bounds().Min
+	for ty := 0.0; ty < height; ty += float64(sh) {
+		for tx := 0.0; tx < width; tx += float64(sw) {
+			cw, ch := int(math.Min(float64(sw), width-tx)), int(math.Min(float64(sh), height-ty))
+			if cw <= 0 || ch <= 0 {
+				continue
+			}
+			piece := src.SubImage(goimage.Rect(min.X, min.Y, min.X+cw, min.Y+ch)).(*ebiten.Image)
+			sliceOP.GeoM.Reset()
+			sliceOP.GeoM.Translate(x+tx, y+ty)
+			if op != nil {
+				sliceOP.GeoM.Concat(op.GeoM)
+			}
+			targetImage.DrawImage(piece, sliceOP)
+		}
+	}
+}
+
+// Draws txt (with markup) wrapped and aligned as per opts, x, y is TopLeft (not baseline)
+func DrawRichText(image *ebiten.Image, txt string, fnt font.Face, x, y int, opts *richtext.Options) {
+	richtext.Draw(image, txt, fnt, float64(x), float64(y), opts)
+}